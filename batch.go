@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dilipvaidya/game-of-life/gameoflife"
+)
+
+// Exit statuses of batch, besides 1 for errors and 2 for bad usage.
+const (
+	exitRunning     = 0
+	exitExtinct     = 10
+	exitStable      = 11
+	exitOscillating = 12
+)
+
+// batchExitStatus returns the exit status batch reports an outcome with.
+func batchExitStatus(outcome gameoflife.Outcome) int {
+	switch outcome {
+	case gameoflife.OutcomeExtinct:
+		return exitExtinct
+	case gameoflife.OutcomeStable:
+		return exitStable
+	case gameoflife.OutcomeOscillating:
+		return exitOscillating
+	default:
+		return exitRunning
+	}
+}
+
+// batchWriter writes generations of a universe in one format: a pattern format, a render
+// format, or json for one line of JSON per generation.
+type batchWriter struct {
+	rules   string
+	pattern gameoflife.PatternFormat
+	render  gameoflife.RenderFormat
+	json    bool
+	image   bool
+	scale   int
+}
+
+// newBatchWriter returns the writer for a format name, or for the extension of path when
+// the name is empty, RLE by default.
+func newBatchWriter(name, path, rules string, scale int) (*batchWriter, error) {
+	w := &batchWriter{rules: rules, pattern: gameoflife.FormatUnknown, scale: scale}
+	if name == "" {
+		switch ext := strings.ToLower(filepath.Ext(path)); {
+		case ext == ".json" || ext == ".jsonl":
+			name = "json"
+		case ext == ".png":
+			name = "png"
+		case ext == ".txt":
+			name = "text"
+		default:
+			w.pattern = gameoflife.DetectPatternFormat(path, nil)
+			if w.pattern == gameoflife.FormatUnknown {
+				w.pattern = gameoflife.FormatRLE
+			}
+			return w, nil
+		}
+	}
+	if name == "json" {
+		w.json = true
+		return w, nil
+	}
+	if format, err := gameoflife.ParsePatternFormat(name); err == nil {
+		w.pattern = format
+		return w, nil
+	}
+	format, err := gameoflife.ParseRenderFormat(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown format %q, want a pattern format (rle, cells, life105, life106, mc), a render format (text, ansi, png) or json", name)
+	}
+	w.render = format
+	w.image = format == gameoflife.RenderPNG
+	return w, nil
+}
+
+// write writes the current generation of the universe.
+func (bw *batchWriter) write(w io.Writer, game *gameoflife.GameOfLife) error {
+	switch {
+	case bw.json:
+		return json.NewEncoder(w).Encode(summarise(game, bw.rules, true))
+	case bw.pattern != gameoflife.FormatUnknown:
+		pattern := game.Pattern()
+		pattern.Name = "generation " + strconv.Itoa(game.Generation())
+		return gameoflife.WritePattern(w, pattern, bw.pattern)
+	default:
+		return game.Render(w, bw.render, bw.scale)
+	}
+}
+
+// batch runs a universe headless, as fast as it goes, and writes its last generation to a
+// file or the standard output. The exit status says what the universe settled into: 0 if
+// it was still changing, 10 if it died out, 11 if it became stable and 12 if it oscillates.
+func batch(args []string) {
+	fs := newFlagSet("batch")
+	universe := addUniverseFlags(fs, 64, 64)
+	universe.addStopFlag()
+	generations := fs.Int("gens", 100, "Number of generations to run")
+	maxPeriod := fs.Int("max-period", 64, "Longest period recognised as oscillating when reporting the outcome")
+	formatName := fs.String("format", "", "Output format: rle, cells, life105, life106, mc, text, ansi, png or json (default: from the -out extension, otherwise RLE)")
+	outPath := fs.String("out", "", "File to write the last generation to (default: standard output)")
+	scale := fs.Int("scale", 8, "Size in pixels of each cell in PNG images")
+	every := fs.Int("every", 0, "Also write every Nth generation, starting with the first")
+	at := fs.String("at", "", "Also write these comma-separated generations")
+	frames := fs.String("frames", "", "File name template with %d for the generation, e.g. gen-%04d.rle, to write the generations picked by -every and -at to; by default they go before the last one in the output")
+	reportPath := fs.String("report", "", "Write how the run ended as JSON to this file (- for the standard output)")
+	fs.Parse(args)
+
+	cfg := universe.mustResolve()
+	if universe.applies("gens") {
+		cfg.Generations = *generations
+	}
+	universe.dump(cfg)
+
+	selected := map[int]bool{}
+	for _, field := range strings.Split(*at, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		generation, err := strconv.Atoi(field)
+		if err != nil || generation < 0 {
+			fmt.Fprintf(os.Stderr, "-at: %q is not a generation\n", field)
+			os.Exit(2)
+		}
+		selected[generation] = true
+	}
+	picked := func(generation int) bool {
+		return selected[generation] || *every > 0 && generation%*every == 0
+	}
+	picks := len(selected) > 0 || *every > 0
+	if *frames != "" && !strings.Contains(*frames, "%") {
+		fmt.Fprintf(os.Stderr, "-frames needs a %%d for the generation\n")
+		os.Exit(2)
+	}
+
+	writer, err := newBatchWriter(*formatName, *outPath, cfg.Rules, *scale)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if writer.image && picks && *frames == "" {
+		fmt.Fprintln(os.Stderr, "PNG images cannot share a file; use -frames to write the picked generations")
+		os.Exit(2)
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		if out, err = os.Create(*outPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	game := build(cfg)
+	closeOutputs, err := openOutputs(game, cfg.Outputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	result, err := game.RunBatch(gameoflife.BatchOptions{
+		Generations: cfg.Generations,
+		MaxPeriod:   *maxPeriod,
+		Selected:    picked,
+		Snapshot: func(game *gameoflife.GameOfLife) error {
+			if *frames == "" {
+				return writer.write(out, game)
+			}
+			return writeFile(fmt.Sprintf(*frames, game.Generation()), func(w io.Writer) error { return writer.write(w, game) })
+		},
+	})
+	if err == nil {
+		err = writeOutputs(game, cfg.Outputs)
+	}
+	// the last generation was written already if it was picked to go to the same output
+	if err == nil && !(*frames == "" && picked(game.Generation())) {
+		err = writer.write(out, game)
+	}
+	if err == nil && *reportPath != "" {
+		if *reportPath == "-" {
+			printJSON(result)
+		} else {
+			err = writeFile(*reportPath, func(w io.Writer) error {
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			})
+		}
+	}
+	closeOutputs()
+	if out != os.Stdout {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	summary := fmt.Sprintf("Generation %d, population %d: %s", result.Generation, result.Population, result.Outcome)
+	if result.Outcome == gameoflife.OutcomeOscillating {
+		summary += fmt.Sprintf(" with period %d", result.Period)
+	}
+	if result.StopReason != "" {
+		summary += fmt.Sprintf(" (stopped: %s)", result.StopReason)
+	}
+	fmt.Fprintln(os.Stderr, summary)
+	os.Exit(batchExitStatus(result.Outcome))
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dilipvaidya/game-of-life/gameoflife"
+)
+
+// command is a subcommand of the tool, e.g. `run` or `render`.
+type command struct {
+	name string
+	// args describes the arguments after the flags, for the usage line
+	args    string
+	summary string
+	run     func(args []string)
+}
+
+// commands returns the subcommands in the order the help lists them.
+func commands() []command {
+	return []command{
+		{"run", "", "Animate a universe in the terminal (the default without a command)", run},
+		{"batch", "", "Run a universe headless and write its last generation, exiting with its outcome", batch},
+		{"render", "", "Draw one generation as text, terminal colours or a PNG image", render},
+		{"convert", "<input> [output]", "Convert a pattern between RLE, plaintext, Life 1.05/1.06 and macrocell", convert},
+		{"analyze", "", "Report the statistics, behaviour and objects of a generation", analyze},
+		{"search", "", "Run an apgsearch-style census of random soups", search},
+		{"serve", "", "Serve a universe over HTTP, with a page to watch it and a JSON API", serve},
+		{"bench", "", "Measure how many generations per second a universe runs at", bench},
+		{"resume", "<checkpoint>", "Continue a run saved with -checkpoint", resume},
+		{"predecessor", "<target>", "Find a generation that evolves into the target, or prove there is none", predecessor},
+		{"periodic", "", "Search a box for still lifes, oscillators and spaceships of a period", periodic},
+		{"methuselah", "", "Evolve small patterns towards long lifespans", methuselah},
+		{"explore-rules", "", "Classify Life-like rules by how soups behave under them", exploreRules},
+		{"life3d", "", "Run three-dimensional Life", life3d},
+	}
+}
+
+// usage prints the list of commands.
+func usage() {
+	fmt.Printf("Usage: %s <command> [flags] [arguments]\n\n", os.Args[0])
+	fmt.Println("Commands:")
+	for _, cmd := range commands() {
+		fmt.Printf("  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Printf("\nRun `%s <command> -h` for the flags of a command. Without a command, the flags are those of run.\n", os.Args[0])
+}
+
+// newFlagSet returns the flag set of a command, with a usage message naming its arguments.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		for _, cmd := range commands() {
+			if cmd.name == name {
+				line := strings.TrimSpace(fmt.Sprintf("%s %s [flags] %s", os.Args[0], name, cmd.args))
+				fmt.Printf("Usage: %s\n\n%s.\n\nFlags:\n", line, cmd.summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// universeOptions are the flags shared by every command that builds a two-state universe:
+// its size, seed, rules, topology and engine, and the config file they may come from instead.
+type universeOptions struct {
+	fs         *flag.FlagSet
+	rows       *int
+	cols       *int
+	seed       *string
+	rules      *string
+	topology   *string
+	engine     *string
+	tilePeriod *int
+	randomSeed *uint64
+	pattern    *string
+	apgcode    *string
+	config     *string
+	dumpConfig *string
+	stop       *string
+}
+
+// addUniverseFlags registers the universe flags with fs, with a default size.
+func addUniverseFlags(fs *flag.FlagSet, rows, cols int) *universeOptions {
+	return &universeOptions{
+		fs:         fs,
+		rows:       fs.Int("rows", rows, "Number of rows in the universe"),
+		cols:       fs.Int("cols", cols, "Number of columns in the universe"),
+		seed:       fs.String("seed", gameoflife.Default.String(), "Seed pattern for the universe (default, glider)"),
+		rules:      fs.String("rules", "conway", fmt.Sprintf("Comma-separated rule names or B/S rulestrings (e.g., conway,no-top-left or B36/S23). Available: %v", gameoflife.AvailableRuleNames())),
+		topology:   fs.String("topology", "torus", "How the universe's edges are joined: torus, plane (no wrapping), cylinder (left and right) or klein (Klein bottle)"),
+		engine:     fs.String("engine", "sparse", "How generations are computed: sparse (every live cell, every generation), active (only where the universe changed) or tiled (also copying regions that repeat)"),
+		tilePeriod: fs.Int("tile-period", 0, "Longest period of repeating regions the tiled engine copies instead of computing, up to 63 (default 2)"),
+		randomSeed: fs.Uint64("random-seed", 0, "Seed for the random number generator"),
+		pattern:    fs.String("pattern", "", "Pattern file to seed the universe with (.rle, .cells, .lif, .mc); overrides -seed"),
+		apgcode:    fs.String("apgcode", "", "Seed the universe with the object of this apgcode (e.g. xq4_153); overrides -seed"),
+		config:     fs.String("config", "", "Simulation config file (.json, .toml, .yaml); flags given as well override it"),
+		dumpConfig: fs.String("dump-config", "", "Write the effective config to this file (format from extension, - for JSON on standard output) and exit"),
+	}
+}
+
+// addStopFlag registers -stop, for commands that run a universe for a number of generations.
+func (o *universeOptions) addStopFlag() {
+	o.stop = o.fs.String("stop", "", "End the run early: comma-separated extinct, stable, period=N (oscillating with period up to N) and max-population=N")
+}
+
+// applies reports whether a flag decides its setting: every flag does without -config,
+// and only the flags given on the command line do with it.
+func (o *universeOptions) applies(name string) bool {
+	if *o.config == "" {
+		return true
+	}
+	given := false
+	o.fs.Visit(func(f *flag.Flag) { given = given || f.Name == name })
+	return given
+}
+
+// resolve returns the configuration the universe flags describe, starting from -config if
+// given. Commands then apply their own flags, such as the number of generations, with
+// applies.
+func (o *universeOptions) resolve() (*gameoflife.Config, error) {
+	cfg := gameoflife.DefaultConfig()
+	if *o.config != "" {
+		var err error
+		if cfg, err = gameoflife.LoadConfig(*o.config); err != nil {
+			return nil, err
+		}
+	}
+	if o.applies("rows") {
+		cfg.Rows = *o.rows
+	}
+	if o.applies("cols") {
+		cfg.Cols = *o.cols
+	}
+	if o.applies("seed") {
+		cfg.Seed = *o.seed
+	}
+	if o.applies("rules") {
+		cfg.Rules = *o.rules
+	}
+	if o.applies("topology") {
+		cfg.Topology = *o.topology
+	}
+	if o.applies("engine") {
+		cfg.Engine = *o.engine
+	}
+	if o.applies("tile-period") {
+		cfg.TilePeriod = *o.tilePeriod
+	}
+	if o.applies("random-seed") {
+		cfg.RandomSeed = *o.randomSeed
+	}
+	if o.stop != nil && o.applies("stop") {
+		stop, err := gameoflife.ParseStop(*o.stop)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Stop = stop
+	}
+	// -pattern and -apgcode replace the config's patterns with one in the middle
+	for _, reference := range []string{*o.apgcode, *o.pattern} {
+		if reference != "" {
+			cfg.Patterns = []gameoflife.PatternPlacement{{Pattern: reference, Centred: true}}
+		}
+	}
+	return cfg, cfg.Validate()
+}
+
+// mustResolve is resolve for commands, exiting on errors.
+func (o *universeOptions) mustResolve() *gameoflife.Config {
+	cfg, err := o.resolve()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return cfg
+}
+
+// dump writes the configuration to -dump-config and exits, if the flag is given, so that
+// any invocation can be saved and shared before it runs.
+func (o *universeOptions) dump(cfg *gameoflife.Config) {
+	if *o.dumpConfig == "" {
+		return
+	}
+	var err error
+	if *o.dumpConfig == "-" {
+		err = gameoflife.WriteConfig(os.Stdout, cfg, gameoflife.ConfigJSON)
+	} else {
+		err = writeFile(*o.dumpConfig, func(w io.Writer) error {
+			return gameoflife.WriteConfig(w, cfg, gameoflife.ConfigFormatFromPath(*o.dumpConfig))
+		})
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// build creates the universe a configuration describes, exiting on errors.
+func build(cfg *gameoflife.Config) *gameoflife.GameOfLife {
+	game, err := cfg.Build()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return game
+}
+
+// universeSummary describes a universe in the JSON output of commands.
+type universeSummary struct {
+	Rows       int               `json:"rows"`
+	Cols       int               `json:"cols"`
+	Topology   string            `json:"topology"`
+	Rules      string            `json:"rules"`
+	Generation int               `json:"generation"`
+	Population int               `json:"population"`
+	Cells      []gameoflife.Cell `json:"cells,omitempty"`
+}
+
+// summarise describes a universe, listing its live cells row-major if withCells is set.
+func summarise(game *gameoflife.GameOfLife, rules string, withCells bool) universeSummary {
+	rows, cols := game.Size()
+	pattern := game.Pattern()
+	summary := universeSummary{
+		Rows:       rows,
+		Cols:       cols,
+		Topology:   game.Topology().String(),
+		Rules:      rules,
+		Generation: game.Generation(),
+		Population: len(pattern.Cells),
+	}
+	if withCells {
+		summary.Cells = slices.SortedFunc(maps.Keys(pattern.Cells), func(a, b gameoflife.Cell) int {
+			if a.R != b.R {
+				return a.R - b.R
+			}
+			return a.C - b.C
+		})
+	}
+	return summary
+}
+
+// printJSON writes v to the standard output as indented JSON.
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
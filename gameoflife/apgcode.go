@@ -0,0 +1,198 @@
+package gameoflife
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ApgcodeMaxPeriod is the longest period Apgcode looks for before giving up on an object.
+const ApgcodeMaxPeriod = 256
+
+// wechslerDigits are the characters of the extended Wechsler format: each encodes
+// one 5-cell column of a strip, top cell in the least significant bit.
+const wechslerDigits = "0123456789abcdefghijklmnopqrstuv"
+
+// wechslerCode encodes cells, already shifted to the origin, in extended Wechsler format:
+// the pattern is cut into horizontal strips 5 rows high, separated by `z`; every column
+// of a strip is one character. Runs of empty columns are shortened: `w` is two, `x`
+// three and `y` followed by a character n is 4+n empty columns. Trailing empty columns
+// of a strip are dropped.
+func wechslerCode(cells []Cell) string {
+	height, width := 0, 0
+	for _, cell := range cells {
+		height, width = max(height, cell.R+1), max(width, cell.C+1)
+	}
+
+	columns := make([][]byte, (height+4)/5)
+	for i := range columns {
+		columns[i] = make([]byte, width)
+	}
+	for _, cell := range cells {
+		columns[cell.R/5][cell.C] |= 1 << (cell.R % 5)
+	}
+
+	var sb strings.Builder
+	for strip, values := range columns {
+		if strip > 0 {
+			sb.WriteByte('z')
+		}
+		end := len(values)
+		for end > 0 && values[end-1] == 0 {
+			end--
+		}
+		for i := 0; i < end; {
+			if values[i] != 0 {
+				sb.WriteByte(wechslerDigits[values[i]])
+				i++
+				continue
+			}
+			zeros := 0
+			for i+zeros < end && values[i+zeros] == 0 && zeros < 39 {
+				zeros++
+			}
+			switch {
+			case zeros == 1:
+				sb.WriteByte('0')
+			case zeros == 2:
+				sb.WriteByte('w')
+			case zeros == 3:
+				sb.WriteByte('x')
+			default:
+				sb.WriteByte('y')
+				sb.WriteByte("0123456789abcdefghijklmnopqrstuvwxyz"[zeros-4])
+			}
+			i += zeros
+		}
+	}
+	return sb.String()
+}
+
+// lessCode orders codes the way Catagolue does: shorter codes first, then lexicographically.
+func lessCode(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// canonicalWechsler returns the preferred extended Wechsler code of the cells over
+// all 8 rotations and reflections.
+func canonicalWechsler(cells map[Cell]struct{}) string {
+	best := ""
+	for i, symmetry := range symmetries {
+		code := wechslerCode(normalisedCells(transformCells(cells, symmetry)))
+		if i == 0 || lessCode(code, best) {
+			best = code
+		}
+	}
+	return best
+}
+
+// Apgcode returns the canonical apgcode of an isolated object, as used by apgsearch and
+// Catagolue: `xs4_33` for a block, `xp2_7` for a blinker, `xq4_153` for a glider.
+// The object is run on its own under the given rules (Conway's rule if none) to find
+// whether it is a still life, oscillator or spaceship, and the code is chosen over
+// all its phases, rotations and reflections, so every copy of an object gets the same code.
+// Objects that do not repeat within ApgcodeMaxPeriod generations get `zz_UNIDENTIFIED`.
+func Apgcode(cells map[Cell]struct{}, rules ...Rule) string {
+	if len(rules) == 0 {
+		rules = []Rule{ConwayRule{}}
+	}
+	return objectApgcode(cells, classifyAlone(cells, rules, ApgcodeMaxPeriod), rules)
+}
+
+// DecodeApgcode turns an apgcode back into a pattern, in the phase and orientation
+// the code describes. Both prefixed codes (`xs4_33`) and bare extended Wechsler
+// codes (`33`) are accepted. For still lifes the population in the prefix is checked.
+func DecodeApgcode(code string) (*Pattern, error) {
+	prefix, body, hasPrefix := strings.Cut(code, "_")
+	if !hasPrefix {
+		prefix, body = "", code
+	}
+
+	cells, err := decodeWechsler(body)
+	if err != nil {
+		return nil, fmt.Errorf("apgcode %q: %w", code, err)
+	}
+
+	if prefix != "" {
+		if len(prefix) < 3 || prefix[0] != 'x' || !strings.ContainsRune("spq", rune(prefix[1])) {
+			return nil, fmt.Errorf("apgcode %q: unsupported prefix %q", code, prefix)
+		}
+		number, err := strconv.Atoi(prefix[2:])
+		if err != nil || number <= 0 && prefix[1] != 's' {
+			return nil, fmt.Errorf("apgcode %q: invalid prefix %q", code, prefix)
+		}
+		if prefix[1] == 's' && number != len(cells) {
+			return nil, fmt.Errorf("apgcode %q: prefix says %d cells but the code has %d", code, number, len(cells))
+		}
+	}
+	return &Pattern{Name: code, Cells: cells}, nil
+}
+
+// decodeWechsler is the inverse of wechslerCode.
+func decodeWechsler(code string) (map[Cell]struct{}, error) {
+	cells := make(map[Cell]struct{})
+	strip, col := 0, 0
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case ch == 'w':
+			col += 2
+		case ch == 'x':
+			col += 3
+		case ch == 'y':
+			i++
+			if i == len(code) {
+				return nil, fmt.Errorf("`y` at the end of the code")
+			}
+			n := strings.IndexByte("0123456789abcdefghijklmnopqrstuvwxyz", code[i])
+			if n < 0 {
+				return nil, fmt.Errorf("invalid character %q after `y`", code[i])
+			}
+			col += 4 + n
+		case ch == 'z':
+			strip, col = strip+1, 0
+		default:
+			value := strings.IndexByte(wechslerDigits, ch)
+			if value < 0 {
+				return nil, fmt.Errorf("invalid character %q", ch)
+			}
+			for bit := range 5 {
+				if value&(1<<bit) != 0 {
+					cells[Cell{strip*5 + bit, col}] = struct{}{}
+				}
+			}
+			col++
+		}
+	}
+	return cells, nil
+}
+
+// objectApgcode returns the apgcode of an isolated object: `xs<population>_` for still
+// lifes, `xp<period>_` for oscillators and `xq<period>_` for spaceships, followed by the
+// preferred code over all phases, rotations and reflections.
+func objectApgcode(cells map[Cell]struct{}, behaviour Behaviour, rules []Rule) string {
+	switch behaviour.Kind {
+	case KindStillLife:
+		return fmt.Sprintf("xs%d_%s", len(cells), canonicalWechsler(cells))
+	case KindOscillator, KindSpaceship:
+		best := ""
+		for i, phase := range evolveAlone(cells, rules, behaviour.Period-1) {
+			code := canonicalWechsler(phase)
+			if i == 0 || lessCode(code, best) {
+				best = code
+			}
+		}
+		prefix := "xp"
+		if behaviour.Kind == KindSpaceship {
+			prefix = "xq"
+		}
+		return fmt.Sprintf("%s%d_%s", prefix, behaviour.Period, best)
+	case KindExtinct:
+		return "xs0_0"
+	default:
+		return "zz_UNIDENTIFIED"
+	}
+}
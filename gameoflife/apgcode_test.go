@@ -0,0 +1,106 @@
+package gameoflife
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestObjectApgcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		drawing string
+		want    string
+	}{
+		{"block", "OO/OO", "xs4_33"},
+		{"beehive", ".O./O.O/O.O/.O.", "xs6_696"},
+		{"pond", ".OO./O..O/O..O/.OO.", "xs8_6996"},
+		{"blinker", "OOO", "xp2_7"},
+		{"toad", ".OOO/OOO.", "xp2_7e"},
+		{"glider", ".O./..O/OOO", "xq4_153"},
+		{"lwss", ".O..O/O..../O...O/OOOO.", "xq4_6frc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := parseDrawing(tt.drawing)
+			behaviour := classifyAlone(cells, []Rule{ConwayRule{}}, 10)
+			got := objectApgcode(cells, behaviour, []Rule{ConwayRule{}})
+			if got != tt.want {
+				t.Errorf("objectApgcode(%s) = %q; want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWechslerCode_ZeroRunsAndStrips(t *testing.T) {
+	tests := []struct {
+		cells []Cell
+		want  string
+	}{
+		{[]Cell{{0, 0}, {0, 2}}, "101"},
+		{[]Cell{{0, 0}, {0, 3}}, "1w1"},
+		{[]Cell{{0, 0}, {0, 4}}, "1x1"},
+		{[]Cell{{0, 0}, {0, 5}}, "1y01"},
+		{[]Cell{{0, 0}, {0, 14}}, "1y91"},
+		{[]Cell{{0, 0}, {5, 0}}, "1z1"},
+		{[]Cell{{0, 0}, {10, 1}}, "1zz01"},
+	}
+
+	for _, tt := range tests {
+		if got := wechslerCode(tt.cells); got != tt.want {
+			t.Errorf("wechslerCode(%v) = %q; want %q", tt.cells, got, tt.want)
+		}
+	}
+}
+
+func TestApgcode_CanonicalAcrossPhasesAndSymmetries(t *testing.T) {
+	for _, entry := range Catalogue() {
+		t.Run(entry.Name, func(t *testing.T) {
+			want := Apgcode(entry.Cells)
+			for _, phase := range evolveAlone(entry.Cells, []Rule{ConwayRule{}}, entry.Behaviour.Period) {
+				for _, symmetry := range symmetries {
+					if got := Apgcode(transformCells(phase, symmetry)); got != want {
+						t.Fatalf("Apgcode() = %q for another phase or orientation; want %q", got, want)
+					}
+				}
+			}
+
+			decoded, err := DecodeApgcode(want)
+			if err != nil {
+				t.Fatalf("DecodeApgcode(%q) error = %v", want, err)
+			}
+			if got := Apgcode(decoded.Cells); got != want {
+				t.Errorf("Apgcode(DecodeApgcode(%q)) = %q", want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeApgcode(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    map[Cell]struct{}
+		wantErr bool
+	}{
+		{code: "xs4_33", want: parseDrawing("OO/OO")},
+		{code: "xq4_153", want: parseDrawing("OOO/..O/.O.")},
+		{code: "1y01", want: map[Cell]struct{}{{0, 0}: {}, {0, 5}: {}}},
+		{code: "xs5_33", wantErr: true},
+		{code: "xz4_33", wantErr: true},
+		{code: "xp0_7", wantErr: true},
+		{code: "xs1_!", wantErr: true},
+		{code: "xs1_1y", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := DecodeApgcode(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeApgcode(%q) error = %v; wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if err == nil && !maps.Equal(got.Cells, tt.want) {
+				t.Errorf("DecodeApgcode(%q) = %v; want %v", tt.code, got.Cells, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package gameoflife
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Automaton is what the run loop, checkpointing and the command line need of a cellular
+// automaton. GameOfLife is one; StateAutomaton runs WireWorld, Langton's Ant and other
+// automata whose cells have more than two states.
+type Automaton interface {
+	// Step advances the automaton by one generation.
+	Step()
+	Generation() int
+	// Display prints the current generation to the standard output.
+	Display()
+	Run(generations int, delay time.Duration)
+	EnableAutoCheckpoint(path string, every int, format CheckpointFormat)
+	SaveCheckpoint(path string, format CheckpointFormat) error
+	// Pattern returns the current generation as a pattern, for saving to a pattern file.
+	Pattern() *Pattern
+}
+
+// Step advances the universe by one generation, like CreateNextGeneration.
+func (g *GameOfLife) Step() {
+	g.CreateNextGeneration()
+}
+
+// autoCheckpoint is the auto-checkpointing configuration of an automaton; disabled when
+// checkpointEvery is zero.
+type autoCheckpoint struct {
+	checkpointPath   string
+	checkpointEvery  int
+	checkpointFormat CheckpointFormat
+}
+
+// EnableAutoCheckpoint makes Run write a checkpoint to path every `every` generations.
+// A non-positive `every` disables auto-checkpointing.
+func (c *autoCheckpoint) EnableAutoCheckpoint(path string, every int, format CheckpointFormat) {
+	c.checkpointPath = path
+	c.checkpointEvery = every
+	c.checkpointFormat = format
+}
+
+// afterStep writes a checkpoint if one is due after a step: every checkpointEvery
+// generations, and after the last step of a run.
+func (c *autoCheckpoint) afterStep(a Automaton, last bool) {
+	if c.checkpointEvery > 0 && (a.Generation()%c.checkpointEvery == 0 || last) {
+		if err := a.SaveCheckpoint(c.checkpointPath, c.checkpointFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "checkpoint failed: %v\n", err)
+		}
+	}
+}
+
+// stopper is implemented by automata that can end a run early, see GameOfLife.SetStop.
+type stopper interface {
+	StopReason() string
+}
+
+// runAutomaton is the run loop shared by all automata: it displays the current generation,
+// then steps, displays and checkpoints once per generation, pausing between generations.
+// Automata that are stoppers end the run as soon as they report a reason.
+func runAutomaton(a Automaton, checkpoint *autoCheckpoint, generations int, delay time.Duration) {
+	if a.Generation() == 0 {
+		fmt.Printf("Original Generation:\n")
+	} else {
+		fmt.Printf("Generation: %d\n", a.Generation())
+	}
+	a.Display()
+	if s, ok := a.(stopper); ok && s.StopReason() != "" {
+		fmt.Printf("Stopped: %s\n", s.StopReason())
+		return
+	}
+	for i := 1; i <= generations; i++ {
+		fmt.Print("\033[H\033[2J") // Clear screen before printing next frame
+		a.Step()
+		fmt.Printf("Generation: %d\n", a.Generation())
+		a.Display()
+		reason := ""
+		if s, ok := a.(stopper); ok {
+			reason = s.StopReason()
+		}
+		checkpoint.afterStep(a, i == generations || reason != "")
+		if reason != "" {
+			fmt.Printf("Stopped: %s\n", reason)
+			return
+		}
+		time.Sleep(delay)
+	}
+}
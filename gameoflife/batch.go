@@ -0,0 +1,93 @@
+package gameoflife
+
+import "fmt"
+
+// Outcome is what a universe was found to settle into by the end of a run.
+type Outcome int
+
+const (
+	// OutcomeRunning means the universe had not settled when the run ended.
+	OutcomeRunning Outcome = iota
+	// OutcomeExtinct means every cell died.
+	OutcomeExtinct
+	// OutcomeStable means the universe stopped changing.
+	OutcomeStable
+	// OutcomeOscillating means the universe repeats with a period above one.
+	OutcomeOscillating
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeExtinct:
+		return "extinct"
+	case OutcomeStable:
+		return "stable"
+	case OutcomeOscillating:
+		return "oscillating"
+	default:
+		return "running"
+	}
+}
+
+// MarshalText writes the outcome by name, e.g. in JSON.
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// BatchOptions configures RunBatch.
+type BatchOptions struct {
+	// Generations is how many generations to run, unless a stop condition set with SetStop
+	// ends the run first.
+	Generations int
+	// MaxPeriod is the longest period the universe is recognised as oscillating with.
+	MaxPeriod int
+	// Snapshot, if set, is called with the universe at the generations Selected picks,
+	// including the starting one.
+	Snapshot func(g *GameOfLife) error
+	// Selected picks the generations passed to Snapshot; nil picks none.
+	Selected func(generation int) bool
+}
+
+// BatchResult describes how a headless run ended.
+type BatchResult struct {
+	Generation int     `json:"generation"`
+	Population int     `json:"population"`
+	Outcome    Outcome `json:"outcome"`
+	// Period is the period of a stable (1) or oscillating universe.
+	Period int `json:"period,omitempty"`
+	// StopReason is the stop condition that ended the run early, if any.
+	StopReason string `json:"stop_reason,omitempty"`
+}
+
+// RunBatch runs the universe without displaying it or pausing, for scripts and tests, and
+// reports what it settled into. Like Run it writes auto-checkpoints and ends at a stop
+// condition; generations are checked against earlier ones up to MaxPeriod back whether or
+// not stop conditions are set.
+func (g *GameOfLife) RunBatch(opts BatchOptions) (BatchResult, error) {
+	watcher := &stopWatcher{stop: Stop{Extinct: true, Stable: true, Period: opts.MaxPeriod}}
+	watcher.restart(g)
+	snapshot := func() error {
+		if opts.Snapshot == nil || opts.Selected == nil || !opts.Selected(g.generation) {
+			return nil
+		}
+		if err := opts.Snapshot(g); err != nil {
+			return fmt.Errorf("snapshot of generation %d: %w", g.generation, err)
+		}
+		return nil
+	}
+
+	err := snapshot()
+	for i := 1; i <= opts.Generations && err == nil && g.StopReason() == ""; i++ {
+		g.CreateNextGeneration()
+		watcher.observe(g)
+		g.autoCheckpoint.afterStep(g, i == opts.Generations || g.StopReason() != "")
+		err = snapshot()
+	}
+	return BatchResult{
+		Generation: g.generation,
+		Population: len(g.universe),
+		Outcome:    watcher.outcome,
+		Period:     watcher.period,
+		StopReason: g.StopReason(),
+	}, err
+}
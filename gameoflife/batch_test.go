@@ -0,0 +1,71 @@
+package gameoflife
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestRunBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		universe map[Cell]struct{}
+		stop     Stop
+		want     BatchResult
+	}{
+		{"lone cell", map[Cell]struct{}{{2, 2}: {}}, Stop{},
+			BatchResult{Generation: 10, Outcome: OutcomeExtinct}},
+		{"block", map[Cell]struct{}{{1, 1}: {}, {1, 2}: {}, {2, 1}: {}, {2, 2}: {}}, Stop{},
+			BatchResult{Generation: 10, Population: 4, Outcome: OutcomeStable, Period: 1}},
+		{"blinker", map[Cell]struct{}{{3, 2}: {}, {3, 3}: {}, {3, 4}: {}}, Stop{},
+			BatchResult{Generation: 10, Population: 3, Outcome: OutcomeOscillating, Period: 2}},
+		{"blinker stopped early", map[Cell]struct{}{{3, 2}: {}, {3, 3}: {}, {3, 4}: {}}, Stop{Period: 2},
+			BatchResult{Generation: 2, Population: 3, Outcome: OutcomeOscillating, Period: 2, StopReason: "oscillating with period 2"}},
+		// a glider on a 16x16 torus comes back after 64 generations, so it has not settled by 10
+		{"glider", map[Cell]struct{}{{0, 1}: {}, {1, 2}: {}, {2, 0}: {}, {2, 1}: {}, {2, 2}: {}}, Stop{},
+			BatchResult{Generation: 10, Population: 5, Outcome: OutcomeRunning}},
+	}
+	for _, tt := range tests {
+		g := CreateSeedUniverse(16, 16, Default, ConwayRule{})
+		g.universe = tt.universe
+		g.SetStop(tt.stop)
+		var snapshots []int
+		got, err := g.RunBatch(BatchOptions{
+			Generations: 10,
+			MaxPeriod:   8,
+			Snapshot:    func(g *GameOfLife) error { snapshots = append(snapshots, g.Generation()); return nil },
+			Selected:    func(generation int) bool { return generation%4 == 0 },
+		})
+		if err != nil || got != tt.want {
+			t.Errorf("%s: RunBatch() = %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+		if want := []int{0, 4, 8}[:min(3, tt.want.Generation/4+1)]; !reflect.DeepEqual(snapshots, want) {
+			t.Errorf("%s: snapshots of generations %v; want %v", tt.name, snapshots, want)
+		}
+	}
+}
+
+func TestRunBatch_EnginesAgree(t *testing.T) {
+	// a soup still changing after 40 generations, which the tiled engine steps in place
+	run := func(engine Engine) (BatchResult, map[Cell]struct{}) {
+		g := CreateSeedUniverse(48, 48, Default, ConwayRule{})
+		g.SetRandomSeed(11)
+		g.FillRandom(0.35)
+		g.SetEngine(engine)
+		got, err := g.RunBatch(BatchOptions{Generations: 40, MaxPeriod: 8})
+		if err != nil {
+			t.Fatalf("RunBatch() with %v error = %v", engine, err)
+		}
+		return got, g.universe
+	}
+	want, wantUniverse := run(EngineSparse)
+	if want.Outcome != OutcomeRunning {
+		t.Fatalf("RunBatch() with EngineSparse = %+v; want a soup still running", want)
+	}
+	for _, engine := range []Engine{EngineActive, EngineTiled} {
+		got, universe := run(engine)
+		if got != want || !maps.Equal(universe, wantUniverse) {
+			t.Errorf("RunBatch() with %v = %+v; want %+v as with EngineSparse, %s", engine, got, want, cellDiff(wantUniverse, universe))
+		}
+	}
+}
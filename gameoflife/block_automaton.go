@@ -0,0 +1,240 @@
+package gameoflife
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlockRule is the rule of a Margolus block cellular automaton: the universe is cut into
+// 2x2 blocks and every block is replaced by Table[block]. A block is numbered by its live
+// cells, 1 for the top left, 2 top right, 4 bottom left and 8 bottom right, as in Golly.
+type BlockRule struct {
+	Table [16]int
+}
+
+// namedBlockRules are well-known reversible block rules by name.
+var namedBlockRules = map[string]string{
+	// Critters: blocks with two live cells stay, others are complemented, and those with
+	// three live cells are also turned around
+	"critters": "15;14;13;3;11;5;6;1;7;9;10;2;12;4;8;0",
+	// Fredkin and Toffoli's billiard-ball model: single particles move diagonally and
+	// two colliding head-on are deflected
+	"bbm": "0;8;4;3;2;5;9;7;1;6;10;11;12;13;14;15",
+	// Tron: uniform blocks are complemented, all others stay
+	"tron": "15;1;2;3;4;5;6;7;8;9;10;11;12;13;14;0",
+}
+
+// ParseBlockRule parses a block rule: one of critters, bbm or tron, or 16 numbers from 0
+// to 15 separated by semicolons or commas, optionally in Golly's notation `MS,D0;8;4;...`.
+func ParseBlockRule(s string) (BlockRule, error) {
+	var r BlockRule
+	s = strings.TrimSpace(s)
+	if table, ok := namedBlockRules[strings.ToLower(s)]; ok {
+		s = table
+	}
+	table := s
+	if rest, ok := strings.CutPrefix(strings.ToUpper(s), "MS,D"); ok {
+		table = rest
+	}
+	fields := strings.FieldsFunc(table, func(r rune) bool { return r == ';' || r == ',' })
+	if len(fields) != 16 {
+		return r, fmt.Errorf("block rule %q: want 16 entries such as MS,D0;8;4;3;2;5;9;7;1;6;10;11;12;13;14;15, or one of %v", s, slices.Sorted(maps.Keys(namedBlockRules)))
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 0 || n > 15 {
+			return r, fmt.Errorf("block rule %q: entry %d is %q, want a number from 0 to 15", s, i, field)
+		}
+		r.Table[i] = n
+	}
+	return r, nil
+}
+
+// String returns the rule in Golly's notation.
+func (r BlockRule) String() string {
+	entries := make([]string, len(r.Table))
+	for i, n := range r.Table {
+		entries[i] = strconv.Itoa(n)
+	}
+	return "MS,D" + strings.Join(entries, ";")
+}
+
+// Inverse returns the rule that undoes this one. Only reversible rules, whose table is a
+// permutation, have one.
+func (r BlockRule) Inverse() (BlockRule, bool) {
+	var inverse BlockRule
+	seen := [16]bool{}
+	for block, next := range r.Table {
+		if seen[next] {
+			return inverse, false
+		}
+		seen[next] = true
+		inverse.Table[next] = block
+	}
+	return inverse, true
+}
+
+// BlockAutomaton is a Margolus block cellular automaton on a torus. Even generations are
+// cut into blocks starting at row and column 0, odd generations into blocks shifted by one
+// cell down and right, so information crosses block borders.
+type BlockAutomaton struct {
+	rule       BlockRule
+	cells      map[Cell]struct{}
+	numRows    int
+	numCols    int
+	generation int
+
+	// auto-checkpointing during Run
+	autoCheckpoint
+}
+
+// NewBlockAutomaton returns a rows x cols block automaton with the given live cells.
+// Both sides must be even so the blocks tile the torus.
+func NewBlockAutomaton(rows, cols int, rule BlockRule, cells map[Cell]struct{}) (*BlockAutomaton, error) {
+	if rows <= 0 || cols <= 0 || rows%2 != 0 || cols%2 != 0 {
+		return nil, fmt.Errorf("block automaton %dx%d: both sides must be positive and even", rows, cols)
+	}
+	a := &BlockAutomaton{rule: rule, cells: make(map[Cell]struct{}, len(cells)), numRows: rows, numCols: cols}
+	for cell := range cells {
+		a.cells[Cell{(cell.R%rows + rows) % rows, (cell.C%cols + cols) % cols}] = struct{}{}
+	}
+	return a, nil
+}
+
+// Rule returns the automaton's rule.
+func (a *BlockAutomaton) Rule() BlockRule {
+	return a.rule
+}
+
+// Generation returns the number of generations computed since the seed.
+func (a *BlockAutomaton) Generation() int {
+	return a.generation
+}
+
+// Cells returns the live cells.
+func (a *BlockAutomaton) Cells() map[Cell]struct{} {
+	return maps.Clone(a.cells)
+}
+
+// Step advances the automaton by one generation.
+func (a *BlockAutomaton) Step() {
+	a.apply(a.rule, a.generation%2)
+	a.generation++
+}
+
+// StepBack undoes the last generation. It needs a reversible rule.
+func (a *BlockAutomaton) StepBack() error {
+	inverse, ok := a.rule.Inverse()
+	if !ok {
+		return fmt.Errorf("block rule %s is not reversible", a.rule)
+	}
+	if a.generation == 0 {
+		return fmt.Errorf("already at generation 0")
+	}
+	a.generation--
+	a.apply(inverse, a.generation%2)
+	return nil
+}
+
+// apply replaces every block of the partition with the given offset using the rule.
+func (a *BlockAutomaton) apply(rule BlockRule, offset int) {
+	next := make(map[Cell]struct{}, len(a.cells))
+	for r := offset; r < a.numRows+offset; r += 2 {
+		for c := offset; c < a.numCols+offset; c += 2 {
+			block := [4]Cell{
+				{r % a.numRows, c % a.numCols}, {r % a.numRows, (c + 1) % a.numCols},
+				{(r + 1) % a.numRows, c % a.numCols}, {(r + 1) % a.numRows, (c + 1) % a.numCols},
+			}
+			state := 0
+			for bit, cell := range block {
+				if _, alive := a.cells[cell]; alive {
+					state |= 1 << bit
+				}
+			}
+			state = rule.Table[state]
+			for bit, cell := range block {
+				if state&(1<<bit) != 0 {
+					next[cell] = struct{}{}
+				}
+			}
+		}
+	}
+	a.cells = next
+}
+
+// Display prints the automaton like GameOfLife.Display.
+func (a *BlockAutomaton) Display() {
+	fmt.Println("==============")
+	for r := range a.numRows {
+		for c := range a.numCols {
+			if _, alive := a.cells[Cell{r, c}]; alive {
+				fmt.Print(" ", whiteChar)
+			} else {
+				fmt.Print(" ", blackChar)
+			}
+		}
+		fmt.Print("\n\n")
+	}
+}
+
+// Run simulates the automaton for a number of generations like GameOfLife.Run.
+func (a *BlockAutomaton) Run(generations int, delay time.Duration) {
+	runAutomaton(a, &a.autoCheckpoint, generations, delay)
+}
+
+// Pattern returns the current generation as a pattern in universe coordinates.
+func (a *BlockAutomaton) Pattern() *Pattern {
+	return &Pattern{Rule: a.rule.String(), Cells: a.Cells()}
+}
+
+// Checkpoint captures the current state of the automaton. The generation is part of it,
+// since it selects the partition of the next step.
+func (a *BlockAutomaton) Checkpoint() (*Checkpoint, error) {
+	return &Checkpoint{
+		Version:    CheckpointVersion,
+		Rows:       a.numRows,
+		Cols:       a.numCols,
+		Generation: a.generation,
+		Rules:      []string{a.rule.String()},
+		Cells:      slices.SortedFunc(maps.Keys(a.cells), compareCells),
+	}, nil
+}
+
+// restoreBlockAutomaton rebuilds a BlockAutomaton from a checkpoint of one.
+func (cp *Checkpoint) restoreBlockAutomaton(rule BlockRule) (*BlockAutomaton, error) {
+	if cp.Version <= 0 || cp.Version > CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d", cp.Version)
+	}
+	cells := make(map[Cell]struct{}, len(cp.Cells))
+	for _, cell := range cp.Cells {
+		if cell.R < 0 || cell.R >= cp.Rows || cell.C < 0 || cell.C >= cp.Cols {
+			return nil, fmt.Errorf("cell %v lies outside the %dx%d universe", cell, cp.Rows, cp.Cols)
+		}
+		cells[cell] = struct{}{}
+	}
+	a, err := NewBlockAutomaton(cp.Rows, cp.Cols, rule, cells)
+	if err != nil {
+		return nil, err
+	}
+	a.generation = cp.Generation
+	return a, nil
+}
+
+// WriteCheckpoint writes the current state of the automaton to w in the given format.
+func (a *BlockAutomaton) WriteCheckpoint(w io.Writer, format CheckpointFormat) error {
+	cp, err := a.Checkpoint()
+	if err != nil {
+		return err
+	}
+	return cp.write(w, format)
+}
+
+// SaveCheckpoint atomically writes a checkpoint to path, like GameOfLife.SaveCheckpoint.
+func (a *BlockAutomaton) SaveCheckpoint(path string, format CheckpointFormat) error {
+	return writeFileAtomic(path, func(w io.Writer) error { return a.WriteCheckpoint(w, format) })
+}
@@ -0,0 +1,119 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+)
+
+func TestParseBlockRule(t *testing.T) {
+	named, err := ParseBlockRule("BBM")
+	if err != nil {
+		t.Fatalf("ParseBlockRule(BBM) error = %v", err)
+	}
+	golly, err := ParseBlockRule("MS,D0;8;4;3;2;5;9;7;1;6;10;11;12;13;14;15")
+	if err != nil {
+		t.Fatalf("ParseBlockRule(MS,D...) error = %v", err)
+	}
+	if named != golly || named.String() != "MS,D0;8;4;3;2;5;9;7;1;6;10;11;12;13;14;15" {
+		t.Errorf("ParseBlockRule(BBM) = %s; want the billiard-ball model", named)
+	}
+
+	for _, input := range []string{"life", "0;1;2", "0;1;2;3;4;5;6;7;8;9;10;11;12;13;14;16"} {
+		if _, err := ParseBlockRule(input); err == nil {
+			t.Errorf("ParseBlockRule(%q) succeeded; want an error", input)
+		}
+	}
+}
+
+func TestBlockAutomaton_BilliardBallParticle(t *testing.T) {
+	bbm, _ := ParseBlockRule("bbm")
+	a, err := NewBlockAutomaton(8, 8, bbm, map[Cell]struct{}{{0, 0}: {}})
+	if err != nil {
+		t.Fatalf("NewBlockAutomaton() error = %v", err)
+	}
+	for generation := 1; generation <= 10; generation++ {
+		a.Step()
+		want := map[Cell]struct{}{{generation % 8, generation % 8}: {}}
+		if !maps.Equal(a.Cells(), want) {
+			t.Fatalf("generation %d: particle at %v; want %v", generation, a.Cells(), want)
+		}
+	}
+
+	if _, err := NewBlockAutomaton(7, 8, bbm, nil); err == nil {
+		t.Errorf("NewBlockAutomaton() with an odd side succeeded; want an error")
+	}
+}
+
+func TestBlockAutomaton_StepBackReversesCritters(t *testing.T) {
+	critters, _ := ParseBlockRule("critters")
+	a, _ := NewBlockAutomaton(32, 32, critters, GenerateSoup("critters"))
+	start := a.Cells()
+
+	history := []map[Cell]struct{}{start}
+	for range 50 {
+		a.Step()
+		history = append(history, a.Cells())
+	}
+	for generation := 49; generation >= 0; generation-- {
+		if err := a.StepBack(); err != nil {
+			t.Fatalf("StepBack() error = %v", err)
+		}
+		if a.Generation() != generation || !maps.Equal(a.Cells(), history[generation]) {
+			t.Fatalf("StepBack() to generation %d differs from the way forward", generation)
+		}
+	}
+	if err := a.StepBack(); err == nil {
+		t.Errorf("StepBack() at generation 0 succeeded; want an error")
+	}
+
+	var irreversible BlockRule // every block becomes empty
+	b, _ := NewBlockAutomaton(4, 4, irreversible, nil)
+	b.Step()
+	if err := b.StepBack(); err == nil {
+		t.Errorf("StepBack() under an irreversible rule succeeded; want an error")
+	}
+}
+
+func TestBlockAutomaton_CheckpointAndPattern(t *testing.T) {
+	critters, _ := ParseBlockRule("critters")
+	a, _ := NewBlockAutomaton(16, 16, critters, GenerateSoup("checkpoint"))
+	for range 7 {
+		a.Step()
+	}
+
+	var buf bytes.Buffer
+	if err := a.WriteCheckpoint(&buf, CheckpointBinary); err != nil {
+		t.Fatalf("WriteCheckpoint() error = %v", err)
+	}
+	cp, err := ReadCheckpoint(&buf)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	restored, err := cp.RestoreAutomaton()
+	if err != nil {
+		t.Fatalf("RestoreAutomaton() error = %v", err)
+	}
+	b, ok := restored.(*BlockAutomaton)
+	if !ok {
+		t.Fatalf("RestoreAutomaton() = %T; want *BlockAutomaton", restored)
+	}
+	// continuing must use the odd partition, like the original
+	a.Step()
+	b.Step()
+	if !maps.Equal(a.Cells(), b.Cells()) {
+		t.Errorf("restored automaton diverged after a step")
+	}
+
+	var rle bytes.Buffer
+	if err := WritePattern(&rle, a.Pattern(), FormatRLE); err != nil {
+		t.Fatalf("WritePattern() error = %v", err)
+	}
+	p, err := ReadPattern(&rle, FormatRLE)
+	if err != nil {
+		t.Fatalf("ReadPattern() error = %v", err)
+	}
+	if p.Rule != critters.String() {
+		t.Errorf("pattern rule = %q; want %q", p.Rule, critters.String())
+	}
+}
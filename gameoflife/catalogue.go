@@ -0,0 +1,107 @@
+package gameoflife
+
+import (
+	"strings"
+	"sync"
+)
+
+// CatalogueEntry is a well-known Conway's Life object.
+type CatalogueEntry struct {
+	Name      string
+	Behaviour Behaviour
+	// Cells is the object in one of its phases, with its bounding box at the origin.
+	Cells map[Cell]struct{}
+}
+
+// catalogueSource lists the known objects as plaintext drawings, rows separated by `/`.
+var catalogueSource = []struct {
+	name    string
+	drawing string
+}{
+	// still lifes
+	{"block", "OO/OO"},
+	{"beehive", ".OO./O..O/.OO."},
+	{"loaf", ".OO./O..O/.O.O/..O."},
+	{"boat", "OO./O.O/.O."},
+	{"ship", "OO./O.O/.OO"},
+	{"tub", ".O./O.O/.O."},
+	{"pond", ".OO./O..O/O..O/.OO."},
+	{"long boat", "OO../O.O./.O.O/..O."},
+	{"barge", ".O../O.O./.O.O/..O."},
+	{"mango", ".OO../O..O./.O..O/..OO."},
+	{"eater 1", "OO../O.O./..O./..OO"},
+	{"snake", "OO.O/O.OO"},
+	{"aircraft carrier", "OO../O..O/..OO"},
+	{"long ship", "OO./O.O/.O.O/..OO"},
+	{"integral sign", "OO./O.O/..O/..O.O/...OO"},
+	// oscillators
+	{"blinker", "OOO"},
+	{"toad", ".OOO/OOO."},
+	{"beacon", "OO../OO../..OO/..OO"},
+	{"clock", "..O./O.O./.O.O/.O.."},
+	{"pulsar", "..OOO...OOO../............./O....O.O....O/O....O.O....O/O....O.O....O/..OOO...OOO../............./..OOO...OOO../O....O.O....O/O....O.O....O/O....O.O....O/............./..OOO...OOO.."},
+	{"pentadecathlon", "..O....O../OO.OOOO.OO/..O....O.."},
+	// spaceships
+	{"glider", ".O./..O/OOO"},
+	{"lightweight spaceship", ".O..O/O..../O...O/OOOO."},
+	{"middleweight spaceship", "...O../.O...O/O...../O....O/OOOOO."},
+	{"heavyweight spaceship", "...OO../.O....O/O....../O.....O/OOOOOO."},
+}
+
+// catalogueMaxPeriod is the longest period looked for when classifying catalogue entries.
+const catalogueMaxPeriod = 30
+
+var (
+	catalogueOnce    sync.Once
+	catalogueEntries []CatalogueEntry
+	// catalogueIndex maps the canonical shape of every phase of every entry to the entry
+	catalogueIndex map[string]int
+)
+
+// Catalogue returns the built-in catalogue of well-known objects under Conway's rule.
+func Catalogue() []CatalogueEntry {
+	loadCatalogue()
+	return catalogueEntries
+}
+
+// LookupCatalogue identifies the object with the given cells, in any phase,
+// rotation or reflection. It returns false for objects not in the catalogue.
+func LookupCatalogue(cells map[Cell]struct{}) (CatalogueEntry, bool) {
+	loadCatalogue()
+	index, ok := catalogueIndex[canonicalShapeKey(cells)]
+	if !ok {
+		return CatalogueEntry{}, false
+	}
+	return catalogueEntries[index], true
+}
+
+func loadCatalogue() {
+	catalogueOnce.Do(func() {
+		conway := []Rule{ConwayRule{}}
+		catalogueIndex = make(map[string]int)
+		for i, source := range catalogueSource {
+			cells := parseDrawing(source.drawing)
+			behaviour := classifyAlone(cells, conway, catalogueMaxPeriod)
+			catalogueEntries = append(catalogueEntries, CatalogueEntry{Name: source.name, Behaviour: behaviour, Cells: cells})
+
+			for _, phase := range evolveAlone(cells, conway, max(behaviour.Period, 1)) {
+				if _, exists := catalogueIndex[canonicalShapeKey(phase)]; !exists {
+					catalogueIndex[canonicalShapeKey(phase)] = i
+				}
+			}
+		}
+	})
+}
+
+// parseDrawing turns a compact plaintext drawing, rows separated by `/`, into cells.
+func parseDrawing(drawing string) map[Cell]struct{} {
+	cells := make(map[Cell]struct{})
+	for r, row := range strings.Split(drawing, "/") {
+		for c, ch := range row {
+			if ch == 'O' {
+				cells[Cell{r, c}] = struct{}{}
+			}
+		}
+	}
+	return cells
+}
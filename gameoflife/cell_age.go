@@ -0,0 +1,178 @@
+package gameoflife
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"maps"
+	"math"
+)
+
+// cellAges is the per-cell bookkeeping enabled by EnableAgeTracking.
+type cellAges struct {
+	since      int          // generation tracking started or restarted at
+	age        map[Cell]int // live cell -> generations it has been alive for
+	lastChange map[Cell]int // cell -> generation it was last born or died in
+	exposure   map[Cell]int // cell -> number of tracked generations it was alive in
+	exposed    int          // number of tracked generations
+}
+
+// EnableAgeTracking makes CreateNextGeneration keep track of how long every cell has
+// been alive and when it last changed, and how often each cell was alive overall for
+// WriteHeatMap. Cells alive when tracking starts have age 0. Rules can read the ages
+// with Age, e.g. AgeLimitRule.
+func (g *GameOfLife) EnableAgeTracking() {
+	g.ages = &cellAges{lastChange: make(map[Cell]int), exposure: make(map[Cell]int)}
+	g.ages.restart(g)
+}
+
+// restart resets the ages to 0, for when the universe was replaced rather than evolved,
+// e.g. by going back in time. The exposure is kept.
+func (a *cellAges) restart(g *GameOfLife) {
+	a.since = g.generation
+	a.age = make(map[Cell]int, len(g.universe))
+	for cell := range g.universe {
+		a.age[cell] = 0
+	}
+	clear(a.lastChange)
+	a.expose(g.universe)
+}
+
+func (a *cellAges) expose(universe map[Cell]struct{}) {
+	for cell := range universe {
+		a.exposure[cell]++
+	}
+	a.exposed++
+}
+
+// update ages the cells that survived into the next generation and records births and deaths.
+func (a *cellAges) update(previous, next map[Cell]struct{}, generation int) {
+	age := make(map[Cell]int, len(next))
+	for cell := range next {
+		if old, survived := a.age[cell]; survived {
+			age[cell] = old + 1
+		} else {
+			age[cell] = 0
+			a.lastChange[cell] = generation
+		}
+	}
+	for cell := range previous {
+		if _, survived := next[cell]; !survived {
+			a.lastChange[cell] = generation
+		}
+	}
+	a.age = age
+	a.expose(next)
+}
+
+// clone returns a copy of the ages that changes independently of them.
+func (a *cellAges) clone() *cellAges {
+	c := *a
+	c.age, c.lastChange, c.exposure = maps.Clone(a.age), maps.Clone(a.lastChange), maps.Clone(a.exposure)
+	return &c
+}
+
+// replay applies a recorded generation to the ages, as update does: the cells born and
+// died in it, and its universe.
+func (a *cellAges) replay(born, died []Cell, universe map[Cell]struct{}, generation int) {
+	for _, cell := range died {
+		delete(a.age, cell)
+		a.lastChange[cell] = generation
+	}
+	for cell := range a.age {
+		a.age[cell]++
+	}
+	for _, cell := range born {
+		a.age[cell] = 0
+		a.lastChange[cell] = generation
+	}
+	a.expose(universe)
+}
+
+// Age returns how many generations a live cell has been alive for: 0 in the generation
+// it was born. It returns false for dead cells and when age tracking is not enabled.
+func (g *GameOfLife) Age(cell Cell) (int, bool) {
+	if g.ages == nil {
+		return 0, false
+	}
+	// cells beyond an edge that is not joined are left where they are, and never alive
+	cell, _ = g.topology.wrap(cell, g.numRows, g.numCols)
+	age, alive := g.ages.age[cell]
+	return age, alive
+}
+
+// TimeSinceChange returns how many generations ago a cell, alive or dead, was last born
+// or died; cells that have not changed since tracking started count from then. It returns
+// false when age tracking is not enabled.
+func (g *GameOfLife) TimeSinceChange(cell Cell) (int, bool) {
+	if g.ages == nil {
+		return 0, false
+	}
+	cell, _ = g.topology.wrap(cell, g.numRows, g.numCols)
+	if changed, ok := g.ages.lastChange[cell]; ok {
+		return g.generation - changed, true
+	}
+	return g.generation - g.ages.since, true
+}
+
+// ColourMode selects how Display colours live cells.
+type ColourMode int
+
+const (
+	// ColourPlain shows every live cell in the same colour.
+	ColourPlain ColourMode = iota
+	// ColourByAge shows newborn cells bright and old cells dark. It needs age tracking.
+	ColourByAge
+)
+
+// SetColourMode selects how Display colours live cells. ColourByAge enables age tracking.
+func (g *GameOfLife) SetColourMode(mode ColourMode) {
+	if mode == ColourByAge && g.ages == nil {
+		g.EnableAgeTracking()
+	}
+	g.colourMode = mode
+}
+
+// ageColours are the 256-colour terminal codes of cells by age, from newborn (white)
+// through yellow and red to dark red for cells older than 2^11 generations.
+var ageColours = []int{231, 229, 226, 220, 214, 208, 202, 196, 160, 124, 88, 52}
+
+// ageChar returns a terminal cell coloured for the age of a live cell. Each colour covers
+// twice as many generations as the one before, so young cells can be told apart.
+func ageChar(age int) string {
+	step := min(int(math.Log2(float64(age+1))), len(ageColours)-1)
+	return fmt.Sprintf("\033[48;5;%dm \033[0m", ageColours[step])
+}
+
+// WriteHeatMap writes a PNG long-exposure image of the universe since age tracking was
+// enabled: each cell is a scale x scale square, black where the cell was never alive and
+// through red and yellow to white where it was always alive.
+func (g *GameOfLife) WriteHeatMap(w io.Writer, scale int) error {
+	if g.ages == nil {
+		return fmt.Errorf("heat map needs age tracking")
+	}
+	scale = max(scale, 1)
+
+	img := image.NewNRGBA(image.Rect(0, 0, g.numCols*scale, g.numRows*scale))
+	for r := range g.numRows {
+		for c := range g.numCols {
+			colour := heatColour(float64(g.ages.exposure[Cell{r, c}]) / float64(g.ages.exposed))
+			for y := r * scale; y < (r+1)*scale; y++ {
+				for x := c * scale; x < (c+1)*scale; x++ {
+					img.SetNRGBA(x, y, colour)
+				}
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// heatColour maps a fraction from 0 to 1 onto black, red, yellow and white.
+func heatColour(fraction float64) color.NRGBA {
+	level := func(from float64) uint8 {
+		return uint8(255 * min(max((fraction-from)*3, 0), 1))
+	}
+	return color.NRGBA{R: level(0), G: level(1.0 / 3), B: level(2.0 / 3), A: 255}
+}
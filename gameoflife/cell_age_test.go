@@ -0,0 +1,130 @@
+package gameoflife
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func blinkerUniverse(rules ...Rule) *GameOfLife {
+	g := CreateSeedUniverse(8, 8, Default, rules...)
+	g.universe = map[Cell]struct{}{{3, 2}: {}, {3, 3}: {}, {3, 4}: {}}
+	return g
+}
+
+func TestAgeTracking(t *testing.T) {
+	g := blinkerUniverse(ConwayRule{})
+	if _, ok := g.Age(Cell{3, 3}); ok {
+		t.Errorf("Age() without tracking reported an age")
+	}
+	g.EnableAgeTracking()
+	g.CreateNextGeneration()
+
+	ages := []struct {
+		cell  Cell
+		age   int
+		alive bool
+	}{
+		{Cell{3, 3}, 1, true},
+		{Cell{2, 3}, 0, true},
+		{Cell{4, 3}, 0, true},
+		{Cell{3, 2}, 0, false},
+		{Cell{0, 0}, 0, false},
+	}
+	for _, tt := range ages {
+		if age, alive := g.Age(tt.cell); age != tt.age || alive != tt.alive {
+			t.Errorf("Age(%v) = %d, %v; want %d, %v", tt.cell, age, alive, tt.age, tt.alive)
+		}
+	}
+
+	g.CreateNextGeneration()
+	if age, _ := g.Age(Cell{3, 3}); age != 2 {
+		t.Errorf("Age(centre) after 2 generations = %d; want 2", age)
+	}
+	changes := []struct {
+		cell  Cell
+		since int
+	}{
+		{Cell{3, 3}, 2}, // never changed, counted from when tracking started
+		{Cell{3, 2}, 0}, // born again
+		{Cell{2, 3}, 0}, // died again
+		{Cell{0, 0}, 2},
+	}
+	for _, tt := range changes {
+		if since, ok := g.TimeSinceChange(tt.cell); !ok || since != tt.since {
+			t.Errorf("TimeSinceChange(%v) = %d, %v; want %d", tt.cell, since, ok, tt.since)
+		}
+	}
+}
+
+func TestAgeTracking_KeptByTimeTravel(t *testing.T) {
+	g := blinkerUniverse(ConwayRule{})
+	g.EnableHistory(4, 0)
+	g.EnableAgeTracking()
+	for range 4 {
+		g.CreateNextGeneration()
+	}
+	if err := g.GoToGeneration(1); err != nil {
+		t.Fatalf("GoToGeneration(1) error = %v", err)
+	}
+	// the centre of the blinker was one generation old at generation 1
+	if age, ok := g.Age(Cell{3, 3}); !ok || age != 1 {
+		t.Errorf("Age(centre) after rewind = %d, %v; want 1, true", age, ok)
+	}
+}
+
+func TestAgeLimitRule(t *testing.T) {
+	g := CreateSeedUniverse(8, 8, Default, AgeLimitRule{Rule: ConwayRule{}, MaxAge: 3})
+	g.universe = map[Cell]struct{}{{3, 3}: {}, {3, 4}: {}, {4, 3}: {}, {4, 4}: {}}
+	g.EnableAgeTracking()
+
+	for generation := 1; generation <= 3; generation++ {
+		g.CreateNextGeneration()
+		if len(g.universe) != 4 {
+			t.Fatalf("generation %d: block has %d cells; want 4", generation, len(g.universe))
+		}
+	}
+	g.CreateNextGeneration()
+	if len(g.universe) != 0 {
+		t.Errorf("block older than MaxAge left %v; want it to die", g.universe)
+	}
+}
+
+func TestWriteHeatMap(t *testing.T) {
+	g := blinkerUniverse(ConwayRule{})
+	var buf bytes.Buffer
+	if err := g.WriteHeatMap(&buf, 2); err == nil {
+		t.Errorf("WriteHeatMap() without tracking succeeded; want an error")
+	}
+
+	g.EnableAgeTracking()
+	g.CreateNextGeneration()
+	g.CreateNextGeneration()
+	buf.Reset()
+	if err := g.WriteHeatMap(&buf, 2); err != nil {
+		t.Fatalf("WriteHeatMap() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decoding heat map: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 16 || size.Y != 16 {
+		t.Errorf("heat map size = %v; want 16x16", size)
+	}
+
+	pixels := []struct {
+		cell Cell
+		want color.NRGBA
+	}{
+		{Cell{3, 3}, color.NRGBA{255, 255, 255, 255}}, // always alive
+		{Cell{3, 2}, color.NRGBA{255, 255, 0, 255}},   // alive in 2 of 3 generations
+		{Cell{2, 3}, color.NRGBA{255, 0, 0, 255}},     // alive in 1 of 3
+		{Cell{0, 0}, color.NRGBA{0, 0, 0, 255}},       // never alive
+	}
+	for _, tt := range pixels {
+		if got := color.NRGBAModel.Convert(img.At(tt.cell.C*2+1, tt.cell.R*2+1)); got != tt.want {
+			t.Errorf("heat map at %v = %v; want %v", tt.cell, got, tt.want)
+		}
+	}
+}
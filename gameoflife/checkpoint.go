@@ -0,0 +1,582 @@
+package gameoflife
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CheckpointFormat selects the on-disk encoding of a checkpoint.
+type CheckpointFormat int
+
+const (
+	// CheckpointBinary is a compact varint encoding with delta-coded cell positions.
+	CheckpointBinary CheckpointFormat = iota
+	// CheckpointJSON is a human readable encoding of the same data.
+	CheckpointJSON
+)
+
+// CheckpointVersion is the current version of the checkpoint format.
+// Readers reject checkpoints written by a newer version.
+// Version 2 added the states of multistate automata, version 3 the topology, version 4
+// the colours and cell ages.
+const CheckpointVersion = 4
+
+// checkpointMagic prefixes every binary checkpoint and is used to tell binary from JSON.
+var checkpointMagic = []byte("GOLCKPT")
+
+func (f CheckpointFormat) String() string {
+	switch f {
+	case CheckpointJSON:
+		return "json"
+	default:
+		return "binary"
+	}
+}
+
+// ParseCheckpointFormat returns the checkpoint format for the given name ("binary" or "json").
+func ParseCheckpointFormat(name string) (CheckpointFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "binary", "bin", "":
+		return CheckpointBinary, nil
+	case "json":
+		return CheckpointJSON, nil
+	default:
+		return CheckpointBinary, fmt.Errorf("unknown checkpoint format %q", name)
+	}
+}
+
+// Checkpoint is a complete, serialisable snapshot of a GameOfLife or StateAutomaton.
+// Restoring a checkpoint and continuing produces exactly the same generations
+// as the original run would have. States, for multistate automata only, holds the
+// state of each of Cells. Topology is empty for the torus. ColourScheme is empty unless
+// the universe follows a colour scheme, when Colours holds the colour of each of Cells.
+type Checkpoint struct {
+	Version    int      `json:"version"`
+	Rows       int      `json:"rows"`
+	Cols       int      `json:"cols"`
+	Generation int      `json:"generation"`
+	Rules      []string `json:"rules"`
+	RNG        []byte   `json:"rng,omitempty"`
+	Cells      []Cell   `json:"cells"`
+	States     []int    `json:"states,omitempty"`
+	Topology   string   `json:"topology,omitempty"`
+
+	ColourScheme string          `json:"colour_scheme,omitempty"`
+	Colours      []int           `json:"colours,omitempty"`
+	Ages         *CheckpointAges `json:"ages,omitempty"`
+}
+
+// CheckpointAges is the state of age tracking, present in checkpoints of universes that
+// track ages. Changed and Exposure list cells row-major, with the generation each was
+// last born or died in and the number of tracked generations each was alive in.
+type CheckpointAges struct {
+	Since     int    `json:"since"`
+	Exposed   int    `json:"exposed"`
+	Ages      []int  `json:"ages"`
+	Changed   []Cell `json:"changed,omitempty"`
+	ChangedAt []int  `json:"changed_at,omitempty"`
+	Exposure  []Cell `json:"exposure,omitempty"`
+	Exposures []int  `json:"exposures,omitempty"`
+}
+
+// Checkpoint captures the current state of the universe.
+// Live cells are listed in row-major order so that equal universes produce equal checkpoints.
+func (g *GameOfLife) Checkpoint() (*Checkpoint, error) {
+	cp := &Checkpoint{
+		Version:    CheckpointVersion,
+		Rows:       g.numRows,
+		Cols:       g.numCols,
+		Generation: g.generation,
+		Rules:      make([]string, 0, len(g.rules)),
+		Cells:      make([]Cell, 0, len(g.universe)),
+	}
+	if g.topology != TopologyTorus {
+		cp.Topology = g.topology.String()
+	}
+
+	for _, rule := range g.rules {
+		name := RuleName(rule)
+		if name == "" {
+			return nil, fmt.Errorf("rule %T cannot be checkpointed", rule)
+		}
+		cp.Rules = append(cp.Rules, name)
+	}
+
+	if g.rng != nil {
+		state, err := g.rng.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("saving random state: %w", err)
+		}
+		cp.RNG = state
+	}
+
+	for cell := range g.universe {
+		cp.Cells = append(cp.Cells, cell)
+	}
+	slices.SortFunc(cp.Cells, compareCells)
+
+	if g.colours != nil {
+		cp.ColourScheme = g.colourScheme.String()
+		cp.Colours = make([]int, len(cp.Cells))
+		for i, cell := range cp.Cells {
+			cp.Colours[i], _ = g.Colour(cell)
+		}
+	}
+	if g.ages != nil {
+		cp.Ages = &CheckpointAges{
+			Since:   g.ages.since,
+			Exposed: g.ages.exposed,
+			Ages:    make([]int, len(cp.Cells)),
+		}
+		for i, cell := range cp.Cells {
+			cp.Ages.Ages[i] = g.ages.age[cell]
+		}
+		cp.Ages.Changed, cp.Ages.ChangedAt = sortedCellValues(g.ages.lastChange)
+		cp.Ages.Exposure, cp.Ages.Exposures = sortedCellValues(g.ages.exposure)
+	}
+
+	return cp, nil
+}
+
+// sortedCellValues returns the cells of a map row-major, with the value of each.
+func sortedCellValues(m map[Cell]int) ([]Cell, []int) {
+	cells := slices.SortedFunc(maps.Keys(m), compareCells)
+	values := make([]int, len(cells))
+	for i, cell := range cells {
+		values[i] = m[cell]
+	}
+	return cells, values
+}
+
+// Restore rebuilds a GameOfLife from the checkpoint.
+func (cp *Checkpoint) Restore() (*GameOfLife, error) {
+	if cp.Version <= 0 || cp.Version > CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d", cp.Version)
+	}
+	if cp.Rows <= 0 || cp.Cols <= 0 {
+		return nil, fmt.Errorf("invalid checkpoint dimensions %dx%d", cp.Rows, cp.Cols)
+	}
+
+	rules := make([]Rule, 0, len(cp.Rules))
+	for _, name := range cp.Rules {
+		parsed := ParseRulesFromString(name)
+		if len(parsed) != 1 {
+			return nil, fmt.Errorf("unknown rule %q in checkpoint", name)
+		}
+		rules = append(rules, parsed[0])
+	}
+
+	topology, err := ParseTopology(cp.Topology)
+	if err != nil {
+		return nil, err
+	}
+
+	g := CreateSeedUniverse(cp.Rows, cp.Cols, Default, rules...)
+	g.topology = topology
+	g.universe = make(map[Cell]struct{}, len(cp.Cells))
+	if err := cp.checkCells(cp.Cells); err != nil {
+		return nil, err
+	}
+	for _, cell := range cp.Cells {
+		g.universe[cell] = struct{}{}
+	}
+	g.generation = cp.Generation
+
+	if cp.ColourScheme != "" {
+		scheme, err := ParseColourScheme(cp.ColourScheme)
+		if err != nil {
+			return nil, err
+		}
+		if len(cp.Colours) != len(cp.Cells) {
+			return nil, fmt.Errorf("checkpoint has %d colours for %d cells", len(cp.Colours), len(cp.Cells))
+		}
+		colours := make(map[Cell]int, len(cp.Cells))
+		for i, cell := range cp.Cells {
+			colours[cell] = cp.Colours[i]
+		}
+		g.SetColours(scheme, colours)
+	}
+	if cp.Ages != nil {
+		ages, err := cp.restoreAges()
+		if err != nil {
+			return nil, err
+		}
+		g.ages = ages
+	}
+
+	if len(cp.RNG) > 0 {
+		rng := &rand.PCG{}
+		if err := rng.UnmarshalBinary(cp.RNG); err != nil {
+			return nil, fmt.Errorf("restoring random state: %w", err)
+		}
+		g.rng = rng
+	}
+
+	return g, nil
+}
+
+// restoreAges rebuilds the state of age tracking from the checkpoint.
+func (cp *Checkpoint) restoreAges() (*cellAges, error) {
+	a := cp.Ages
+	if len(a.Ages) != len(cp.Cells) || len(a.ChangedAt) != len(a.Changed) || len(a.Exposures) != len(a.Exposure) {
+		return nil, errors.New("checkpoint ages do not match their cells")
+	}
+	for _, cells := range [][]Cell{a.Changed, a.Exposure} {
+		if err := cp.checkCells(cells); err != nil {
+			return nil, err
+		}
+	}
+	ages := &cellAges{
+		since:      a.Since,
+		exposed:    a.Exposed,
+		age:        make(map[Cell]int, len(cp.Cells)),
+		lastChange: make(map[Cell]int, len(a.Changed)),
+		exposure:   make(map[Cell]int, len(a.Exposure)),
+	}
+	for i, cell := range cp.Cells {
+		ages.age[cell] = a.Ages[i]
+	}
+	for i, cell := range a.Changed {
+		ages.lastChange[cell] = a.ChangedAt[i]
+	}
+	for i, cell := range a.Exposure {
+		ages.exposure[cell] = a.Exposures[i]
+	}
+	return ages, nil
+}
+
+// checkCells returns an error if any of the cells lies outside the checkpoint's universe.
+func (cp *Checkpoint) checkCells(cells []Cell) error {
+	for _, cell := range cells {
+		if cell.R < 0 || cell.R >= cp.Rows || cell.C < 0 || cell.C >= cp.Cols {
+			return fmt.Errorf("cell %v lies outside the %dx%d universe", cell, cp.Rows, cp.Cols)
+		}
+	}
+	return nil
+}
+
+// RestoreAutomaton rebuilds whichever automaton the checkpoint was taken of.
+func (cp *Checkpoint) RestoreAutomaton() (Automaton, error) {
+	if len(cp.Rules) == 1 {
+		if _, err := ParseStateRule(cp.Rules[0]); err == nil {
+			return cp.restoreStateAutomaton()
+		}
+		if rule, err := ParseBlockRule(cp.Rules[0]); err == nil {
+			return cp.restoreBlockAutomaton(rule)
+		}
+	}
+	return cp.Restore()
+}
+
+// LoadAutomaton reads the checkpoint stored at path and restores the automaton it was
+// taken of, a GameOfLife or a StateAutomaton.
+func LoadAutomaton(path string) (Automaton, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cp, err := ReadCheckpoint(f)
+	if err != nil {
+		return nil, err
+	}
+	return cp.RestoreAutomaton()
+}
+
+// WriteCheckpoint writes the current state of the universe to w in the given format.
+func (g *GameOfLife) WriteCheckpoint(w io.Writer, format CheckpointFormat) error {
+	cp, err := g.Checkpoint()
+	if err != nil {
+		return err
+	}
+	return cp.write(w, format)
+}
+
+func (cp *Checkpoint) write(w io.Writer, format CheckpointFormat) error {
+	if format == CheckpointJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cp)
+	}
+	return cp.writeBinary(w)
+}
+
+// SaveCheckpoint atomically writes a checkpoint to path, so an interrupted write
+// never destroys the previous checkpoint.
+func (g *GameOfLife) SaveCheckpoint(path string, format CheckpointFormat) error {
+	return writeFileAtomic(path, func(w io.Writer) error { return g.WriteCheckpoint(w, format) })
+}
+
+// writeFileAtomic writes a file through a temporary file in the same directory, renamed
+// over path once complete, so readers see either the old file or the whole new one.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadCheckpoint reads a checkpoint in either format from r, detecting the format
+// from the leading bytes.
+func ReadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(checkpointMagic))
+	if err == nil && bytes.Equal(head, checkpointMagic) {
+		return readBinaryCheckpoint(br)
+	}
+
+	cp := &Checkpoint{}
+	if err := json.NewDecoder(br).Decode(cp); err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// DetectCheckpointFormat returns the format of the checkpoint stored at path, as
+// ReadCheckpoint would detect it.
+func DetectCheckpointFormat(path string) (CheckpointFormat, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CheckpointBinary, err
+	}
+	defer f.Close()
+
+	head, err := bufio.NewReader(f).Peek(len(checkpointMagic))
+	if err == nil && bytes.Equal(head, checkpointMagic) {
+		return CheckpointBinary, nil
+	}
+	return CheckpointJSON, nil
+}
+
+// LoadCheckpoint reads the checkpoint stored at path and restores the universe from it.
+func LoadCheckpoint(path string) (*GameOfLife, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cp, err := ReadCheckpoint(f)
+	if err != nil {
+		return nil, err
+	}
+	return cp.Restore()
+}
+
+// writeBinary encodes the checkpoint as:
+//
+//	magic, version, rows, cols, generation,
+//	#rules, (len, name)..., len(rng), rng,
+//	#cells, (delta of row-major cell index)...,
+//	#states, state...   (version 2 and later)
+//	len(topology), topology   (version 3 and later)
+//	len(colour scheme), colour scheme, #colours, colour...   (version 4 and later)
+//	has ages, [since, exposed, #ages, age..., changed cells, generation...,
+//	exposure cells, count...]   (version 4 and later)
+//
+// Cell lists are written as #cells followed by the delta of each cell's row-major index.
+//
+// All integers are unsigned varints.
+func (cp *Checkpoint) writeBinary(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	buf.Write(checkpointMagic)
+
+	putUvarint := func(v uint64) {
+		buf.Write(binary.AppendUvarint(nil, v))
+	}
+
+	putUvarint(uint64(cp.Version))
+	putUvarint(uint64(cp.Rows))
+	putUvarint(uint64(cp.Cols))
+	putUvarint(uint64(cp.Generation))
+	putUvarint(uint64(len(cp.Rules)))
+	for _, name := range cp.Rules {
+		putUvarint(uint64(len(name)))
+		buf.WriteString(name)
+	}
+	putUvarint(uint64(len(cp.RNG)))
+	buf.Write(cp.RNG)
+
+	putCells := func(cells []Cell) {
+		putUvarint(uint64(len(cells)))
+		previous := uint64(0)
+		for _, cell := range cells {
+			index := uint64(cell.R)*uint64(cp.Cols) + uint64(cell.C)
+			putUvarint(index - previous)
+			previous = index
+		}
+	}
+	putInts := func(values []int) {
+		for _, v := range values {
+			putUvarint(uint64(v))
+		}
+	}
+
+	putCells(cp.Cells)
+	if cp.Version >= 2 {
+		putUvarint(uint64(len(cp.States)))
+		for _, state := range cp.States {
+			putUvarint(uint64(state))
+		}
+	}
+	if cp.Version >= 3 {
+		putUvarint(uint64(len(cp.Topology)))
+		buf.WriteString(cp.Topology)
+	}
+	if cp.Version >= 4 {
+		putUvarint(uint64(len(cp.ColourScheme)))
+		buf.WriteString(cp.ColourScheme)
+		putUvarint(uint64(len(cp.Colours)))
+		putInts(cp.Colours)
+		if a := cp.Ages; a == nil {
+			putUvarint(0)
+		} else {
+			putUvarint(1)
+			putUvarint(uint64(a.Since))
+			putUvarint(uint64(a.Exposed))
+			putUvarint(uint64(len(a.Ages)))
+			putInts(a.Ages)
+			putCells(a.Changed)
+			putInts(a.ChangedAt)
+			putCells(a.Exposure)
+			putInts(a.Exposures)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// readBinaryCheckpoint decodes a checkpoint written by writeBinary.
+func readBinaryCheckpoint(r *bufio.Reader) (*Checkpoint, error) {
+	if _, err := r.Discard(len(checkpointMagic)); err != nil {
+		return nil, err
+	}
+
+	var readErr error
+	getUvarint := func() uint64 {
+		if readErr != nil {
+			return 0
+		}
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			readErr = err
+		}
+		return v
+	}
+	getBytes := func() []byte {
+		n := getUvarint()
+		if readErr != nil {
+			return nil
+		}
+		if n > 1<<20 {
+			readErr = errors.New("field too large")
+			return nil
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			readErr = err
+		}
+		return b
+	}
+
+	cp := &Checkpoint{
+		Version:    int(getUvarint()),
+		Rows:       int(getUvarint()),
+		Cols:       int(getUvarint()),
+		Generation: int(getUvarint()),
+	}
+	for n := getUvarint(); readErr == nil && n > 0; n-- {
+		cp.Rules = append(cp.Rules, string(getBytes()))
+	}
+	cp.RNG = getBytes()
+
+	getCells := func() []Cell {
+		numCells := getUvarint()
+		if readErr != nil {
+			return nil
+		}
+		if cp.Cols <= 0 || numCells > uint64(cp.Rows)*uint64(cp.Cols) {
+			readErr = errors.New("cell count does not fit the universe")
+			return nil
+		}
+		cells := make([]Cell, 0, numCells)
+		index := uint64(0)
+		for ; readErr == nil && numCells > 0; numCells-- {
+			index += getUvarint()
+			cells = append(cells, Cell{int(index / uint64(cp.Cols)), int(index % uint64(cp.Cols))})
+		}
+		return cells
+	}
+	// getInts reads n values, n at most the length of a cell list already read
+	getInts := func(n uint64, cells int) []int {
+		if readErr == nil && n > uint64(cells) {
+			readErr = errors.New("more values than cells")
+		}
+		var values []int
+		for ; readErr == nil && n > 0; n-- {
+			values = append(values, int(getUvarint()))
+		}
+		return values
+	}
+
+	cp.Cells = getCells()
+	if cp.Version >= 2 {
+		cp.States = getInts(getUvarint(), len(cp.Cells))
+	}
+	if cp.Version >= 3 {
+		cp.Topology = string(getBytes())
+	}
+	if cp.Version >= 4 {
+		cp.ColourScheme = string(getBytes())
+		cp.Colours = getInts(getUvarint(), len(cp.Cells))
+		if getUvarint() == 1 && readErr == nil {
+			a := &CheckpointAges{Since: int(getUvarint()), Exposed: int(getUvarint())}
+			a.Ages = getInts(getUvarint(), len(cp.Cells))
+			a.Changed = getCells()
+			a.ChangedAt = getInts(uint64(len(a.Changed)), len(a.Changed))
+			a.Exposure = getCells()
+			a.Exposures = getInts(uint64(len(a.Exposure)), len(a.Exposure))
+			cp.Ages = a
+		}
+	}
+
+	if readErr != nil {
+		return nil, fmt.Errorf("reading binary checkpoint: %w", readErr)
+	}
+	return cp, nil
+}
+
+// compareCells orders cells row-major.
+func compareCells(a, b Cell) int {
+	if a.R != b.R {
+		return a.R - b.R
+	}
+	return a.C - b.C
+}
@@ -0,0 +1,152 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint_RoundTripContinuesIdentically(t *testing.T) {
+	for _, format := range []CheckpointFormat{CheckpointBinary, CheckpointJSON} {
+		t.Run(format.String(), func(t *testing.T) {
+			original := CreateSeedUniverse(10, 12, Glider, RuleFactory(ConwayRuleType))
+			original.SetRandomSeed(42)
+			for range 7 {
+				original.CreateNextGeneration()
+			}
+
+			var buf bytes.Buffer
+			if err := original.WriteCheckpoint(&buf, format); err != nil {
+				t.Fatalf("WriteCheckpoint() error = %v", err)
+			}
+			cp, err := ReadCheckpoint(&buf)
+			if err != nil {
+				t.Fatalf("ReadCheckpoint() error = %v", err)
+			}
+			resumed, err := cp.Restore()
+			if err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+
+			if resumed.Generation() != 7 {
+				t.Errorf("Generation() = %d; want 7", resumed.Generation())
+			}
+			if got, want := resumed.Rand().Uint64(), original.Rand().Uint64(); got != want {
+				t.Errorf("random state not restored: got %d, want %d", got, want)
+			}
+
+			for range 13 {
+				original.CreateNextGeneration()
+				resumed.CreateNextGeneration()
+			}
+			if len(resumed.universe) != len(original.universe) {
+				t.Fatalf("got %d alive cells, want %d", len(resumed.universe), len(original.universe))
+			}
+			for cell := range original.universe {
+				if _, ok := resumed.universe[cell]; !ok {
+					t.Errorf("expected cell %v to be alive after resuming", cell)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckpoint_KeepsColoursAndAges(t *testing.T) {
+	for _, format := range []CheckpointFormat{CheckpointBinary, CheckpointJSON} {
+		t.Run(format.String(), func(t *testing.T) {
+			original := CreateSeedUniverse(16, 16, Default, ConwayRule{})
+			original.SetRandomSeed(7)
+			original.FillRandom(0.4)
+			original.EnableColours(QuadLife, ColourSeedRandom)
+			original.EnableAgeTracking()
+			for range 5 {
+				original.CreateNextGeneration()
+			}
+
+			var buf bytes.Buffer
+			if err := original.WriteCheckpoint(&buf, format); err != nil {
+				t.Fatalf("WriteCheckpoint() error = %v", err)
+			}
+			cp, err := ReadCheckpoint(&buf)
+			if err != nil {
+				t.Fatalf("ReadCheckpoint() error = %v", err)
+			}
+			resumed, err := cp.Restore()
+			if err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+			if resumed.ColourScheme() != QuadLife {
+				t.Errorf("ColourScheme() = %v; want QuadLife", resumed.ColourScheme())
+			}
+
+			for range 5 {
+				original.CreateNextGeneration()
+				resumed.CreateNextGeneration()
+			}
+			if !maps.Equal(resumed.colours, original.colours) {
+				t.Errorf("colours after resuming = %v; want %v", resumed.colours, original.colours)
+			}
+			if !maps.Equal(resumed.ages.age, original.ages.age) || !maps.Equal(resumed.ages.lastChange, original.ages.lastChange) ||
+				!maps.Equal(resumed.ages.exposure, original.ages.exposure) || resumed.ages.exposed != original.ages.exposed ||
+				resumed.ages.since != original.ages.since {
+				t.Errorf("ages after resuming = %+v; want %+v", resumed.ages, original.ages)
+			}
+		})
+	}
+}
+
+func TestSaveCheckpoint_LoadCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.ckpt")
+	g := CreateSeedUniverse(5, 5, Default, RuleFactory(ConwayRuleType), RuleFactory(NoTopLeftNeighborRuleType))
+	g.CreateNextGeneration()
+
+	if err := g.SaveCheckpoint(path, CheckpointBinary); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if len(loaded.rules) != 2 {
+		t.Errorf("got %d rules, want 2", len(loaded.rules))
+	}
+	if loaded.numRows != 5 || loaded.numCols != 5 {
+		t.Errorf("dimensions = %dx%d; want 5x5", loaded.numRows, loaded.numCols)
+	}
+}
+
+func TestDetectCheckpointFormat(t *testing.T) {
+	g := CreateSeedUniverse(5, 5, Glider, ConwayRule{})
+	for _, format := range []CheckpointFormat{CheckpointBinary, CheckpointJSON} {
+		path := filepath.Join(t.TempDir(), "state.ckpt")
+		if err := g.SaveCheckpoint(path, format); err != nil {
+			t.Fatalf("SaveCheckpoint(%v) error = %v", format, err)
+		}
+		if detected, err := DetectCheckpointFormat(path); err != nil || detected != format {
+			t.Errorf("DetectCheckpointFormat() = %v, %v; want %v", detected, err, format)
+		}
+	}
+}
+
+func TestCheckpoint_RestoreRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cp   Checkpoint
+	}{
+		{"future-version", Checkpoint{Version: CheckpointVersion + 1, Rows: 3, Cols: 3}},
+		{"bad-dimensions", Checkpoint{Version: CheckpointVersion, Rows: 0, Cols: 3}},
+		{"unknown-rule", Checkpoint{Version: CheckpointVersion, Rows: 3, Cols: 3, Rules: []string{"nope"}}},
+		{"cell-outside", Checkpoint{Version: CheckpointVersion, Rows: 3, Cols: 3, Cells: []Cell{{3, 0}}}},
+		{"missing-colours", Checkpoint{Version: CheckpointVersion, Rows: 3, Cols: 3, Cells: []Cell{{1, 1}}, ColourScheme: "QuadLife"}},
+		{"missing-ages", Checkpoint{Version: CheckpointVersion, Rows: 3, Cols: 3, Cells: []Cell{{1, 1}}, Ages: &CheckpointAges{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cp.Restore(); err == nil {
+				t.Errorf("Restore() succeeded; want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package gameoflife
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ColourScheme selects a colour-inheriting variant of Life. Cells are born and die as
+// under the universe's rules, but every live cell also has one of Colours colours: a
+// survivor keeps its colour and a newborn takes the colour most common among its live
+// neighbours, its parents.
+type ColourScheme int
+
+const (
+	// Monochrome is plain Life: every live cell has colour 1.
+	Monochrome ColourScheme = iota
+	// Immigration has two colours; with three parents a newborn always has a majority colour.
+	Immigration
+	// QuadLife has four colours; a newborn whose three parents all differ takes the fourth.
+	QuadLife
+)
+
+func (s ColourScheme) String() string {
+	switch s {
+	case Immigration:
+		return "Immigration"
+	case QuadLife:
+		return "QuadLife"
+	default:
+		return "Monochrome"
+	}
+}
+
+// Colours returns the number of colours live cells can have.
+func (s ColourScheme) Colours() int {
+	switch s {
+	case Immigration:
+		return 2
+	case QuadLife:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// ParseColourScheme returns the colour scheme with the given name, in any case, as used
+// by Golly in the rule line of pattern files.
+func ParseColourScheme(name string) (ColourScheme, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "monochrome":
+		return Monochrome, nil
+	case "immigration":
+		return Immigration, nil
+	case "quadlife":
+		return QuadLife, nil
+	default:
+		return Monochrome, fmt.Errorf("unknown colour scheme %q, want immigration or quadlife", name)
+	}
+}
+
+// ColourSeed selects how EnableColours colours the cells already alive.
+type ColourSeed int
+
+const (
+	// ColourSeedRandom gives every live cell a random colour drawn from the universe's generator.
+	ColourSeedRandom ColourSeed = iota
+	// ColourSeedRegions splits the universe into one region per colour, left and right
+	// halves for two colours and quadrants for four, so that colours start out as teams.
+	ColourSeedRegions
+)
+
+// ParseColourSeed returns the colour seed with the given name: random or regions.
+func ParseColourSeed(name string) (ColourSeed, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "random":
+		return ColourSeedRandom, nil
+	case "regions":
+		return ColourSeedRegions, nil
+	default:
+		return ColourSeedRandom, fmt.Errorf("unknown colour seed %q, want random or regions", name)
+	}
+}
+
+// EnableColours makes the universe follow a colour scheme, colouring the cells already
+// alive as selected by seed.
+func (g *GameOfLife) EnableColours(scheme ColourScheme, seed ColourSeed) {
+	colours := make(map[Cell]int, len(g.universe))
+	n := scheme.Colours()
+	rng := g.Rand()
+	// in a fixed order, so the same random seed gives the same colours
+	for _, cell := range slices.SortedFunc(maps.Keys(g.universe), compareCells) {
+		switch {
+		case seed == ColourSeedRegions && n == 2:
+			colours[cell] = 1 + 2*cell.C/g.numCols
+		case seed == ColourSeedRegions:
+			colours[cell] = 1 + min(2*cell.R/g.numRows, 1)*2 + min(2*cell.C/g.numCols, 1)
+		default:
+			colours[cell] = 1 + rng.IntN(n)
+		}
+		colours[cell] = min(colours[cell], n)
+	}
+	g.SetColours(scheme, colours)
+}
+
+// SetColours makes the universe follow a colour scheme with the given colours, from 1 to
+// scheme.Colours(). Live cells without a colour get colour 1.
+func (g *GameOfLife) SetColours(scheme ColourScheme, colours map[Cell]int) {
+	g.colourScheme = scheme
+	g.colours = make(map[Cell]int, len(g.universe))
+	for cell := range g.universe {
+		g.colours[cell] = min(max(colours[cell], 1), scheme.Colours())
+	}
+}
+
+// ColourScheme returns the colour scheme the universe follows.
+func (g *GameOfLife) ColourScheme() ColourScheme {
+	return g.colourScheme
+}
+
+// Colour returns the colour of a live cell, from 1 to ColourScheme().Colours(). It returns
+// false for dead cells.
+func (g *GameOfLife) Colour(cell Cell) (int, bool) {
+	cell, ok := g.topology.wrap(cell, g.numRows, g.numCols)
+	if _, alive := g.universe[cell]; !ok || !alive {
+		return 0, false
+	}
+	if colour, ok := g.colours[cell]; ok {
+		return colour, true
+	}
+	return 1, true
+}
+
+// ColourPopulations returns the number of live cells of each colour, colour 1 first.
+func (g *GameOfLife) ColourPopulations() []int {
+	populations := make([]int, g.colourScheme.Colours())
+	for cell := range g.universe {
+		colour, _ := g.Colour(cell)
+		populations[colour-1]++
+	}
+	return populations
+}
+
+// nextColours colours the next generation: survivors keep their colour and newborns
+// inherit theirs.
+func (g *GameOfLife) nextColours(next map[Cell]struct{}) map[Cell]int {
+	colours := make(map[Cell]int, len(next))
+	for cell := range next {
+		if colour, survived := g.colours[cell]; survived {
+			colours[cell] = colour
+		} else {
+			colours[cell] = g.inheritColour(cell)
+		}
+	}
+	return colours
+}
+
+// inheritColour returns the colour of a cell born in the next generation: the colour most
+// common among its live neighbours. When several colours are tied, the lowest wins, except
+// in QuadLife where three parents of different colours give the missing fourth.
+func (g *GameOfLife) inheritColour(cell Cell) int {
+	var counts [5]int
+	parents := 0
+	for _, offset := range g.neighbouringCells {
+		parent, ok := g.neighbour(cell, offset)
+		if !ok {
+			continue
+		}
+		if colour, alive := g.Colour(parent); alive {
+			counts[colour]++
+			parents++
+		}
+	}
+
+	best := 1
+	for colour := 2; colour <= g.colourScheme.Colours(); colour++ {
+		if counts[colour] > counts[best] {
+			best = colour
+		}
+	}
+	if g.colourScheme == QuadLife && parents == 3 && counts[best] == 1 {
+		for colour := 1; colour <= 4; colour++ {
+			if counts[colour] == 0 {
+				return colour
+			}
+		}
+	}
+	return best
+}
+
+// colourChars are the terminal cells of live cells by colour, colour 1 first.
+var colourChars = []string{
+	"\033[44m \033[0m", // blue
+	"\033[43m \033[0m", // yellow
+	"\033[42m \033[0m", // green
+	"\033[45m \033[0m", // magenta
+}
@@ -0,0 +1,97 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestColours_Inheritance(t *testing.T) {
+	tests := []struct {
+		scheme  ColourScheme
+		colours map[Cell]int // of a horizontal blinker
+		centre  int
+		born    int
+	}{
+		{Immigration, map[Cell]int{{3, 2}: 1, {3, 3}: 2, {3, 4}: 1}, 2, 1},
+		{Immigration, map[Cell]int{{3, 2}: 2, {3, 3}: 2, {3, 4}: 1}, 2, 2},
+		{QuadLife, map[Cell]int{{3, 2}: 1, {3, 3}: 1, {3, 4}: 3}, 1, 1},
+		{QuadLife, map[Cell]int{{3, 2}: 1, {3, 3}: 2, {3, 4}: 3}, 2, 4},
+	}
+	for _, tt := range tests {
+		g := blinkerUniverse(ConwayRule{})
+		g.SetColours(tt.scheme, tt.colours)
+		g.CreateNextGeneration()
+
+		if colour, _ := g.Colour(Cell{3, 3}); colour != tt.centre {
+			t.Errorf("%s %v: surviving centre has colour %d; want %d", tt.scheme, tt.colours, colour, tt.centre)
+		}
+		for _, cell := range []Cell{{2, 3}, {4, 3}} {
+			if colour, _ := g.Colour(cell); colour != tt.born {
+				t.Errorf("%s %v: newborn %v has colour %d; want %d", tt.scheme, tt.colours, cell, colour, tt.born)
+			}
+		}
+	}
+}
+
+func TestColours_SeedsAndPopulations(t *testing.T) {
+	g := CreateSeedUniverse(8, 8, Default, ConwayRule{})
+	g.universe = map[Cell]struct{}{{0, 0}: {}, {0, 7}: {}, {7, 0}: {}, {7, 7}: {}, {1, 1}: {}}
+	g.EnableColours(QuadLife, ColourSeedRegions)
+	if got, want := g.ColourPopulations(), []int{2, 1, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("ColourPopulations() of quadrants = %v; want %v", got, want)
+	}
+	if got := g.Stats().Colours; !slices.Equal(got, []int{2, 1, 1, 1}) {
+		t.Errorf("Stats().Colours = %v; want [2 1 1 1]", got)
+	}
+
+	g.EnableColours(Immigration, ColourSeedRegions)
+	if got, want := g.ColourPopulations(), []int{3, 2}; !slices.Equal(got, want) {
+		t.Errorf("ColourPopulations() of halves = %v; want %v", got, want)
+	}
+
+	g.SetRandomSeed(7)
+	g.EnableColours(QuadLife, ColourSeedRandom)
+	first := maps.Clone(g.colours)
+	g.SetRandomSeed(7)
+	g.EnableColours(QuadLife, ColourSeedRandom)
+	if !maps.Equal(first, g.colours) {
+		t.Errorf("random colours differ with the same seed: %v and %v", first, g.colours)
+	}
+}
+
+func TestColours_MultistateRLE(t *testing.T) {
+	const input = "x = 4, y = 2, rule = QuadLife\n2A.B$CD!\n"
+	p, err := ReadPattern(strings.NewReader(input), FormatRLE)
+	if err != nil {
+		t.Fatalf("ReadPattern() error = %v", err)
+	}
+	want := map[Cell]int{{0, 0}: 1, {0, 1}: 1, {0, 3}: 2, {1, 0}: 3, {1, 1}: 4}
+	if !maps.Equal(p.States, want) || len(p.Cells) != len(want) {
+		t.Errorf("ReadPattern() states = %v with %d cells; want %v", p.States, len(p.Cells), want)
+	}
+
+	g, err := CreateUniverseFromPattern(4, 6, p, ConwayRule{})
+	if err != nil {
+		t.Fatalf("CreateUniverseFromPattern() error = %v", err)
+	}
+	if g.ColourScheme() != QuadLife || !slices.Equal(g.ColourPopulations(), []int{2, 1, 1, 1}) {
+		t.Errorf("universe follows %s with populations %v; want QuadLife with [2 1 1 1]", g.ColourScheme(), g.ColourPopulations())
+	}
+
+	var out bytes.Buffer
+	if err := WritePattern(&out, g.Pattern(), FormatRLE); err != nil {
+		t.Fatalf("WritePattern() error = %v", err)
+	}
+	if out.String() != input {
+		t.Errorf("WritePattern() = %q; want %q", out.String(), input)
+	}
+
+	// two-state patterns stay two-state
+	p, err = ReadPattern(strings.NewReader("x = 3, y = 1\nobo!\n"), FormatRLE)
+	if err != nil || p.States != nil {
+		t.Errorf("ReadPattern() of a two-state pattern gave states %v, error %v; want none", p.States, err)
+	}
+}
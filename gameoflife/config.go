@@ -0,0 +1,333 @@
+package gameoflife
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config describes a whole simulation, so a run can be reproduced from a file instead of a
+// long command line: the universe, how it is seeded, how long it runs, when it stops early
+// and where its results go.
+type Config struct {
+	Rows     int    `json:"rows"`
+	Cols     int    `json:"cols"`
+	Topology string `json:"topology,omitempty"`
+	// Rules are comma-separated rule names or rulestrings, as for ParseRulesFromString.
+	Rules string `json:"rules"`
+	// Engine is how generations are computed, see ParseEngine; it does not change them.
+	Engine string `json:"engine,omitempty"`
+	// TilePeriod is the longest period the tiled engine skips, see SetTilePeriod.
+	TilePeriod int `json:"tile_period,omitempty"`
+	// RandomSeed seeds the universe's random number generator, see SetRandomSeed.
+	RandomSeed uint64 `json:"random_seed"`
+	// Seed is the built-in seed pattern (default or glider), used when there are no
+	// Patterns and no Density.
+	Seed string `json:"seed,omitempty"`
+	// Density fills the universe at random, see FillRandom, before Patterns are placed.
+	Density  float64            `json:"density,omitempty"`
+	Patterns []PatternPlacement `json:"patterns,omitempty"`
+	// Generations is how many generations the run lasts.
+	Generations int          `json:"generations"`
+	Stop        Stop         `json:"stop"`
+	Outputs     []OutputSink `json:"outputs,omitempty"`
+}
+
+// PatternPlacement puts a pattern into the universe.
+type PatternPlacement struct {
+	// Pattern is a catalogue name, an apgcode or the path of a pattern file.
+	Pattern string `json:"pattern"`
+	// Row and Col are where the top left corner of the pattern's bounding box goes.
+	Row int `json:"row,omitempty"`
+	Col int `json:"col,omitempty"`
+	// Centred puts the pattern in the middle of the universe instead.
+	Centred bool `json:"centred,omitempty"`
+}
+
+// OutputSink is a result of a run written to a file. Kind is one of
+//
+//	pattern     the last generation as a pattern file, in Format or by the extension
+//	render      the last generation drawn in Format (ansi, text or png), cells Scale pixels
+//	stats       statistics of every generation, CSV or JSON Lines by the extension
+//	checkpoint  a checkpoint every Every generations and at the end, in Format
+//	heatmap     the long-exposure PNG of the run, cells Scale pixels
+type OutputSink struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	Format string `json:"format,omitempty"`
+	Every  int    `json:"every,omitempty"`
+	Scale  int    `json:"scale,omitempty"`
+}
+
+// outputKinds are the kinds of OutputSink.
+var outputKinds = []string{"pattern", "render", "stats", "checkpoint", "heatmap"}
+
+// DefaultConfig returns the configuration of a run without any options: a blinker in a 5x5
+// torus under Conway's rule for 25 generations.
+func DefaultConfig() *Config {
+	return &Config{Rows: 5, Cols: 5, Topology: TopologyTorus.String(), Rules: "conway", Seed: Default.String(), Generations: 25}
+}
+
+// Validate reports the first problem with the configuration.
+func (c *Config) Validate() error {
+	if c.Rows <= 0 || c.Cols <= 0 {
+		return fmt.Errorf("config: rows and cols must be positive, got %dx%d", c.Rows, c.Cols)
+	}
+	if _, err := ParseTopology(c.Topology); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if _, err := ParseEngine(c.Engine); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if c.TilePeriod < 0 || c.TilePeriod > maxTilePeriod {
+		return fmt.Errorf("config: tile_period %d is not between 0 and %d", c.TilePeriod, maxTilePeriod)
+	}
+	if _, ok := c.lifeRules(); !ok {
+		_, stateErr := ParseStateRule(c.Rules)
+		_, blockErr := ParseBlockRule(c.Rules)
+		if stateErr != nil && blockErr != nil {
+			return fmt.Errorf("config: rules %q are not all rule names or rulestrings, available: %v", c.Rules, AvailableRuleNames())
+		}
+	}
+	if c.Seed != "" && c.Seed != Default.String() && c.Seed != Glider.String() {
+		return fmt.Errorf("config: unknown seed %q, want default or glider", c.Seed)
+	}
+	if c.Density < 0 || c.Density > 1 {
+		return fmt.Errorf("config: density %g is not between 0 and 1", c.Density)
+	}
+	for _, placement := range c.Patterns {
+		if placement.Pattern == "" {
+			return fmt.Errorf("config: a pattern placement names no pattern")
+		}
+	}
+	if c.Generations < 0 {
+		return fmt.Errorf("config: generations must not be negative")
+	}
+	if c.Stop.Period < 0 || c.Stop.MaxPopulation < 0 {
+		return fmt.Errorf("config: stop period and max_population must not be negative")
+	}
+	for _, sink := range c.Outputs {
+		known := false
+		for _, kind := range outputKinds {
+			known = known || sink.Kind == kind
+		}
+		if !known {
+			return fmt.Errorf("config: unknown output kind %q, want one of %v", sink.Kind, outputKinds)
+		}
+		if sink.Path == "" {
+			return fmt.Errorf("config: %s output has no path", sink.Kind)
+		}
+	}
+	return nil
+}
+
+// lifeRules returns the two-state rules named by Rules, and whether they all are.
+func (c *Config) lifeRules() ([]Rule, bool) {
+	rules := ParseRulesFromString(c.Rules)
+	return rules, len(rules) == len(strings.Split(c.Rules, ","))
+}
+
+// Build creates the universe the configuration describes, with its stop conditions set.
+// A single centred pattern is placed like CreateUniverseFromPattern, keeping its colours.
+// Multistate and block rules pass Validate but cannot be built into a GameOfLife.
+func (c *Config) Build() (*GameOfLife, error) {
+	rules, ok := c.lifeRules()
+	if !ok {
+		return nil, fmt.Errorf("config: rules %q are not two-state rules", c.Rules)
+	}
+	return c.BuildWith(rules...)
+}
+
+// BuildWith creates the universe the configuration describes, like Build, but following
+// the given rules instead of Rules, e.g. to wrap them in an AgeLimitRule.
+func (c *Config) BuildWith(rules ...Rule) (*GameOfLife, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	topology, _ := ParseTopology(c.Topology)
+	engine, _ := ParseEngine(c.Engine)
+	seed := Default
+	if c.Seed == Glider.String() {
+		seed = Glider
+	}
+
+	var g *GameOfLife
+	if len(c.Patterns) == 1 && c.Patterns[0].Centred && c.Density == 0 {
+		pattern, err := ResolvePattern(c.Patterns[0].Pattern)
+		if err != nil {
+			return nil, err
+		}
+		if g, err = CreateUniverseFromPattern(c.Rows, c.Cols, pattern, rules...); err != nil {
+			return nil, err
+		}
+		g.SetRandomSeed(c.RandomSeed)
+	} else {
+		g = CreateSeedUniverse(c.Rows, c.Cols, seed, rules...)
+		g.SetTopology(topology)
+		g.SetRandomSeed(c.RandomSeed)
+		if c.Density > 0 {
+			g.FillRandom(c.Density)
+		} else if len(c.Patterns) > 0 {
+			g.universe = make(map[Cell]struct{})
+		}
+		for _, placement := range c.Patterns {
+			if err := g.place(placement); err != nil {
+				return nil, err
+			}
+		}
+	}
+	g.SetTopology(topology)
+	g.SetEngine(engine)
+	g.SetTilePeriod(c.TilePeriod)
+	g.SetStop(c.Stop)
+	return g, nil
+}
+
+// place adds the live cells of a placed pattern to the universe. Cells beyond the edges
+// wrap around as the topology joins them; a pattern overhanging an edge that is not
+// joined is an error.
+func (g *GameOfLife) place(placement PatternPlacement) error {
+	pattern, err := ResolvePattern(placement.Pattern)
+	if err != nil {
+		return err
+	}
+	minCell, _, _ := pattern.Bounds()
+	offset := Cell{placement.Row - minCell.R, placement.Col - minCell.C}
+	if placement.Centred {
+		if offset, err = pattern.placement(g.numRows, g.numCols); err != nil {
+			return err
+		}
+	}
+	for cell := range pattern.Cells {
+		r, c := cell.R+offset.R, cell.C+offset.C
+		if g.topology == TopologyTorus {
+			g.universe[Cell{(r%g.numRows + g.numRows) % g.numRows, (c%g.numCols + g.numCols) % g.numCols}] = struct{}{}
+			continue
+		}
+		wrapped, ok := g.topology.wrap(Cell{r, c}, g.numRows, g.numCols)
+		if !ok || wrapped.R < 0 || wrapped.R >= g.numRows || wrapped.C < 0 || wrapped.C >= g.numCols {
+			return fmt.Errorf("config: pattern %q at row %d, column %d overhangs the edge of the %dx%d %s",
+				placement.Pattern, placement.Row, placement.Col, g.numRows, g.numCols, g.topology)
+		}
+		g.universe[wrapped] = struct{}{}
+	}
+	return nil
+}
+
+// ConfigFormat is the file format of a Config.
+type ConfigFormat int
+
+const (
+	// ConfigJSON is plain JSON.
+	ConfigJSON ConfigFormat = iota
+	// ConfigTOML is the subset of TOML with tables, arrays of tables, inline tables and
+	// single-line arrays.
+	ConfigTOML
+	// ConfigYAML is the subset of YAML with block mappings and sequences and flow
+	// sequences and mappings on a single line.
+	ConfigYAML
+)
+
+func (f ConfigFormat) String() string {
+	switch f {
+	case ConfigTOML:
+		return "toml"
+	case ConfigYAML:
+		return "yaml"
+	default:
+		return "json"
+	}
+}
+
+// ConfigFormatFromPath returns the config format implied by a file's extension, JSON
+// unless it is .toml, .yaml or .yml.
+func ConfigFormatFromPath(path string) ConfigFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return ConfigTOML
+	case ".yaml", ".yml":
+		return ConfigYAML
+	default:
+		return ConfigJSON
+	}
+}
+
+// ReadConfig reads a configuration in the given format. Fields left out keep the values of
+// DefaultConfig, and unknown fields are an error so that typos do not go unnoticed.
+func ReadConfig(r io.Reader, format ConfigFormat) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if format != ConfigJSON {
+		var tree any
+		if format == ConfigTOML {
+			tree, err = parseTOML(string(data))
+		} else {
+			tree, err = parseYAML(string(data))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s config: %w", format, err)
+		}
+		if data, err = json.Marshal(tree); err != nil {
+			return nil, err
+		}
+	}
+
+	c := DefaultConfig()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("reading %s config: %w", format, err)
+	}
+	// empty lists are left out when writing, so read them as none
+	if len(c.Patterns) == 0 {
+		c.Patterns = nil
+	}
+	if len(c.Outputs) == 0 {
+		c.Outputs = nil
+	}
+	return c, c.Validate()
+}
+
+// LoadConfig reads the configuration stored at path, in the format implied by its extension.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadConfig(f, ConfigFormatFromPath(path))
+}
+
+// WriteConfig writes the configuration in the given format, fields in a fixed order.
+func WriteConfig(w io.Writer, c *Config, format ConfigFormat) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format == ConfigJSON {
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	tree, err := orderedJSON(data)
+	if err != nil {
+		return err
+	}
+	var text string
+	if format == ConfigTOML {
+		text, err = formatTOML(tree)
+	} else {
+		text = formatYAML(tree)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, text)
+	return err
+}
@@ -0,0 +1,189 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfig_RoundTrip(t *testing.T) {
+	c := &Config{
+		Rows: 20, Cols: 30, Topology: "klein", Rules: "B36/S23", Engine: "tiled", TilePeriod: 6, RandomSeed: 7, Seed: "glider",
+		Density: 0.25,
+		Patterns: []PatternPlacement{
+			{Pattern: "glider", Row: 2, Col: 3},
+			{Pattern: "block", Centred: true},
+		},
+		Generations: 100,
+		Stop:        Stop{Extinct: true, Period: 4},
+		Outputs: []OutputSink{
+			{Kind: "stats", Path: "stats.csv"},
+			{Kind: "checkpoint", Path: "run.ckpt", Format: "binary", Every: 10},
+		},
+	}
+	for _, format := range []ConfigFormat{ConfigJSON, ConfigTOML, ConfigYAML} {
+		var buf bytes.Buffer
+		if err := WriteConfig(&buf, c, format); err != nil {
+			t.Fatalf("WriteConfig(%s): %v", format, err)
+		}
+		got, err := ReadConfig(&buf, format)
+		if err != nil {
+			t.Fatalf("ReadConfig(%s): %v\n%s", format, err, buf.String())
+		}
+		if !reflect.DeepEqual(got, c) {
+			t.Errorf("%s round trip = %+v; want %+v", format, got, c)
+		}
+	}
+
+	// the default config, with its empty stop table, survives too
+	for _, format := range []ConfigFormat{ConfigJSON, ConfigTOML, ConfigYAML} {
+		var buf bytes.Buffer
+		if err := WriteConfig(&buf, DefaultConfig(), format); err != nil {
+			t.Fatalf("WriteConfig(%s): %v", format, err)
+		}
+		if got, err := ReadConfig(&buf, format); err != nil || !reflect.DeepEqual(got, DefaultConfig()) {
+			t.Errorf("%s round trip of the default = %+v, %v", format, got, err)
+		}
+	}
+
+	// empty lists are not written, and read as none
+	for format, text := range map[ConfigFormat]string{
+		ConfigJSON: `{"patterns": [], "outputs": []}`,
+		ConfigYAML: "patterns: []\noutputs: []",
+	} {
+		got, err := ReadConfig(strings.NewReader(text), format)
+		if err != nil || got.Patterns != nil || got.Outputs != nil {
+			t.Errorf("ReadConfig(%s, %q) = %+v, %v; want no patterns and outputs", format, text, got, err)
+		}
+	}
+}
+
+func TestReadConfig_TOMLAndYAML(t *testing.T) {
+	want := DefaultConfig()
+	want.Rows, want.Cols = 16, 16
+	want.Rules = "B36/S23"
+	want.Stop = Stop{Stable: true, MaxPopulation: 200}
+	want.Patterns = []PatternPlacement{{Pattern: "glider", Row: 1, Col: 1}, {Pattern: "blinker", Row: 8, Col: 8}}
+	want.Outputs = []OutputSink{{Kind: "render", Path: "last.png", Format: "png", Scale: 4}}
+
+	toml := `# a glider and a blinker
+rows = 16
+cols = 16
+rules = "B36/S23"   # HighLife
+
+[stop]
+stable = true
+max_population = 200
+
+[[patterns]]
+pattern = "glider"
+row = 1
+col = 1
+
+[[patterns]]
+pattern = 'blinker'
+row = 8
+col = 8
+
+[[outputs]]
+kind = "render"
+path = "last.png"
+format = "png"
+scale = 4
+`
+	yaml := `# a glider and a blinker
+---
+rows: 16
+cols: 16
+rules: B36/S23
+stop: {stable: true, max_population: 200}
+patterns:
+- pattern: glider
+  row: 1
+  col: 1
+- pattern: "blinker"
+  row: 8
+  col: 8
+outputs:
+  - kind: render
+    path: last.png # next to the config
+    format: png
+    scale: 4
+`
+	for format, text := range map[ConfigFormat]string{ConfigTOML: toml, ConfigYAML: yaml} {
+		got, err := ReadConfig(strings.NewReader(text), format)
+		if err != nil {
+			t.Fatalf("ReadConfig(%s): %v", format, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadConfig(%s) = %+v; want %+v", format, got, want)
+		}
+	}
+}
+
+func TestReadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		format ConfigFormat
+		text   string
+	}{
+		{ConfigJSON, `{"rowz": 5}`},
+		{ConfigJSON, `{"rows": 0}`},
+		{ConfigJSON, `{"rules": "conway,nonsense"}`},
+		{ConfigJSON, `{"engine": "warp"}`},
+		{ConfigJSON, `{"tile_period": 64}`},
+		{ConfigJSON, `{"outputs": [{"kind": "movie", "path": "a.mp4"}]}`},
+		{ConfigTOML, "rules = conway"},
+		{ConfigTOML, "rows = 5\nrows = 6"},
+		{ConfigTOML, "[stop\nstable = true"},
+		{ConfigYAML, "stop:\n  stable: yes please"},
+		{ConfigYAML, "rows: 5\n  cols: 6"},
+	}
+	for _, tt := range tests {
+		if _, err := ReadConfig(strings.NewReader(tt.text), tt.format); err == nil {
+			t.Errorf("ReadConfig(%s, %q) succeeded; want an error", tt.format, tt.text)
+		}
+	}
+}
+
+func TestConfig_Build(t *testing.T) {
+	c := DefaultConfig()
+	c.Rows, c.Cols = 10, 10
+	c.Topology = "plane"
+	c.Engine = "active"
+	c.Patterns = []PatternPlacement{{Pattern: "block", Row: 0, Col: 0}, {Pattern: "blinker", Row: 9, Col: 7}}
+	c.Stop = Stop{Stable: true}
+	g, err := c.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Cell]struct{}{
+		{0, 0}: {}, {0, 1}: {}, {1, 0}: {}, {1, 1}: {},
+		{9, 7}: {}, {9, 8}: {}, {9, 9}: {},
+	}
+	if !maps.Equal(g.universe, want) {
+		t.Errorf("Build() universe = %v; want %v", g.universe, want)
+	}
+	if g.Topology() != TopologyPlane || g.Engine() != EngineActive || g.stop == nil {
+		t.Errorf("Build() topology = %s, engine = %s, stop = %v; want plane, active and a stop watcher", g.Topology(), g.Engine(), g.stop)
+	}
+
+	// a pattern overhanging an edge wraps only where the topology joins the edges
+	c.Patterns = []PatternPlacement{{Pattern: "blinker", Row: 9, Col: 8}}
+	if _, err := c.Build(); err == nil {
+		t.Errorf("Build() with a blinker overhanging the plane's edge succeeded; want an error")
+	}
+	c.Topology = "cylinder"
+	if g, err = c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[Cell]struct{}{{9, 8}: {}, {9, 9}: {}, {9, 0}: {}}; !maps.Equal(g.universe, want) {
+		t.Errorf("Build() universe on a cylinder = %v; want %v", g.universe, want)
+	}
+
+	c.Patterns = []PatternPlacement{{Pattern: "xq4_153", Centred: true}}
+	if g, err = c.Build(); err != nil || len(g.universe) != 5 {
+		t.Errorf("Build() with a centred glider = %v, %v; want 5 cells", g, err)
+	}
+}
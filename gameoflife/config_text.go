@@ -0,0 +1,620 @@
+package gameoflife
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Configs in TOML and YAML are parsed into the same tree encoding/json produces when
+// decoding into `any` (maps, slices, strings, numbers, booleans and nil) and then decoded
+// like JSON, so all three formats share Config's field names and checks. Only the subsets
+// of TOML and YAML needed for such trees are supported.
+
+// parseTOML parses the TOML subset of ConfigTOML: `key = value` lines, `[table]` and
+// `[[array.of.tables]]` headers, and strings, numbers, booleans, single-line arrays and
+// inline tables as values.
+func parseTOML(text string) (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(cutComment(line, false))
+		if line == "" {
+			continue
+		}
+		var err error
+		switch {
+		case strings.HasPrefix(line, "[["):
+			name, ok := strings.CutSuffix(line[2:], "]]")
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated array of tables %q", n+1, line)
+			}
+			current, err = tomlArrayTable(root, strings.TrimSpace(name))
+		case strings.HasPrefix(line, "["):
+			name, ok := strings.CutSuffix(line[1:], "]")
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated table %q", n+1, line)
+			}
+			current, err = tomlTable(root, strings.TrimSpace(name))
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: want key = value, got %q", n+1, line)
+			}
+			key = unquoteKey(strings.TrimSpace(key))
+			if _, exists := current[key]; exists {
+				return nil, fmt.Errorf("line %d: key %q defined twice", n+1, key)
+			}
+			var rest string
+			current[key], rest, err = parseFlowValue(strings.TrimSpace(value), '=')
+			if err == nil && strings.TrimSpace(rest) != "" {
+				err = fmt.Errorf("unexpected %q after value", rest)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+	}
+	return root, nil
+}
+
+// tomlTable returns the table with a dotted name, creating it and its parents as needed.
+// A name that refers to an array of tables means its last table.
+func tomlTable(root map[string]any, name string) (map[string]any, error) {
+	table := root
+	for _, part := range strings.Split(name, ".") {
+		part = unquoteKey(strings.TrimSpace(part))
+		switch child := table[part].(type) {
+		case nil:
+			next := make(map[string]any)
+			table[part] = next
+			table = next
+		case map[string]any:
+			table = child
+		case []any:
+			last, ok := child[len(child)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%q is an array of values, not tables", name)
+			}
+			table = last
+		default:
+			return nil, fmt.Errorf("%q is a value, not a table", name)
+		}
+	}
+	return table, nil
+}
+
+// tomlArrayTable appends a new table to the array of tables with a dotted name.
+func tomlArrayTable(root map[string]any, name string) (map[string]any, error) {
+	parent := root
+	key := name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		var err error
+		if parent, err = tomlTable(root, name[:i]); err != nil {
+			return nil, err
+		}
+		key = name[i+1:]
+	}
+	key = unquoteKey(strings.TrimSpace(key))
+	array, ok := parent[key].([]any)
+	if !ok && parent[key] != nil {
+		return nil, fmt.Errorf("%q is not an array of tables", name)
+	}
+	table := make(map[string]any)
+	parent[key] = append(array, table)
+	return table, nil
+}
+
+// parseYAML parses the YAML subset of ConfigYAML: block mappings and sequences nested by
+// indentation, with scalars, and flow sequences and mappings on a single line, as values.
+func parseYAML(text string) (any, error) {
+	p := &yamlParser{}
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(cutComment(line, true), " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", n+1)
+		}
+		p.lines = append(p.lines, yamlLine{number: n + 1, indent: len(line) - len(trimmed), text: trimmed})
+	}
+	if len(p.lines) == 0 {
+		return map[string]any{}, nil
+	}
+	tree, err := p.block(p.lines[0].indent)
+	if err == nil && p.pos < len(p.lines) {
+		err = fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].number)
+	}
+	return tree, err
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// yamlParser reads the lines of a YAML document one block at a time.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// block parses the sequence or mapping whose lines start at the given indentation.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSequenceItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		rest := strings.TrimLeft(line.text[1:], " ")
+		switch {
+		case rest == "":
+			p.pos++
+			var item any
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				var err error
+				if item, err = p.block(p.lines[p.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+			items = append(items, item)
+		case mappingKeyEnd(rest) >= 0:
+			// `- key: value` starts a mapping indented to where its first key is
+			itemIndent := indent + len(line.text) - len(rest)
+			p.lines[p.pos] = yamlLine{number: line.number, indent: itemIndent, text: rest}
+			item, err := p.mapping(itemIndent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		default:
+			item, err := yamlScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+			items = append(items, item)
+			p.pos++
+		}
+	}
+	return items, nil
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		end := mappingKeyEnd(line.text)
+		if end < 0 || isSequenceItem(line.text) {
+			return nil, fmt.Errorf("line %d: want key: value, got %q", line.number, line.text)
+		}
+		key := unquoteKey(strings.TrimSpace(line.text[:end]))
+		if _, exists := m[key]; exists {
+			return nil, fmt.Errorf("line %d: key %q defined twice", line.number, key)
+		}
+		value := strings.TrimSpace(line.text[end+1:])
+		p.pos++
+
+		if value != "" {
+			v, err := yamlScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+			m[key] = v
+			continue
+		}
+		// a nested block, or a sequence that may sit at the key's own indentation
+		m[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || next.indent == indent && isSequenceItem(next.text) {
+				v, err := p.block(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			}
+		}
+	}
+	return m, nil
+}
+
+// mappingKeyEnd returns the index of the colon that ends a mapping key, or -1 if the text
+// is not a `key: value` entry.
+func mappingKeyEnd(text string) int {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return -1
+	}
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		switch {
+		case quote != 0:
+			if text[i] == '\\' && quote == '"' {
+				i++
+			} else if text[i] == quote {
+				quote = 0
+			}
+		case text[i] == '"' || text[i] == '\'':
+			quote = text[i]
+		case text[i] == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// yamlScalar parses a whole value: a scalar, or a flow sequence or mapping.
+func yamlScalar(s string) (any, error) {
+	v, rest, err := parseFlowValue(s, ':')
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("unexpected %q after value", rest)
+	}
+	return v, nil
+}
+
+// parseFlowValue parses the value at the start of s and returns the rest: a quoted string,
+// an array `[a, b]`, an inline table `{k = v}` (TOML, assign '=') or flow mapping `{k: v}`
+// (YAML, assign ':'), or a bare word that is a boolean, a number, null in YAML, and
+// otherwise a string in YAML.
+func parseFlowValue(s string, assign byte) (any, string, error) {
+	s = strings.TrimLeft(s, " \t")
+	if s == "" {
+		return nil, "", fmt.Errorf("missing value")
+	}
+	switch s[0] {
+	case '"':
+		end := closingQuote(s)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string %s", s)
+		}
+		str, err := strconv.Unquote(s[:end+1])
+		return str, s[end+1:], err
+	case '\'':
+		if assign == ':' {
+			// YAML doubles single quotes inside single-quoted strings
+			for i := 1; i < len(s); i++ {
+				if s[i] == '\'' {
+					if i+1 < len(s) && s[i+1] == '\'' {
+						i++
+						continue
+					}
+					return strings.ReplaceAll(s[1:i], "''", "'"), s[i+1:], nil
+				}
+			}
+		} else if end := strings.IndexByte(s[1:], '\''); end >= 0 {
+			return s[1 : end+1], s[end+2:], nil
+		}
+		return nil, "", fmt.Errorf("unterminated string %s", s)
+	case '[':
+		items := []any{}
+		rest := s[1:]
+		for {
+			rest = strings.TrimLeft(rest, " \t")
+			if strings.HasPrefix(rest, "]") {
+				return items, rest[1:], nil
+			}
+			item, after, err := parseFlowValue(rest, assign)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			if rest, err = flowSeparator(after, ']'); err != nil {
+				return nil, "", err
+			}
+		}
+	case '{':
+		table := make(map[string]any)
+		rest := s[1:]
+		for {
+			rest = strings.TrimLeft(rest, " \t")
+			if strings.HasPrefix(rest, "}") {
+				return table, rest[1:], nil
+			}
+			i := strings.IndexByte(rest, assign)
+			if i < 0 {
+				return nil, "", fmt.Errorf("want key%c value in %s", assign, s)
+			}
+			key := unquoteKey(strings.TrimSpace(rest[:i]))
+			value, after, err := parseFlowValue(rest[i+1:], assign)
+			if err != nil {
+				return nil, "", err
+			}
+			table[key] = value
+			if rest, err = flowSeparator(after, '}'); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	end := strings.IndexAny(s, ",]}")
+	if end < 0 {
+		end = len(s)
+	}
+	word := strings.TrimSpace(s[:end])
+	switch word {
+	case "true":
+		return true, s[end:], nil
+	case "false":
+		return false, s[end:], nil
+	case "null", "~":
+		if assign == ':' {
+			return nil, s[end:], nil
+		}
+	}
+	number := strings.ReplaceAll(word, "_", "")
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return n, s[end:], nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, s[end:], nil
+	}
+	if assign == ':' {
+		return word, s[end:], nil
+	}
+	return nil, "", fmt.Errorf("cannot parse value %q; strings must be quoted", word)
+}
+
+// flowSeparator consumes the comma after an item of a flow collection, or leaves the
+// closing bracket for the caller.
+func flowSeparator(s string, closing byte) (string, error) {
+	s = strings.TrimLeft(s, " \t")
+	switch {
+	case strings.HasPrefix(s, ","):
+		return s[1:], nil
+	case s != "" && s[0] == closing:
+		return s, nil
+	default:
+		return "", fmt.Errorf("want ',' or '%c', got %q", closing, s)
+	}
+}
+
+// closingQuote returns the index of the quote that ends the double-quoted string at the
+// start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteKey removes the quotes around a quoted key.
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		if key[0] == '"' {
+			if unquoted, err := strconv.Unquote(key); err == nil {
+				return unquoted
+			}
+		}
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// cutComment removes a `#` comment from a line, ignoring `#` inside quotes. In YAML a
+// comment must start the line or follow a space.
+func cutComment(line string, yaml bool) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case quote != 0:
+			if line[i] == '\\' && quote == '"' {
+				i++
+			} else if line[i] == quote {
+				quote = 0
+			}
+		case line[i] == '"' || line[i] == '\'':
+			quote = line[i]
+		case line[i] == '#' && (!yaml || i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// orderedMap is a JSON object with its keys in their original order.
+type orderedMap []orderedEntry
+
+type orderedEntry struct {
+	key   string
+	value any
+}
+
+// orderedJSON decodes JSON into orderedMaps, slices and json.Number and other scalars,
+// keeping the order of object keys, so configs are written with fields in struct order.
+func orderedJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return readOrdered(dec)
+}
+
+func readOrdered(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		m := orderedMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, orderedEntry{key.(string), value})
+		}
+		_, err := dec.Token()
+		return m, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			item, err := readOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := dec.Token()
+		return items, err
+	}
+	return token, nil
+}
+
+// formatScalar writes a string, number or boolean in a form both TOML and YAML read.
+func formatScalar(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// isTableArray reports whether v is a non-empty array of objects.
+func isTableArray(v any) bool {
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		if _, ok := item.(orderedMap); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// formatTOML writes an ordered tree as TOML: the values of each table first, then its
+// tables and arrays of tables.
+func formatTOML(tree any) (string, error) {
+	root, ok := tree.(orderedMap)
+	if !ok {
+		return "", fmt.Errorf("TOML documents must be tables")
+	}
+	var b strings.Builder
+	err := writeTOMLTable(&b, "", root)
+	return b.String(), err
+}
+
+func writeTOMLTable(b *strings.Builder, name string, table orderedMap) error {
+	for _, entry := range table {
+		switch value := entry.value.(type) {
+		case nil:
+		case orderedMap:
+		case []any:
+			if isTableArray(value) {
+				continue
+			}
+			items := make([]string, len(value))
+			for i, item := range value {
+				if _, nested := item.(orderedMap); nested {
+					return fmt.Errorf("%s: arrays mixing tables and values are not supported", entry.key)
+				}
+				items[i] = formatScalar(item)
+			}
+			fmt.Fprintf(b, "%s = [%s]\n", entry.key, strings.Join(items, ", "))
+		default:
+			fmt.Fprintf(b, "%s = %s\n", entry.key, formatScalar(value))
+		}
+	}
+	for _, entry := range table {
+		path := entry.key
+		if name != "" {
+			path = name + "." + entry.key
+		}
+		switch value := entry.value.(type) {
+		case orderedMap:
+			fmt.Fprintf(b, "\n[%s]\n", path)
+			if err := writeTOMLTable(b, path, value); err != nil {
+				return err
+			}
+		case []any:
+			if !isTableArray(value) {
+				continue
+			}
+			for _, item := range value {
+				fmt.Fprintf(b, "\n[[%s]]\n", path)
+				if err := writeTOMLTable(b, path, item.(orderedMap)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// formatYAML writes an ordered tree as block YAML, with every string double-quoted.
+func formatYAML(tree any) string {
+	var b strings.Builder
+	writeYAML(&b, tree, 0)
+	return b.String()
+}
+
+func writeYAML(b *strings.Builder, v any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch value := v.(type) {
+	case orderedMap:
+		for _, entry := range value {
+			fmt.Fprintf(b, "%s%s:", pad, entry.key)
+			writeYAMLValue(b, entry.value, indent)
+		}
+	case []any:
+		for _, item := range value {
+			if m, ok := item.(orderedMap); ok && len(m) > 0 {
+				// the first key goes on the dash's line, the others line up with it
+				var item strings.Builder
+				writeYAML(&item, m, indent+2)
+				fmt.Fprintf(b, "%s- %s", pad, item.String()[indent+2:])
+				continue
+			}
+			fmt.Fprintf(b, "%s-", pad)
+			writeYAMLValue(b, item, indent)
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a mapping entry or sequence item after its key or dash.
+func writeYAMLValue(b *strings.Builder, v any, indent int) {
+	switch value := v.(type) {
+	case nil:
+		b.WriteString(" null\n")
+	case orderedMap:
+		if len(value) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, value, indent+2)
+	case []any:
+		if len(value) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, value, indent+2)
+	default:
+		fmt.Fprintf(b, " %s\n", formatScalar(value))
+	}
+}
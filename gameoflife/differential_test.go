@@ -0,0 +1,235 @@
+package gameoflife
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"testing"
+)
+
+// testEngines are the ways of stepping a universe that must agree with each other and with
+// referenceUniverse. Each one prepares a freshly created universe to be stepped by
+// CreateNextGeneration; the golden patterns and the differential tests run all of them.
+var testEngines = []struct {
+	name    string
+	prepare func(g *GameOfLife)
+}{
+	{"sparse", func(g *GameOfLife) {}},
+	{"active", func(g *GameOfLife) { g.SetEngine(EngineActive) }},
+	{"tiled", func(g *GameOfLife) { g.SetEngine(EngineTiled) }},
+	{"tiled-period-6", func(g *GameOfLife) {
+		g.SetEngine(EngineTiled)
+		g.SetTilePeriod(6)
+	}},
+}
+
+// referenceUniverse is a deliberately plain dense implementation of Life-like rules, and
+// optionally NoTopLeftNeighborRule, on every topology, written without sharing code with
+// the engines, to test them against.
+type referenceUniverse struct {
+	rows, cols int
+	topology   Topology
+	rules      []LifeLikeRule
+	noTopLeft  bool
+	alive      [][]bool
+}
+
+func newReferenceUniverse(rows, cols int, topology Topology, rules []LifeLikeRule, noTopLeft bool, cells map[Cell]struct{}) *referenceUniverse {
+	u := &referenceUniverse{rows: rows, cols: cols, topology: topology, rules: rules, noTopLeft: noTopLeft, alive: make([][]bool, rows)}
+	for r := range u.alive {
+		u.alive[r] = make([]bool, cols)
+	}
+	for cell := range cells {
+		u.alive[cell.R][cell.C] = true
+	}
+	return u
+}
+
+// at reports whether the cell at r, c is alive, where r and c may be one row or column
+// beyond the edges.
+func (u *referenceUniverse) at(r, c int) bool {
+	rowJoined := u.topology == TopologyTorus || u.topology == TopologyKlein
+	colJoined := u.topology != TopologyPlane
+	if (r < 0 || r >= u.rows) && !rowJoined || (c < 0 || c >= u.cols) && !colJoined {
+		return false
+	}
+	crossed := r < 0 || r >= u.rows
+	r, c = (r+u.rows)%u.rows, (c+u.cols)%u.cols
+	if crossed && u.topology == TopologyKlein {
+		c = u.cols - 1 - c
+	}
+	return u.alive[r][c]
+}
+
+func (u *referenceUniverse) step() {
+	next := make([][]bool, u.rows)
+	for r := range next {
+		next[r] = make([]bool, u.cols)
+		for c := range next[r] {
+			count := 0
+			for dr := -1; dr <= 1; dr++ {
+				for dc := -1; dc <= 1; dc++ {
+					if (dr != 0 || dc != 0) && u.at(r+dr, c+dc) {
+						count++
+					}
+				}
+			}
+			for _, rule := range u.rules {
+				if u.alive[r][c] && rule.Survival[count] || !u.alive[r][c] && rule.Birth[count] {
+					next[r][c] = true
+				}
+			}
+			// NoTopLeftNeighborRule keeps every live cell whose top-left neighbour is dead
+			if u.noTopLeft && u.alive[r][c] && !u.at(r-1, c-1) {
+				next[r][c] = true
+			}
+		}
+	}
+	u.alive = next
+}
+
+func (u *referenceUniverse) cells() map[Cell]struct{} {
+	cells := make(map[Cell]struct{})
+	for r, row := range u.alive {
+		for c, alive := range row {
+			if alive {
+				cells[Cell{r, c}] = struct{}{}
+			}
+		}
+	}
+	return cells
+}
+
+// lifeLikeRuleFromBits returns the rule with birth and survival on the neighbour counts
+// whose bits are set. Birth on 0 is left out, as ParseLifeLikeRule rejects it.
+func lifeLikeRuleFromBits(birth, survival uint16) LifeLikeRule {
+	var rule LifeLikeRule
+	for count := range 9 {
+		rule.Birth[count] = count > 0 && birth&(1<<count) != 0
+		rule.Survival[count] = survival&(1<<count) != 0
+	}
+	return rule
+}
+
+// checkEngines runs every engine and the reference side by side and fails at the first
+// generation where any of them differs. noTopLeft adds NoTopLeftNeighborRule to the rules.
+func checkEngines(t *testing.T, rows, cols int, topology Topology, rules []LifeLikeRule, noTopLeft bool, cells map[Cell]struct{}, generations int) {
+	t.Helper()
+	reference := newReferenceUniverse(rows, cols, topology, rules, noTopLeft, cells)
+	engineRules := make([]Rule, len(rules))
+	for i, rule := range rules {
+		engineRules[i] = rule
+	}
+	if noTopLeft {
+		engineRules = append(engineRules, NoTopLeftNeighborRule{})
+	}
+	universes := make([]*GameOfLife, len(testEngines))
+	for i, engine := range testEngines {
+		universes[i] = CreateSeedUniverse(rows, cols, Default, engineRules...)
+		universes[i].universe = maps.Clone(cells)
+		universes[i].SetTopology(topology)
+		engine.prepare(universes[i])
+	}
+
+	for generation := 1; generation <= generations; generation++ {
+		reference.step()
+		want := reference.cells()
+		for i, g := range universes {
+			g.CreateNextGeneration()
+			if !maps.Equal(g.universe, want) {
+				t.Fatalf("%s engine, %dx%d %s, rules %v (no-top-left %v): generation %d differs from the reference, %s",
+					testEngines[i].name, rows, cols, topology, rules, noTopLeft, generation, cellDiff(want, g.universe))
+			}
+		}
+	}
+}
+
+// randomCells fills a rows x cols universe at random with the given density.
+func randomCells(rng *rand.Rand, rows, cols int, density float64) map[Cell]struct{} {
+	cells := make(map[Cell]struct{})
+	for r := range rows {
+		for c := range cols {
+			if rng.Float64() < density {
+				cells[Cell{r, c}] = struct{}{}
+			}
+		}
+	}
+	return cells
+}
+
+func TestEngines_DifferentialTable(t *testing.T) {
+	conway, _ := ParseLifeLikeRule("B3/S23")
+	highLife, _ := ParseLifeLikeRule("B36/S23")
+	rng := rand.New(rand.NewPCG(3, 4))
+	tests := []struct {
+		name       string
+		rows, cols int
+		topology   Topology
+		rules      []LifeLikeRule
+		noTopLeft  bool
+	}{
+		{"conway-torus", 24, 24, TopologyTorus, []LifeLikeRule{conway}, false},
+		{"conway-plane", 24, 24, TopologyPlane, []LifeLikeRule{conway}, false},
+		{"highlife-cylinder", 20, 28, TopologyCylinder, []LifeLikeRule{highLife}, false},
+		{"conway-klein", 28, 20, TopologyKlein, []LifeLikeRule{conway}, false},
+		{"no-top-left-only", 20, 20, TopologyTorus, nil, true},
+		{"conway-no-top-left-torus", 24, 24, TopologyTorus, []LifeLikeRule{conway}, true},
+		{"conway-no-top-left-plane", 24, 24, TopologyPlane, []LifeLikeRule{conway}, true},
+		{"conway-no-top-left-klein", 20, 36, TopologyKlein, []LifeLikeRule{conway}, true},
+	}
+	for _, tt := range tests {
+		cells := randomCells(rng, tt.rows, tt.cols, 0.4)
+		t.Run(tt.name, func(t *testing.T) {
+			checkEngines(t, tt.rows, tt.cols, tt.topology, tt.rules, tt.noTopLeft, cells, 40)
+		})
+	}
+}
+
+func TestEngines_Differential(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	named := []LifeLikeRule{}
+	for _, rulestring := range []string{"B3/S23", "B36/S23", "B2/S", "B3678/S34678", "B1357/S1357", "B35678/S5678"} {
+		rule, err := ParseLifeLikeRule(rulestring)
+		if err != nil {
+			t.Fatal(err)
+		}
+		named = append(named, rule)
+	}
+
+	for i := range 200 {
+		rows, cols := 1+rng.IntN(20), 1+rng.IntN(20)
+		topology := Topologies()[rng.IntN(len(Topologies()))]
+		rules := []LifeLikeRule{named[rng.IntN(len(named))]}
+		switch rng.IntN(4) {
+		case 0:
+			rules[0] = lifeLikeRuleFromBits(uint16(rng.Uint32()), uint16(rng.Uint32()))
+		case 1:
+			rules = append(rules, lifeLikeRuleFromBits(uint16(rng.Uint32()), uint16(rng.Uint32())))
+		}
+		noTopLeft := rng.IntN(4) == 0
+		cells := randomCells(rng, rows, cols, rng.Float64())
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			checkEngines(t, rows, cols, topology, rules, noTopLeft, cells, 30)
+		})
+	}
+}
+
+func FuzzEngines(f *testing.F) {
+	f.Add(uint8(8), uint8(8), uint8(0), uint16(1<<3), uint16(1<<2|1<<3), uint8(20), []byte{0x02, 0x04, 0x07})
+	f.Add(uint8(1), uint8(1), uint8(3), uint16(0x1fe), uint16(0x1ff), uint8(5), []byte{0x01})
+	f.Add(uint8(5), uint8(3), uint8(3), uint16(1<<3|1<<6), uint16(1<<2|1<<3), uint8(30), []byte{0xff, 0x0f, 0xa5})
+	f.Add(uint8(9), uint8(7), uint8(0x80|1), uint16(1<<3), uint16(1<<2|1<<3), uint8(25), []byte{0x5a, 0xc3, 0x3c, 0x99})
+	f.Fuzz(func(t *testing.T, rows, cols, topology uint8, birth, survival uint16, generations uint8, bitmap []byte) {
+		r, c := 1+int(rows%24), 1+int(cols%24)
+		cells := make(map[Cell]struct{})
+		for i := range r * c {
+			if i/8 < len(bitmap) && bitmap[i/8]&(1<<(i%8)) != 0 {
+				cells[Cell{i / c, i % c}] = struct{}{}
+			}
+		}
+		rules := []LifeLikeRule{lifeLikeRuleFromBits(birth, survival)}
+		// the top bit of the topology byte adds NoTopLeftNeighborRule
+		noTopLeft := topology&0x80 != 0
+		checkEngines(t, r, c, Topologies()[int(topology&0x7f)%len(Topologies())], rules, noTopLeft, cells, int(generations%64))
+	})
+}
@@ -0,0 +1,334 @@
+package gameoflife
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// Engine is how CreateNextGeneration computes the next generation. Engines differ only in
+// speed: every engine gives the same generations.
+type Engine int
+
+const (
+	// EngineSparse counts the neighbours of every live cell in every generation, so a step
+	// costs the same however little of the universe changes. It is the default.
+	EngineSparse Engine = iota
+	// EngineActive cuts the universe into tiles and recomputes only the tiles next to ones
+	// that changed in the previous generation, so regions that have settled into still
+	// lifes cost nothing. It needs rules that look only at a cell's neighbourhood; under
+	// others, such as AgeLimitRule, it steps like EngineSparse.
+	EngineActive
+	// EngineTiled also skips tiles that repeat, like Golly's QuickLife: a tile whose
+	// surroundings went through the same cells p generations ago, for a period p up to the
+	// one set with SetTilePeriod, is copied from its own past instead of computed, so
+	// blinkers and other small oscillators in the ash cost little more than still lifes.
+	// It needs the same rules as EngineActive.
+	EngineTiled
+)
+
+// engineNames are the names of the engines, as used on the command line and in configs.
+var engineNames = map[Engine]string{
+	EngineSparse: "sparse",
+	EngineActive: "active",
+	EngineTiled:  "tiled",
+}
+
+func (e Engine) String() string {
+	if name, ok := engineNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("Engine(%d)", int(e))
+}
+
+// Engines returns every engine, the default first.
+func Engines() []Engine {
+	return []Engine{EngineSparse, EngineActive, EngineTiled}
+}
+
+// ParseEngine returns the engine with the given name. An empty name is the default.
+func ParseEngine(name string) (Engine, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return EngineSparse, nil
+	}
+	names := make([]string, 0, len(engineNames))
+	for _, e := range Engines() {
+		if engineNames[e] == name {
+			return e, nil
+		}
+		names = append(names, engineNames[e])
+	}
+	return EngineSparse, fmt.Errorf("unknown engine %q, want one of %s", name, strings.Join(names, ", "))
+}
+
+// Engine returns how the universe computes its generations.
+func (g *GameOfLife) Engine() Engine {
+	return g.engine
+}
+
+// SetEngine changes how the universe computes its generations from the next one on.
+func (g *GameOfLife) SetEngine(e Engine) {
+	g.engine = e
+	g.tiles = nil
+}
+
+// defaultTilePeriod is the longest period EngineTiled skips unless SetTilePeriod says
+// otherwise: blinkers, by far the most common oscillator in the ash of Life soups, have
+// period 2.
+const defaultTilePeriod = 2
+
+// maxTilePeriod is the longest period SetTilePeriod accepts.
+const maxTilePeriod = 63
+
+// TilePeriod returns the longest period EngineTiled skips repeating tiles with.
+func (g *GameOfLife) TilePeriod() int {
+	if g.tilePeriod == 0 {
+		return defaultTilePeriod
+	}
+	return g.tilePeriod
+}
+
+// SetTilePeriod sets the longest period, between 1 and 63, EngineTiled skips repeating
+// tiles with; 0 restores the default of 2. Longer periods also skip oscillators such as
+// pulsars (3) and pentadecathlons (15), at the cost of keeping more of each tile's past.
+func (g *GameOfLife) SetTilePeriod(period int) {
+	g.tilePeriod = min(max(period, 0), maxTilePeriod)
+	g.tiles = nil
+}
+
+// tileEngine is the state an engine other than EngineSparse keeps between generations.
+type tileEngine interface {
+	// describes reports whether the state still holds the universe's current generation.
+	describes(g *GameOfLife) bool
+	// step computes the next generation and returns it with the number of births and
+	// deaths.
+	step(g *GameOfLife) (newUniverse map[Cell]struct{}, births, deaths int)
+}
+
+// newTileEngine returns the state of the universe's engine, for its current generation.
+func (g *GameOfLife) newTileEngine() tileEngine {
+	if g.engine == EngineTiled {
+		return newTiledRegion(g)
+	}
+	return newActiveRegion(g)
+}
+
+// localRules reports whether the next state of a cell under the rules depends on nothing
+// but the cells around it, so a region none of whose cells changed cannot change either.
+func localRules(rules []Rule) bool {
+	for _, rule := range rules {
+		switch rule.(type) {
+		case TotalisticRule, NoTopLeftNeighborRule:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// tileSize is the side of the square tiles the engines track changes by. Smaller tiles
+// recompute less around a change but cost more to track.
+const tileSize = 16
+
+// tiling is what the engines working by tile share: the universe they last produced and
+// how its tiles lie next to each other.
+type tiling struct {
+	// universe is the generation the engine holds. Any other map, or a different number of
+	// cells, means the universe was replaced, e.g. by GoToGeneration, and the state is stale.
+	universe   map[Cell]struct{}
+	population int
+	rows, cols int
+	topology   Topology
+
+	// around caches the tiles within one cell of each tile
+	around map[Cell][]Cell
+}
+
+func newTiling(g *GameOfLife) tiling {
+	return tiling{
+		universe:   g.universe,
+		population: len(g.universe),
+		rows:       g.numRows,
+		cols:       g.numCols,
+		topology:   g.topology,
+		around:     make(map[Cell][]Cell),
+	}
+}
+
+// activeRegion is the state EngineActive keeps between generations: the live cells of the
+// universe grouped by tile, and which tiles changed in the last generation.
+type activeRegion struct {
+	tiling
+	tiles   map[Cell]map[Cell]struct{}
+	changed map[Cell]struct{}
+}
+
+// newActiveRegion groups the universe's cells by tile. Every tile with live cells counts
+// as changed, so the first step computes all of them.
+func newActiveRegion(g *GameOfLife) *activeRegion {
+	a := &activeRegion{
+		tiling:  newTiling(g),
+		tiles:   make(map[Cell]map[Cell]struct{}),
+		changed: make(map[Cell]struct{}),
+	}
+	for cell := range g.universe {
+		tile := tileOf(cell)
+		if a.tiles[tile] == nil {
+			a.tiles[tile] = make(map[Cell]struct{})
+			a.changed[tile] = struct{}{}
+		}
+		a.tiles[tile][cell] = struct{}{}
+	}
+	return a
+}
+
+// tileOf returns the tile a cell of the universe lies in.
+func tileOf(cell Cell) Cell {
+	return Cell{cell.R / tileSize, cell.C / tileSize}
+}
+
+// describes reports whether the engine still holds the universe's current generation.
+func (a *tiling) describes(g *GameOfLife) bool {
+	return len(g.universe) == a.population &&
+		reflect.ValueOf(g.universe).Pointer() == reflect.ValueOf(a.universe).Pointer() &&
+		a.rows == g.numRows && a.cols == g.numCols && a.topology == g.topology
+}
+
+// tilesAround returns the tiles holding a cell within one cell of tile t, following the
+// topology, t included.
+func (a *tiling) tilesAround(t Cell) []Cell {
+	if tiles, ok := a.around[t]; ok {
+		return tiles
+	}
+	top, left := t.R*tileSize, t.C*tileSize
+	// tiles on the last row or column may be cut short by the universe's edge
+	bottom, right := min(top+tileSize, a.rows), min(left+tileSize, a.cols)
+	seen := map[Cell]struct{}{t: {}}
+	tiles := []Cell{t}
+	for r := top - 1; r <= bottom; r++ {
+		for c := left - 1; c <= right; c++ {
+			if r >= top && r < bottom && c >= left && c < right {
+				continue
+			}
+			cell, ok := a.topology.wrap(Cell{r, c}, a.rows, a.cols)
+			if !ok {
+				continue
+			}
+			if tile := tileOf(cell); !has(seen, tile) {
+				seen[tile] = struct{}{}
+				tiles = append(tiles, tile)
+			}
+		}
+	}
+	a.around[t] = tiles
+	return tiles
+}
+
+// has reports whether a set of cells contains the cell.
+func has(set map[Cell]struct{}, cell Cell) bool {
+	_, ok := set[cell]
+	return ok
+}
+
+// step computes the next generation of the tiles next to a change and returns it with the
+// number of births and deaths. The universe's map is left alone: the next generation is a
+// copy, or the same map if nothing changed.
+func (a *activeRegion) step(g *GameOfLife) (newUniverse map[Cell]struct{}, births, deaths int) {
+	// a cell can only change if a cell within one of it changed in the last generation
+	dirty := make(map[Cell]struct{})
+	for tile := range a.changed {
+		for _, near := range a.tilesAround(tile) {
+			dirty[near] = struct{}{}
+		}
+	}
+	next := a.nextTiles(g, dirty, func(tile Cell) map[Cell]struct{} { return a.tiles[tile] })
+
+	newUniverse = g.universe
+	changed := make(map[Cell]struct{})
+	for tile := range dirty {
+		old, now := a.tiles[tile], next[tile]
+		if maps.Equal(old, now) {
+			continue
+		}
+		if len(changed) == 0 {
+			newUniverse = maps.Clone(g.universe)
+		}
+		changed[tile] = struct{}{}
+		born, died := replaceCells(newUniverse, old, now)
+		births, deaths = births+born, deaths+died
+		if len(now) == 0 {
+			delete(a.tiles, tile)
+		} else {
+			a.tiles[tile] = now
+		}
+	}
+	a.changed = changed
+	a.universe, a.population = newUniverse, len(newUniverse)
+	return newUniverse, births, deaths
+}
+
+// nextTiles computes the next generation of the given tiles, reading the live cells of
+// each tile with cellsOf, and returns the live cells of each tile that has any.
+func (a *tiling) nextTiles(g *GameOfLife, tiles map[Cell]struct{}, cellsOf func(tile Cell) map[Cell]struct{}) map[Cell]map[Cell]struct{} {
+	// the cells of the tiles have their live neighbours in these
+	sources := make(map[Cell]struct{}, len(tiles))
+	for tile := range tiles {
+		for _, near := range a.tilesAround(tile) {
+			sources[near] = struct{}{}
+		}
+	}
+
+	neighborCounts := make(map[Cell]int)
+	for tile := range sources {
+		for cell := range cellsOf(tile) {
+			for _, offset := range g.neighbouringCells {
+				if neighbourCell, ok := g.neighbour(cell, offset); ok && has(tiles, tileOf(neighbourCell)) {
+					neighborCounts[neighbourCell]++
+				}
+			}
+		}
+	}
+
+	next := make(map[Cell]map[Cell]struct{}, len(tiles))
+	live := func(cell Cell) {
+		tile := tileOf(cell)
+		if next[tile] == nil {
+			next[tile] = make(map[Cell]struct{})
+		}
+		next[tile][cell] = struct{}{}
+	}
+	for cell, neighborCount := range neighborCounts {
+		if g.nextState(cell, has(cellsOf(tileOf(cell)), cell), neighborCount) {
+			live(cell)
+		}
+	}
+	// live cells without live neighbours were not counted
+	for tile := range tiles {
+		for cell := range cellsOf(tile) {
+			if _, counted := neighborCounts[cell]; !counted && g.nextState(cell, true, 0) {
+				live(cell)
+			}
+		}
+	}
+	return next
+}
+
+// replaceCells replaces the old live cells of a tile in the universe with the new ones and
+// returns how many cells were born and died.
+func replaceCells(universe, old, now map[Cell]struct{}) (births, deaths int) {
+	for cell := range old {
+		if !has(now, cell) {
+			delete(universe, cell)
+			deaths++
+		}
+	}
+	for cell := range now {
+		if !has(old, cell) {
+			universe[cell] = struct{}{}
+			births++
+		}
+	}
+	return births, deaths
+}
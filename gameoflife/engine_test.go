@@ -0,0 +1,164 @@
+package gameoflife
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseEngine(t *testing.T) {
+	for _, engine := range Engines() {
+		if parsed, err := ParseEngine(engine.String()); err != nil || parsed != engine {
+			t.Errorf("ParseEngine(%q) = %v, %v", engine, parsed, err)
+		}
+	}
+	if engine, err := ParseEngine(""); err != nil || engine != EngineSparse {
+		t.Errorf("ParseEngine(\"\") = %v, %v; want the sparse engine", engine, err)
+	}
+	if _, err := ParseEngine("hashlife"); err == nil {
+		t.Errorf("ParseEngine(hashlife) succeeded; want an error")
+	}
+}
+
+func TestActiveEngine_SkipsSettledTiles(t *testing.T) {
+	g := CreateSeedUniverse(96, 96, Default, ConwayRule{})
+	g.universe = stabilisedSoup(96, 96, 0)
+	blinker := Cell{34, 36}
+	for _, cell := range []Cell{blinker, {35, 36}, {36, 36}} {
+		g.universe[cell] = struct{}{}
+	}
+	g.SetEngine(EngineActive)
+	want := maps.Clone(g.universe)
+
+	for range 4 {
+		g.CreateNextGeneration()
+	}
+	if !maps.Equal(g.universe, want) {
+		t.Fatalf("the soup and blinker did not return after 4 generations, %s", cellDiff(want, g.universe))
+	}
+	// only the blinker's tile changes; the tiles of the still lifes are skipped
+	if len(g.tiles.(*activeRegion).changed) != 1 || !has(g.tiles.(*activeRegion).changed, tileOf(blinker)) {
+		t.Errorf("changed tiles = %v; want only the blinker's tile %v", g.tiles.(*activeRegion).changed, tileOf(blinker))
+	}
+	if births, deaths := g.lastBirths, g.lastDeaths; births != 2 || deaths != 2 {
+		t.Errorf("births, deaths = %d, %d; want 2, 2", births, deaths)
+	}
+}
+
+func TestTileEngines_FollowReplacedUniverse(t *testing.T) {
+	for _, engine := range []Engine{EngineActive, EngineTiled} {
+		g := CreateSeedUniverse(20, 20, Glider, ConwayRule{})
+		g.SetEngine(engine)
+		g.EnableHistory(4, 0)
+		want := []map[Cell]struct{}{maps.Clone(g.universe)}
+		for range 8 {
+			g.CreateNextGeneration()
+			want = append(want, maps.Clone(g.universe))
+		}
+
+		// rewinding swaps in another universe, which the engine must notice
+		if err := g.GoToGeneration(3); err != nil {
+			t.Fatalf("%s: GoToGeneration(3) error = %v", engine, err)
+		}
+		for generation := 4; generation <= 8; generation++ {
+			g.CreateNextGeneration()
+			if !maps.Equal(g.universe, want[generation]) {
+				t.Fatalf("%s: generation %d after rewinding differs, %s", engine, generation, cellDiff(want[generation], g.universe))
+			}
+		}
+
+		// as must changing the topology
+		g.SetTopology(TopologyPlane)
+		g.universe = map[Cell]struct{}{{0, 4}: {}, {0, 5}: {}, {0, 6}: {}}
+		g.CreateNextGeneration()
+		if want := map[Cell]struct{}{{0, 5}: {}, {1, 5}: {}}; !maps.Equal(g.universe, want) {
+			t.Errorf("%s: blinker on the plane's edge = %v; want %v", engine, g.universe, want)
+		}
+	}
+}
+
+func TestTiledEngine_SkipsRepeatingTiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		period   int
+		computed bool
+	}{
+		{"blinkers", "", 0, false},
+		{"blinkers without skipping", "", 1, true},
+		{"pulsar", "pulsar", 3, false},
+		{"pulsar with the default period", "pulsar", 0, true},
+	}
+	for _, tt := range tests {
+		g := CreateSeedUniverse(96, 96, Default, ConwayRule{})
+		g.universe = stabilisedSoup(96, 96, 3)
+		if tt.pattern != "" {
+			pattern, err := ResolvePattern(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g.universe = translateCells(pattern.Cells, Cell{40, 40}, 96, 96)
+		}
+		g.SetEngine(EngineTiled)
+		g.SetTilePeriod(tt.period)
+		want := maps.Clone(g.universe)
+
+		// the first generations are computed while the tiles' histories fill up
+		for range 6 {
+			g.CreateNextGeneration()
+		}
+		if !maps.Equal(g.universe, want) {
+			t.Fatalf("%s: generation 6 differs from the start, %s", tt.name, cellDiff(want, g.universe))
+		}
+		if computed := g.tiles.(*tiledRegion).computed; computed > 0 != tt.computed {
+			t.Errorf("%s: %d tiles computed in generation 6; want computed %v", tt.name, computed, tt.computed)
+		}
+	}
+}
+
+func TestTiledEngine_LeavesGivenUniverseAlone(t *testing.T) {
+	// the engine changes only maps it made itself in place
+	given := map[Cell]struct{}{{4, 3}: {}, {4, 4}: {}, {4, 5}: {}}
+	g := CreateSeedUniverse(10, 10, Default, ConwayRule{})
+	g.universe = given
+	g.SetEngine(EngineTiled)
+	for range 3 {
+		g.CreateNextGeneration()
+	}
+	if want := map[Cell]struct{}{{4, 3}: {}, {4, 4}: {}, {4, 5}: {}}; !maps.Equal(given, want) {
+		t.Errorf("stepping changed the map the universe was given to %v", given)
+	}
+	if want := map[Cell]struct{}{{3, 4}: {}, {4, 4}: {}, {5, 4}: {}}; !maps.Equal(g.universe, want) {
+		t.Errorf("generation 3 = %v; want %v", g.universe, want)
+	}
+}
+
+func TestSetTilePeriod(t *testing.T) {
+	g := CreateSeedUniverse(5, 5, Default, ConwayRule{})
+	for _, tt := range []struct{ set, want int }{{0, 2}, {15, 15}, {-1, 2}, {100, 63}} {
+		if g.SetTilePeriod(tt.set); g.TilePeriod() != tt.want {
+			t.Errorf("SetTilePeriod(%d) made TilePeriod() %d; want %d", tt.set, g.TilePeriod(), tt.want)
+		}
+	}
+}
+
+func TestActiveEngine_NonLocalRulesStepSparse(t *testing.T) {
+	sparse := CreateSeedUniverse(8, 8, Default, AgeLimitRule{Rule: ConwayRule{}, MaxAge: 3})
+	sparse.universe = map[Cell]struct{}{{3, 3}: {}, {3, 4}: {}, {4, 3}: {}, {4, 4}: {}}
+	sparse.EnableAgeTracking()
+	active := CreateSeedUniverse(8, 8, Default, AgeLimitRule{Rule: ConwayRule{}, MaxAge: 3})
+	active.universe = maps.Clone(sparse.universe)
+	active.EnableAgeTracking()
+	active.SetEngine(EngineActive)
+
+	// the block dies of old age although nothing around it changes
+	for generation := 1; generation <= 4; generation++ {
+		sparse.CreateNextGeneration()
+		active.CreateNextGeneration()
+		if !maps.Equal(active.universe, sparse.universe) {
+			t.Fatalf("generation %d = %v; want %v", generation, active.universe, sparse.universe)
+		}
+	}
+	if len(active.universe) != 0 || active.tiles != nil {
+		t.Errorf("universe = %v with engine state %v; want the block dead without engine state", active.universe, active.tiles)
+	}
+}
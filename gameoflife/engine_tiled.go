@@ -0,0 +1,219 @@
+package gameoflife
+
+import (
+	"maps"
+	"math"
+	"math/bits"
+)
+
+// tilePhase is the live cells of a tile from a generation on. Phases of a tile with the
+// same id hold the same cells, so comparing a tile across generations is comparing ids.
+// Id 0 is the empty tile.
+type tilePhase struct {
+	since int
+	id    int
+	cells map[Cell]struct{}
+}
+
+// tileHistory is the recent past of a tile, oldest phase first: enough to know its cells
+// in each of the last generations up to the longest period skipped. Phases are never
+// changed once made, so the same cells can be shared by many generations.
+type tileHistory struct {
+	phases []tilePhase
+	lastID int
+}
+
+// newTileHistory returns the history of a tile that has been empty from the start.
+func newTileHistory() *tileHistory {
+	return &tileHistory{phases: []tilePhase{{}}}
+}
+
+func (h *tileHistory) current() tilePhase {
+	return h.phases[len(h.phases)-1]
+}
+
+// at returns the phase of the tile in a generation, and false if the history does not go
+// back that far.
+func (h *tileHistory) at(generation int) (tilePhase, bool) {
+	for i := len(h.phases) - 1; i >= 0; i-- {
+		if h.phases[i].since <= generation {
+			return h.phases[i], true
+		}
+	}
+	return tilePhase{}, false
+}
+
+// phaseOf returns the phase with the given cells, with the id of a remembered phase with
+// the same cells if there is one.
+func (h *tileHistory) phaseOf(cells map[Cell]struct{}) tilePhase {
+	if len(cells) == 0 {
+		return tilePhase{}
+	}
+	for _, phase := range h.phases {
+		if phase.id != 0 && maps.Equal(phase.cells, cells) {
+			return phase
+		}
+	}
+	h.lastID++
+	return tilePhase{id: h.lastID, cells: cells}
+}
+
+// record makes phase the current one from its generation on, forgetting phases that ended
+// more than period generations before.
+func (h *tileHistory) record(phase tilePhase, period int) {
+	h.phases = append(h.phases, phase)
+	drop := 0
+	for drop < len(h.phases)-1 && h.phases[drop+1].since <= phase.since-period {
+		drop++
+	}
+	h.phases = append(h.phases[:0], h.phases[drop:]...)
+}
+
+// anyPeriod are the periods, as bit p for period p, a tile that stays empty repeats with.
+const anyPeriod = math.MaxUint64
+
+// tiledRegion is the state EngineTiled keeps between generations: the recent past of every
+// tile, and which tiles changed in the last generation.
+//
+// A tile's next generation depends only on its cells and those just around it, which lie
+// in the tiles around it. So if every tile around a tile, itself included, is the same as
+// p generations ago, the tile's next generation is the same as p generations ago too and
+// can be copied from its history. Tiles none of whose surroundings changed (p = 1) are
+// not even looked at, as with EngineActive.
+type tiledRegion struct {
+	tiling
+	period     int
+	generation int
+	tiles      map[Cell]*tileHistory
+	changed    map[Cell]struct{}
+	// owned is whether the universe's map was made by the engine, which then changes it
+	// in place when nothing needs the previous generation
+	owned bool
+	// computed is how many tiles the last step computed rather than copied or skipped
+	computed int
+}
+
+// newTiledRegion starts the history of every tile at the universe's current generation.
+// Every tile with live cells counts as changed, so the first step computes all of them.
+func newTiledRegion(g *GameOfLife) *tiledRegion {
+	a := &tiledRegion{
+		tiling:  newTiling(g),
+		period:  g.TilePeriod(),
+		tiles:   make(map[Cell]*tileHistory),
+		changed: make(map[Cell]struct{}),
+	}
+	for cell := range g.universe {
+		tile := tileOf(cell)
+		if a.tiles[tile] == nil {
+			a.tiles[tile] = &tileHistory{phases: []tilePhase{{id: 1, cells: make(map[Cell]struct{})}}, lastID: 1}
+			a.changed[tile] = struct{}{}
+		}
+		a.tiles[tile].phases[0].cells[cell] = struct{}{}
+	}
+	return a
+}
+
+// cellsOf returns the live cells of a tile in the current generation.
+func (a *tiledRegion) cellsOf(tile Cell) map[Cell]struct{} {
+	if h := a.tiles[tile]; h != nil {
+		return h.current().cells
+	}
+	return nil
+}
+
+// repeats returns the periods, as bit p for period p, with which the tile's cells now are
+// the same as p generations ago. Tiles that have been empty for longer than the longest
+// period are forgotten.
+func (a *tiledRegion) repeats(tile Cell) uint64 {
+	h := a.tiles[tile]
+	if h == nil {
+		return anyPeriod
+	}
+	now := h.current()
+	if now.id == 0 && now.since <= a.generation-a.period {
+		delete(a.tiles, tile)
+		return anyPeriod
+	}
+	var periods uint64
+	for p := 1; p <= a.period; p++ {
+		if then, ok := h.at(a.generation - p); ok && then.id == now.id {
+			periods |= 1 << p
+		}
+	}
+	return periods
+}
+
+// step computes the tiles next to a change that do not repeat, copies those that do, and
+// returns the next generation with the number of births and deaths.
+func (a *tiledRegion) step(g *GameOfLife) (newUniverse map[Cell]struct{}, births, deaths int) {
+	// a cell can only change if a cell within one of it changed in the last generation
+	dirty := make(map[Cell]struct{})
+	for tile := range a.changed {
+		for _, near := range a.tilesAround(tile) {
+			dirty[near] = struct{}{}
+		}
+	}
+
+	repeats := make(map[Cell]uint64)
+	next := make(map[Cell]tilePhase, len(dirty))
+	compute := make(map[Cell]struct{})
+	for tile := range dirty {
+		periods := uint64(anyPeriod)
+		for _, near := range a.tilesAround(tile) {
+			r, ok := repeats[near]
+			if !ok {
+				r = a.repeats(near)
+				repeats[near] = r
+			}
+			periods &= r
+		}
+		if periods &^= 1; periods == 0 {
+			compute[tile] = struct{}{}
+			continue
+		}
+		// the surroundings repeat with period p, so the tile's next generation does too
+		p := bits.TrailingZeros64(periods)
+		if h := a.tiles[tile]; h != nil {
+			next[tile], _ = h.at(a.generation + 1 - p)
+		} else {
+			next[tile] = tilePhase{}
+		}
+	}
+	a.computed = len(compute)
+	for tile, cells := range a.nextTiles(g, compute, a.cellsOf) {
+		h := a.tiles[tile]
+		if h == nil {
+			h = newTileHistory()
+			a.tiles[tile] = h
+		}
+		next[tile] = h.phaseOf(cells)
+	}
+	a.generation++
+
+	// the universe is changed in place unless its map is someone else's or the previous
+	// generation is needed, by history, colours or ages
+	inPlace := a.owned && g.history == nil && g.colours == nil && g.ages == nil
+	changed := make(map[Cell]struct{})
+	for tile := range dirty {
+		h, phase := a.tiles[tile], next[tile]
+		old := tilePhase{}
+		if h != nil {
+			old = h.current()
+		}
+		if phase.id == old.id {
+			continue
+		}
+		if len(changed) == 0 && !inPlace {
+			a.universe, a.owned = maps.Clone(a.universe), true
+		}
+		changed[tile] = struct{}{}
+		born, died := replaceCells(a.universe, old.cells, phase.cells)
+		births, deaths = births+born, deaths+died
+		// tiles that were not tracked stay empty or were computed, which tracks them
+		phase.since = a.generation
+		h.record(phase, a.period)
+	}
+	a.changed = changed
+	a.population = len(a.universe)
+	return a.universe, births, deaths
+}
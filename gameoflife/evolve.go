@@ -0,0 +1,178 @@
+package gameoflife
+
+import "slices"
+
+// ObjectKind is the long-term behaviour of an isolated object.
+type ObjectKind int
+
+const (
+	// KindUnknown objects did not repeat within the examined number of generations.
+	KindUnknown ObjectKind = iota
+	// KindStillLife objects never change.
+	KindStillLife
+	// KindOscillator objects return to their initial state in place after some period.
+	KindOscillator
+	// KindSpaceship objects return to their initial shape, translated, after some period.
+	KindSpaceship
+	// KindExtinct objects die out completely.
+	KindExtinct
+)
+
+func (k ObjectKind) String() string {
+	switch k {
+	case KindStillLife:
+		return "still life"
+	case KindOscillator:
+		return "oscillator"
+	case KindSpaceship:
+		return "spaceship"
+	case KindExtinct:
+		return "extinct"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the kind by name, so it reads well in JSON.
+func (k ObjectKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Behaviour describes how an isolated pattern evolves.
+type Behaviour struct {
+	Kind   ObjectKind `json:"kind"`
+	Period int        `json:"period"`
+	// Displacement is how far a spaceship moves in one period.
+	Displacement Cell `json:"displacement"`
+}
+
+// evolveAlone runs a pattern on its own, away from everything else and from the
+// universe borders, and returns generations 0..generations in the pattern's coordinates.
+func evolveAlone(cells map[Cell]struct{}, rules []Rule, generations int) []map[Cell]struct{} {
+	phases := make([]map[Cell]struct{}, 0, generations+1)
+	phases = append(phases, cells)
+	step := isolatedStepper(cells, rules, generations)
+	for range generations {
+		phases = append(phases, step())
+	}
+	return phases
+}
+
+// isolatedStepper places the pattern in a scratch universe with enough room to run for
+// the given number of generations without wrapping, and returns a function that
+// advances it one generation and returns the new generation in the pattern's coordinates.
+func isolatedStepper(cells map[Cell]struct{}, rules []Rule, generations int) func() map[Cell]struct{} {
+	minCell, height, width := boundingBox(cells)
+	// a pattern grows by at most one cell per side and generation
+	margin := generations + 2
+	scratch := CreateSeedUniverse(height+2*margin, width+2*margin, Default, rules...)
+	scratch.universe = make(map[Cell]struct{}, len(cells))
+	offset := Cell{margin - minCell.R, margin - minCell.C}
+	for cell := range cells {
+		scratch.universe[Cell{cell.R + offset.R, cell.C + offset.C}] = struct{}{}
+	}
+
+	return func() map[Cell]struct{} {
+		scratch.CreateNextGeneration()
+		phase := make(map[Cell]struct{}, len(scratch.universe))
+		for cell := range scratch.universe {
+			phase[Cell{cell.R - offset.R, cell.C - offset.C}] = struct{}{}
+		}
+		return phase
+	}
+}
+
+// Classify finds how a pattern behaves on its own on an unbounded plane, looking for
+// periods up to maxPeriod. Patterns that do not repeat within maxPeriod generations are
+// KindUnknown.
+func Classify(cells map[Cell]struct{}, maxPeriod int, rules ...Rule) Behaviour {
+	return classifyAlone(cells, rules, maxPeriod)
+}
+
+// classifyAlone finds the behaviour of a pattern run on its own for up to maxPeriod generations.
+func classifyAlone(cells map[Cell]struct{}, rules []Rule, maxPeriod int) Behaviour {
+	if len(cells) == 0 {
+		return Behaviour{Kind: KindExtinct}
+	}
+
+	step := isolatedStepper(cells, rules, maxPeriod)
+	start := normalisedCells(cells)
+	startMin, _, _ := boundingBox(cells)
+	for period := 1; period <= maxPeriod; period++ {
+		phase := step()
+		if len(phase) == 0 {
+			return Behaviour{Kind: KindExtinct, Period: period}
+		}
+		if len(phase) != len(cells) || !slices.Equal(normalisedCells(phase), start) {
+			continue
+		}
+
+		phaseMin, _, _ := boundingBox(phase)
+		displacement := Cell{phaseMin.R - startMin.R, phaseMin.C - startMin.C}
+		switch {
+		case displacement != Cell{}:
+			return Behaviour{Kind: KindSpaceship, Period: period, Displacement: displacement}
+		case period == 1:
+			return Behaviour{Kind: KindStillLife, Period: 1}
+		default:
+			return Behaviour{Kind: KindOscillator, Period: period}
+		}
+	}
+	return Behaviour{Kind: KindUnknown}
+}
+
+// normalisedCells returns the cells shifted so their bounding box starts at the origin,
+// sorted row-major.
+func normalisedCells(cells map[Cell]struct{}) []Cell {
+	minCell, _, _ := boundingBox(cells)
+	normalised := make([]Cell, 0, len(cells))
+	for cell := range cells {
+		normalised = append(normalised, Cell{cell.R - minCell.R, cell.C - minCell.C})
+	}
+	slices.SortFunc(normalised, compareCells)
+	return normalised
+}
+
+// symmetries are the 8 rotations and reflections of the square, as functions on a cell.
+var symmetries = []func(Cell) Cell{
+	func(c Cell) Cell { return Cell{c.R, c.C} },
+	func(c Cell) Cell { return Cell{c.C, -c.R} },
+	func(c Cell) Cell { return Cell{-c.R, -c.C} },
+	func(c Cell) Cell { return Cell{-c.C, c.R} },
+	func(c Cell) Cell { return Cell{c.R, -c.C} },
+	func(c Cell) Cell { return Cell{-c.R, c.C} },
+	func(c Cell) Cell { return Cell{c.C, c.R} },
+	func(c Cell) Cell { return Cell{-c.C, -c.R} },
+}
+
+// transformCells applies a symmetry to every cell.
+func transformCells(cells map[Cell]struct{}, symmetry func(Cell) Cell) map[Cell]struct{} {
+	transformed := make(map[Cell]struct{}, len(cells))
+	for cell := range cells {
+		transformed[symmetry(cell)] = struct{}{}
+	}
+	return transformed
+}
+
+// shapeKey returns a string identifying the shape of the cells, ignoring position.
+func shapeKey(cells map[Cell]struct{}) string {
+	normalised := normalisedCells(cells)
+	key := make([]byte, 0, 4*len(normalised))
+	for _, cell := range normalised {
+		key = append(key, byte(cell.R), byte(cell.R>>8), byte(cell.C), byte(cell.C>>8))
+	}
+	return string(key)
+}
+
+// canonicalShapeKey returns the smallest shapeKey over all 8 symmetries, so that
+// rotated and reflected copies of a shape share the same key.
+func canonicalShapeKey(cells map[Cell]struct{}) string {
+	best := ""
+	for i, symmetry := range symmetries {
+		key := shapeKey(transformCells(cells, symmetry))
+		if i == 0 || key < best {
+			best = key
+		}
+	}
+	return best
+}
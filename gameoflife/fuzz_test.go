@@ -0,0 +1,222 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// The fuzz targets below check that the parsers never panic or hang on any input, and
+// that whatever they accept survives being written and read back. Inputs that once
+// failed are kept in testdata/fuzz and run by every `go test`.
+
+// fuzzMaxInput bounds the inputs fuzzed, so that valid but huge patterns do not dominate.
+const fuzzMaxInput = 4096
+
+func FuzzReadPattern(f *testing.F) {
+	for _, seed := range []struct {
+		format PatternFormat
+		text   string
+	}{
+		{FormatRLE, "#N glider\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"},
+		{FormatRLE, "x = 3, y = 1, rule = B3/S23\n2.A!\n"},
+		{FormatPlaintext, "!Name: blinker\nOOO\n"},
+		{FormatLife105, "#Life 1.05\n#P -1 -1\n.*.\n..*\n***\n"},
+		{FormatLife106, "#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n"},
+		{FormatMacrocell, "[M2] (golly 2.0)\n#R B3/S23\n.*$..*$***$\n"},
+	} {
+		if _, err := ReadPattern(strings.NewReader(seed.text), seed.format); err != nil {
+			f.Fatalf("seed %q does not read as %s: %v", seed.text, seed.format, err)
+		}
+		// the fuzzed byte picks format 1 + byte % 5
+		f.Add(uint8(seed.format-1), []byte(seed.text))
+	}
+	f.Fuzz(func(t *testing.T, formatNumber uint8, data []byte) {
+		if len(data) > fuzzMaxInput {
+			t.Skip()
+		}
+		format := PatternFormat(1 + int(formatNumber)%5)
+		p, err := ReadPattern(bytes.NewReader(data), format)
+		if err != nil || len(p.Cells) > 1<<16 {
+			return
+		}
+		if _, height, width := p.Bounds(); height*width > 1<<20 && format != FormatLife106 && format != FormatMacrocell {
+			return // drawn formats would write a huge grid
+		}
+
+		var buf bytes.Buffer
+		if err := WritePattern(&buf, p, format); err != nil {
+			t.Fatalf("writing a pattern read as %s: %v", format, err)
+		}
+		again, err := ReadPattern(bytes.NewReader(buf.Bytes()), format)
+		if err != nil {
+			t.Fatalf("reading back a pattern written as %s: %v\n%s", format, err, buf.String())
+		}
+		if !slices.Equal(normalisedCells(again.Cells), normalisedCells(p.Cells)) {
+			t.Fatalf("%s round trip changed the cells, %s", format, cellDiff(p.Cells, again.Cells))
+		}
+		if got, want := normalisedStates(again), normalisedStates(p); !maps.Equal(got, want) {
+			t.Fatalf("%s round trip changed the states to %v; want %v", format, got, want)
+		}
+	})
+}
+
+// normalisedStates returns the states of a pattern's live cells moved like
+// normalisedCells moves them, or nil for a two-state pattern.
+func normalisedStates(p *Pattern) map[Cell]int {
+	if p.States == nil {
+		return nil
+	}
+	minCell, _, _ := boundingBox(p.Cells)
+	states := make(map[Cell]int, len(p.Cells))
+	for cell := range p.Cells {
+		states[Cell{cell.R - minCell.R, cell.C - minCell.C}] = p.state(cell)
+	}
+	return states
+}
+
+func FuzzDecodeApgcode(f *testing.F) {
+	for _, code := range []string{"xs4_33", "xq4_153", "xp2_7", "xp15_4r4z4r4", "xs0_", "y2_0"} {
+		f.Add(code)
+	}
+	f.Fuzz(func(t *testing.T, code string) {
+		if len(code) > fuzzMaxInput {
+			t.Skip()
+		}
+		p, err := DecodeApgcode(code)
+		if err != nil || len(p.Cells) == 0 {
+			return
+		}
+		cells := normalisedCells(p.Cells)
+		decoded, err := decodeWechsler(wechslerCode(cells))
+		if err != nil || !slices.Equal(normalisedCells(decoded), cells) {
+			t.Fatalf("re-encoding %q: %v, %s", code, err, cellDiff(p.Cells, decoded))
+		}
+	})
+}
+
+func FuzzParseLifeLikeRule(f *testing.F) {
+	for _, rulestring := range []string{"B3/S23", "b36/s23", "S23/B3", "B/S", "B0/S8", "B3/S23/"} {
+		f.Add(rulestring)
+	}
+	f.Fuzz(func(t *testing.T, rulestring string) {
+		rule, err := ParseLifeLikeRule(rulestring)
+		if err != nil {
+			return
+		}
+		if rule.Birth[0] {
+			t.Fatalf("ParseLifeLikeRule(%q) accepted birth on 0", rulestring)
+		}
+		if again, err := ParseLifeLikeRule(rule.String()); err != nil || again != rule {
+			t.Fatalf("ParseLifeLikeRule(%q) = %v, which reads back as %v, %v", rulestring, rule, again, err)
+		}
+	})
+}
+
+func FuzzParseRulesFromString(f *testing.F) {
+	for _, rules := range []string{"conway", "conway,no-top-left", "B36/S23", " Conway , b2/s ", ",,"} {
+		f.Add(rules)
+	}
+	f.Fuzz(func(t *testing.T, rules string) {
+		g := CreateSeedUniverse(4, 4, Glider, ParseRulesFromString(rules)...)
+		g.CreateNextGeneration()
+	})
+}
+
+func FuzzParseRule3D(f *testing.F) {
+	for _, rule := range []string{"4555", "5766", "B6/S567", "b45/s5", "9999", "B5,7/S10", "B/S10,"} {
+		f.Add(rule)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		rule, err := ParseRule3D(s)
+		if err != nil {
+			return
+		}
+		if again, err := ParseRule3D(rule.String()); err != nil || !reflect.DeepEqual(again, rule) {
+			t.Fatalf("ParseRule3D(%q) = %v, which reads back as %v, %v", s, rule, again, err)
+		}
+	})
+}
+
+func FuzzParseBlockRule(f *testing.F) {
+	for _, rule := range []string{"critters", "bbm", "MS,D0;8;4;3;2;5;9;7;1;6;10;11;12;13;14;15", "0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15"} {
+		f.Add(rule)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		rule, err := ParseBlockRule(s)
+		if err != nil {
+			return
+		}
+		if again, err := ParseBlockRule(rule.String()); err != nil || again != rule {
+			t.Fatalf("ParseBlockRule(%q) = %v, which reads back as %v, %v", s, rule, again, err)
+		}
+	})
+}
+
+func FuzzParseStateRule(f *testing.F) {
+	for _, rule := range []string{"wireworld", "ant", "w110", "rule-30", "w256"} {
+		f.Add(rule)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		ParseStateRule(s)
+	})
+}
+
+func FuzzParseStop(f *testing.F) {
+	for _, stop := range []string{"extinct", "stable,period=12", "max-population=500", "period=0", ""} {
+		f.Add(stop)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		stop, err := ParseStop(s)
+		if err != nil {
+			return
+		}
+		if again, err := ParseStop(stop.String()); err != nil || again != stop {
+			t.Fatalf("ParseStop(%q) = %+v, which reads back as %+v, %v", s, stop, again, err)
+		}
+	})
+}
+
+func FuzzReadConfig(f *testing.F) {
+	f.Add(uint8(ConfigJSON), `{"rows": 8, "cols": 8, "stop": {"extinct": true}}`)
+	f.Add(uint8(ConfigTOML), "rows = 8\n[stop]\nperiod = 2\n[[patterns]]\npattern = \"glider\"\n")
+	f.Add(uint8(ConfigYAML), "rows: 8\npatterns:\n  - pattern: glider\n    row: 1\noutputs: [{kind: stats, path: s.csv}]\n")
+	f.Fuzz(func(t *testing.T, formatNumber uint8, text string) {
+		if len(text) > fuzzMaxInput {
+			t.Skip()
+		}
+		format := ConfigFormat(int(formatNumber) % 3)
+		c, err := ReadConfig(strings.NewReader(text), format)
+		if err != nil {
+			return
+		}
+		for _, out := range []ConfigFormat{ConfigJSON, ConfigTOML, ConfigYAML} {
+			var buf bytes.Buffer
+			if err := WriteConfig(&buf, c, out); err != nil {
+				t.Fatalf("writing a config read as %s as %s: %v", format, out, err)
+			}
+			again, err := ReadConfig(&buf, out)
+			if err != nil || !reflect.DeepEqual(again, c) {
+				t.Fatalf("%s config read as %s round trip = %+v, %v; want %+v", out, format, again, err, c)
+			}
+		}
+	})
+}
+
+func FuzzParseTopology(f *testing.F) {
+	for _, name := range []string{"torus", "Klein", " plane ", ""} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		topology, err := ParseTopology(name)
+		if err != nil {
+			return
+		}
+		if again, err := ParseTopology(topology.String()); err != nil || again != topology {
+			t.Fatalf("ParseTopology(%q) = %v, which reads back as %v, %v", name, topology, again, err)
+		}
+	})
+}
@@ -2,6 +2,8 @@ package gameoflife
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"os"
 	"time"
 )
 
@@ -11,6 +13,7 @@ const (
 	VALUE_LIVE_CELL = 1
 	blackChar       = "\033[40m \033[0m"
 	whiteChar       = "\033[47m \033[0m"
+	redChar         = "\033[41m \033[0m"
 )
 
 // Cell represents a cell in the Game of Life universe with its row and column indices.
@@ -25,6 +28,40 @@ type GameOfLife struct {
 	numCols           int
 	neighbouringCells []Cell
 	rules             []Rule
+	generation        int
+	rng               *rand.PCG
+	history           *History // nil unless EnableHistory was called
+
+	// births and deaths of the last step, and where per-generation stats go
+	lastBirths    int
+	lastDeaths    int
+	statsRecorder *StatsRecorder
+
+	// occurrences of this pattern are highlighted by Display; nil for none
+	highlight *highlightQuery
+
+	// per-cell ages, nil unless EnableAgeTracking was called, and how Display colours cells
+	ages       *cellAges
+	colourMode ColourMode
+
+	// colours of live cells under a colour scheme; nil for plain Life
+	colours      map[Cell]int
+	colourScheme ColourScheme
+
+	// how the edges are joined, a torus unless SetTopology was called
+	topology Topology
+
+	// how generations are computed, what engines other than EngineSparse keep between
+	// them, and the longest period EngineTiled skips
+	engine     Engine
+	tiles      tileEngine
+	tilePeriod int
+
+	// watches for the conditions that end a run; nil unless SetStop was called
+	stop *stopWatcher
+
+	// auto-checkpointing during Run
+	autoCheckpoint
 }
 
 // CreateSeedUniverse create seed universe based on the given row, col and seed pattern
@@ -47,45 +84,57 @@ func CreateSeedUniverse(row, col int, seedPattern SEED_PATTERN, rules ...Rule) *
 			{R: 1, C: -1}, {R: 1, C: 0}, {R: 1, C: 1},
 		},
 		rules: rules,
+		rng:   rand.NewPCG(0, 0),
 	}
 }
 
+// Generation returns the number of generations computed since the seed universe.
+func (g *GameOfLife) Generation() int {
+	return g.generation
+}
+
+// Size returns the number of rows and columns of the universe.
+func (g *GameOfLife) Size() (rows, cols int) {
+	return g.numRows, g.numCols
+}
+
+// SetRandomSeed reseeds the random number generator owned by the universe.
+// Everything random that happens to a universe draws from this generator so that
+// a run can be reproduced, and its state is saved with every checkpoint.
+func (g *GameOfLife) SetRandomSeed(seed uint64) {
+	g.rng = rand.NewPCG(seed, seed)
+}
+
+// Rand returns a random number generator backed by the universe's generator state.
+func (g *GameOfLife) Rand() *rand.Rand {
+	if g.rng == nil {
+		g.rng = rand.NewPCG(0, 0)
+	}
+	return rand.New(g.rng)
+}
+
 // Display displays the current state of the Game of Life universe to the standard output.
 // Alive cells are represented by whiteChar, and dead cells by blackChar.
+// Alive cells belonging to a highlighted pattern are represented by redChar.
+// With ColourByAge, other alive cells are coloured by their age instead, and under a
+// colour scheme by their colour.
 // The universe is printed row by row, with each cell separated by a space.
 func (g GameOfLife) Display() {
-	fmt.Println("==============")
-
-	for rowIndex := range g.numRows {
-		for colIndex := range g.numCols {
-			if _, ok := g.universe[Cell{rowIndex, colIndex}]; ok {
-				fmt.Print(" ", whiteChar)
-			} else {
-				fmt.Print(" ", blackChar)
-			}
-		}
-		fmt.Print("\n\n")
-	}
+	g.WriteANSI(os.Stdout)
 }
 
 // Run simulates the Game of Life for a specified number of generations.
 // It prints the initial state, then iteratively generates and prints each subsequent generation,
 // pausing for the specified delay between generations.
+// When auto-checkpointing is enabled, the state is saved every configured number of generations
+// and once more after the last generation.
 //
 // Parameters:
 //
 //	generations - the number of generations to simulate.
 //	delay - the duration to wait between each generation.
 func (g *GameOfLife) Run(generations int, delay time.Duration) {
-	fmt.Printf("Original Generation:\n")
-	g.Display()
-	for i := 1; i <= generations; i++ {
-		fmt.Print("\033[H\033[2J") // Clear screen before printing next frame
-		fmt.Printf("Generation: %d\n", i)
-		g.CreateNextGeneration()
-		g.Display()
-		time.Sleep(delay)
-	}
+	runAutomaton(g, &g.autoCheckpoint, generations, delay)
 }
 
 // _wrapCellWithinUniverse return border bound cooridinates for row and col if the given cell coordinates (neighbourCellRow, neighbourCellCol) beyond the boundry
@@ -105,12 +154,11 @@ func (g *GameOfLife) _wrapCellWithinUniverse(cell Cell) Cell {
 //   - neighborCounts: a pointer to a map that tracks the count of alive neighbours for each cell.
 func (g *GameOfLife) _markNeighbourAlive(currentPosition Cell, neighborCounts *map[Cell]int) {
 
-	for _, neighbourCell := range g.neighbouringCells {
-		neighbourCell = g.
-			_wrapCellWithinUniverse(
-				Cell{(currentPosition.R + neighbourCell.R), (currentPosition.C + neighbourCell.C)},
-			)
-		(*neighborCounts)[neighbourCell]++
+	for _, offset := range g.neighbouringCells {
+		// neighbours beyond an edge that is not joined are always dead
+		if neighbourCell, ok := g.neighbour(currentPosition, offset); ok {
+			(*neighborCounts)[neighbourCell]++
+		}
 	}
 }
 
@@ -126,10 +174,50 @@ func (g *GameOfLife) _markNeighbourAlive(currentPosition Cell, neighborCounts *m
 // #3 will be: more than 4 neight would be overcrowding
 // #5 cell has live neighbour on top left, it will always die
 //
-// The new universe is created which replaces existing one.
+// The new universe is created which replaces existing one. How it is computed depends on
+// the engine, see SetEngine.
 func (g *GameOfLife) CreateNextGeneration() {
+	var newUniverse map[Cell]struct{}
+	var births, deaths int
+	if g.engine != EngineSparse && localRules(g.rules) {
+		if g.tiles == nil || !g.tiles.describes(g) {
+			g.tiles = g.newTileEngine()
+		}
+		newUniverse, births, deaths = g.tiles.step(g)
+	} else {
+		g.tiles = nil
+		newUniverse, births, deaths = g.sparseStep()
+	}
+
+	if g.colours != nil {
+		g.colours = g.nextColours(newUniverse)
+	}
+	if g.ages != nil {
+		g.ages.update(g.universe, newUniverse, g.generation+1)
+	}
+	if g.history != nil {
+		g.history.record(g.generation+1, g.universe, newUniverse, g.colours, g.ages)
+	}
+	g.lastBirths, g.lastDeaths = births, deaths
+	g.universe = newUniverse
+	g.generation++
+	if g.stop != nil {
+		g.stop.observe(g)
+	}
+
+	if g.statsRecorder != nil {
+		if err := g.statsRecorder.Record(g.Stats()); err != nil {
+			fmt.Fprintf(os.Stderr, "recording stats failed, stopped recording: %v\n", err)
+			g.statsRecorder = nil
+		}
+	}
+}
+
+// sparseStep computes the next generation with EngineSparse, counting the neighbours of
+// every live cell, and returns it with the number of births and deaths.
+func (g *GameOfLife) sparseStep() (newUniverse map[Cell]struct{}, births, deaths int) {
 	neighborCounts := make(map[Cell]int)
-	newUniverse := make(map[Cell]struct{})
+	newUniverse = make(map[Cell]struct{})
 
 	// for every live cell, count the number of live neighbours
 	for cell := range g.universe {
@@ -151,19 +239,31 @@ func (g *GameOfLife) CreateNextGeneration() {
 	// and determine their next state based on the number of live neighbours.
 	// This is where the rules are applied to determine if a cell should be alive or dead
 	// based on the neighborCounts.
+	survivors := 0
 	for cell := range candidates {
 		// Check if the cell is currently alive
 		_, isCellAlive := g.universe[cell]
 		neighborCount := neighborCounts[cell]
 
-		// Apply each rule to determine if the cell should be alive in the next generation
-		for _, rule := range g.rules {
-			if rule.Apply(cell, isCellAlive, neighborCount, g) {
-				newUniverse[cell] = struct{}{}
-				break // If any rule applies, we can stop checking further rules for this cell
+		if g.nextState(cell, isCellAlive, neighborCount) {
+			newUniverse[cell] = struct{}{}
+			if isCellAlive {
+				survivors++
+			} else {
+				births++
 			}
 		}
 	}
+	return newUniverse, births, len(g.universe) - survivors
+}
 
-	g.universe = newUniverse
+// nextState applies the rules to a cell: it is alive in the next generation if any rule
+// says so.
+func (g *GameOfLife) nextState(cell Cell, alive bool, neighborCount int) bool {
+	for _, rule := range g.rules {
+		if rule.Apply(cell, alive, neighborCount, g) {
+			return true // If any rule applies, we can stop checking further rules for this cell
+		}
+	}
+	return false
 }
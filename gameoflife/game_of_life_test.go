@@ -1,6 +1,8 @@
 package gameoflife
 
 import (
+	"maps"
+	"math/rand/v2"
 	"testing"
 )
 
@@ -281,6 +283,21 @@ func TestCreateNextGeneration_Reproduction(t *testing.T) {
 	}
 }
 
+func TestFillRandom(t *testing.T) {
+	a := CreateSeedUniverse(20, 30, Default, ConwayRule{})
+	b := CreateSeedUniverse(20, 30, Default, ConwayRule{})
+	a.SetRandomSeed(7)
+	b.SetRandomSeed(7)
+	a.FillRandom(0.25)
+	b.FillRandom(0.25)
+	if !maps.Equal(a.universe, b.universe) {
+		t.Errorf("FillRandom() with the same seed gave different universes")
+	}
+	if population := len(a.universe); population < 100 || population > 200 {
+		t.Errorf("FillRandom(0.25) of 600 cells gave %d live cells", population)
+	}
+}
+
 func BenchmarkCreateNextGeneration_100x100_Glider(b *testing.B) {
 	// Create a dense 100x100 universe with a checkerboard pattern
 	numRows, numCols := 100, 100
@@ -402,3 +419,90 @@ func BenchmarkCreateNextGeneration_1000x1000_Sparse(b *testing.B) {
 		u.CreateNextGeneration()
 	}
 }
+
+// ashObjects are the still lifes random soups mostly settle into, and the blinker.
+var ashObjects = map[string][]Cell{
+	"block":   {{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	"beehive": {{0, 1}, {0, 2}, {1, 0}, {1, 3}, {2, 1}, {2, 2}},
+	"loaf":    {{0, 1}, {0, 2}, {1, 0}, {1, 3}, {2, 1}, {2, 3}, {3, 2}},
+	"boat":    {{0, 0}, {0, 1}, {1, 0}, {1, 2}, {2, 1}},
+	"tub":     {{0, 1}, {1, 0}, {1, 2}, {2, 1}},
+	"blinker": {{0, 0}, {0, 1}, {0, 2}},
+}
+
+// stabilisedSoup returns a rows x cols universe covered in ash like a soup leaves behind
+// once it has stabilised: an object in every 12x12 square, far enough apart not to
+// interact, one in every blinkerEvery of them a blinker (none if 0) and the rest random
+// still lifes.
+func stabilisedSoup(rows, cols, blinkerEvery int) map[Cell]struct{} {
+	stillLifes := []string{"block", "beehive", "loaf", "boat", "tub"}
+	rng := rand.New(rand.NewPCG(1, 2))
+	universe := make(map[Cell]struct{})
+	for i, r := 0, 0; r+12 <= rows; r += 12 {
+		for c := 0; c+12 <= cols; c, i = c+12, i+1 {
+			name := stillLifes[rng.IntN(len(stillLifes))]
+			if blinkerEvery > 0 && i%blinkerEvery == 0 {
+				name = "blinker"
+			}
+			for _, cell := range ashObjects[name] {
+				universe[Cell{r + 4 + cell.R, c + 4 + cell.C}] = struct{}{}
+			}
+		}
+	}
+	return universe
+}
+
+// benchmarkStabilisedSoup steps a 1000x1000 stabilised soup with an engine. The universe
+// is stepped a few times before timing, so engines that learn where it changes and
+// repeats have done so.
+func benchmarkStabilisedSoup(b *testing.B, engine Engine, blinkerEvery int) {
+	u := CreateSeedUniverse(1000, 1000, Default, RuleFactory(ConwayRuleType))
+	u.universe = stabilisedSoup(1000, 1000, blinkerEvery)
+	u.SetEngine(engine)
+	for range 3 {
+		u.CreateNextGeneration()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		u.CreateNextGeneration()
+	}
+}
+
+func BenchmarkCreateNextGeneration_1000x1000_StableSoup(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineSparse, 0)
+}
+
+func BenchmarkCreateNextGeneration_1000x1000_StableSoup_Active(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineActive, 0)
+}
+
+// a few blinkers keep the tiles around them changing
+func BenchmarkCreateNextGeneration_1000x1000_StableSoupBlinkers(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineSparse, 50)
+}
+
+func BenchmarkCreateNextGeneration_1000x1000_StableSoupBlinkers_Active(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineActive, 50)
+}
+
+func BenchmarkCreateNextGeneration_1000x1000_StableSoup_Tiled(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineTiled, 0)
+}
+
+// the tiled engine copies the blinkers' tiles from two generations before
+func BenchmarkCreateNextGeneration_1000x1000_StableSoupBlinkers_Tiled(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineTiled, 50)
+}
+
+// a blinker in every fourth square, about as many as soups leave behind
+func BenchmarkCreateNextGeneration_1000x1000_BlinkingSoup(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineSparse, 4)
+}
+
+func BenchmarkCreateNextGeneration_1000x1000_BlinkingSoup_Active(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineActive, 4)
+}
+
+func BenchmarkCreateNextGeneration_1000x1000_BlinkingSoup_Tiled(b *testing.B) {
+	benchmarkStabilisedSoup(b, EngineTiled, 4)
+}
@@ -0,0 +1,258 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Golden patterns are RLE files in testdata/golden whose `#C key: value` comments say what
+// the pattern must do. Every file is checked with every engine and topology, so adding a
+// regression case is a matter of dropping in a file:
+//
+//	#N glider
+//	#C generations: 4
+//	#C expect: bo$2bo$3o!
+//	#C offset: 1 1
+//	#C period: 4
+//	#C displacement: 1 1
+//	x = 3, y = 3, rule = B3/S23
+//	bo$2bo$3o!
+//
+// generations (required) is how long to run. expect is the RLE body of that generation,
+// `!` when everything died, with its bounding box at offset (default 0 0) from the start's.
+// period, if given, is the smallest number of generations after which the last generation
+// repeats, moved by displacement (default 0 0). The pattern's rule defaults to Conway's.
+//
+// Without further keys the pattern runs with enough room around it never to reach an
+// edge, so every topology must agree. `universe: rows cols` and `at: row col` instead put
+// the start's bounding box at a position of a universe of that size, for cases about the
+// edges; `topologies: plane,cylinder` then limits the topologies the expectations hold for.
+type goldenCase struct {
+	name        string
+	start       map[Cell]struct{}
+	rules       []Rule
+	generations int
+	expect      map[Cell]struct{} // in the start's coordinates
+	period      int
+	displace    Cell
+	rows, cols  int
+	at          Cell
+	topologies  []Topology
+}
+
+// readGoldenCase reads a golden pattern file.
+func readGoldenCase(path string) (*goldenCase, error) {
+	pattern, err := LoadPattern(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &goldenCase{
+		name:       strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		start:      pattern.Cells,
+		topologies: Topologies(),
+	}
+	if c.rules = ParseRulesFromString(pattern.Rule); len(c.rules) == 0 {
+		c.rules = []Rule{ConwayRule{}}
+	}
+
+	ints := func(value string, n int) ([]int, error) {
+		fields := strings.Fields(value)
+		if len(fields) != n {
+			return nil, fmt.Errorf("want %d numbers, got %q", n, value)
+		}
+		numbers := make([]int, n)
+		for i, field := range fields {
+			if numbers[i], err = strconv.Atoi(field); err != nil {
+				return nil, err
+			}
+		}
+		return numbers, nil
+	}
+	var expectBody string
+	var offset Cell
+	for _, comment := range pattern.Comments {
+		key, value, ok := strings.Cut(comment, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		var numbers []int
+		switch strings.TrimSpace(key) {
+		case "generations":
+			numbers, err = ints(value, 1)
+			if err == nil {
+				c.generations = numbers[0]
+			}
+		case "expect":
+			expectBody = value
+		case "offset":
+			if numbers, err = ints(value, 2); err == nil {
+				offset = Cell{numbers[0], numbers[1]}
+			}
+		case "period":
+			if numbers, err = ints(value, 1); err == nil {
+				c.period = numbers[0]
+			}
+		case "displacement":
+			if numbers, err = ints(value, 2); err == nil {
+				c.displace = Cell{numbers[0], numbers[1]}
+			}
+		case "universe":
+			if numbers, err = ints(value, 2); err == nil {
+				c.rows, c.cols = numbers[0], numbers[1]
+			}
+		case "at":
+			if numbers, err = ints(value, 2); err == nil {
+				c.at = Cell{numbers[0], numbers[1]}
+			}
+		case "topologies":
+			c.topologies = nil
+			for _, name := range strings.Split(value, ",") {
+				topology, err := ParseTopology(name)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+				c.topologies = append(c.topologies, topology)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, key, err)
+		}
+	}
+	if c.generations <= 0 || expectBody == "" {
+		return nil, fmt.Errorf("%s: generations and expect are required", path)
+	}
+
+	expected, err := readRLE(bufio.NewReader(strings.NewReader(expectBody)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: expect: %w", path, err)
+	}
+	// expect is relative to its own bounding box, placed at offset from the start's
+	startMin, _, _ := boundingBox(c.start)
+	c.expect = translateCells(expected.Cells, Cell{startMin.R + offset.R, startMin.C + offset.C}, 0, 0)
+	return c, nil
+}
+
+// translateCells moves cells by an offset, wrapping them into a rows x cols universe if
+// rows is positive.
+func translateCells(cells map[Cell]struct{}, offset Cell, rows, cols int) map[Cell]struct{} {
+	moved := make(map[Cell]struct{}, len(cells))
+	for cell := range cells {
+		cell = Cell{cell.R + offset.R, cell.C + offset.C}
+		if rows > 0 {
+			cell = Cell{(cell.R%rows + rows) % rows, (cell.C%cols + cols) % cols}
+		}
+		moved[cell] = struct{}{}
+	}
+	return moved
+}
+
+// cellDiff draws the union of two generations' bounding boxes: `O` alive in both, `-` only
+// in want (missing), `+` only in got (extra) and `.` dead in both.
+func cellDiff(want, got map[Cell]struct{}) string {
+	union := maps.Clone(want)
+	maps.Copy(union, got)
+	if len(union) == 0 {
+		return "(both empty)\n"
+	}
+	minCell, height, width := boundingBox(union)
+	var b strings.Builder
+	fmt.Fprintf(&b, "rows %d..%d, columns %d..%d (O both, - missing, + extra):\n", minCell.R, minCell.R+height-1, minCell.C, minCell.C+width-1)
+	for r := minCell.R; r < minCell.R+height; r++ {
+		for c := minCell.C; c < minCell.C+width; c++ {
+			_, inWant := want[Cell{r, c}]
+			_, inGot := got[Cell{r, c}]
+			switch {
+			case inWant && inGot:
+				b.WriteByte('O')
+			case inWant:
+				b.WriteByte('-')
+			case inGot:
+				b.WriteByte('+')
+			default:
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// run checks the case with one engine and topology.
+func (c *goldenCase) run(t *testing.T, prepare func(*GameOfLife), topology Topology) {
+	minCell, height, width := boundingBox(c.start)
+	rows, cols, at := c.rows, c.cols, c.at
+	if rows == 0 {
+		// a pattern grows by at most one cell per side and generation
+		margin := c.generations + c.period + 2
+		rows, cols, at = height+2*margin, width+2*margin, Cell{margin, margin}
+	}
+	offset := Cell{at.R - minCell.R, at.C - minCell.C}
+
+	g := CreateSeedUniverse(rows, cols, Default, c.rules...)
+	g.universe = translateCells(c.start, offset, rows, cols)
+	g.SetTopology(topology)
+	prepare(g)
+	for range c.generations {
+		g.CreateNextGeneration()
+	}
+	want := translateCells(c.expect, offset, rows, cols)
+	if !maps.Equal(g.universe, want) {
+		t.Fatalf("generation %d differs, %s", c.generations, cellDiff(want, g.universe))
+	}
+	if c.period == 0 {
+		return
+	}
+
+	last := maps.Clone(g.universe)
+	lastShape := normalisedCells(last)
+	for p := 1; p <= c.period; p++ {
+		g.CreateNextGeneration()
+		if p < c.period && len(g.universe) == len(last) && slices.Equal(normalisedCells(g.universe), lastShape) {
+			t.Fatalf("generation %d repeats after %d generations, before the period of %d", c.generations, p, c.period)
+		}
+	}
+	if want := translateCells(last, c.displace, rows, cols); !maps.Equal(g.universe, want) {
+		t.Fatalf("generation %d does not repeat moved by %v after %d generations, %s",
+			c.generations, c.displace, c.period, cellDiff(want, g.universe))
+	}
+}
+
+func TestGoldenPatterns(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "golden", "*.rle"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no golden patterns in testdata/golden")
+	}
+	for _, path := range paths {
+		c, err := readGoldenCase(path)
+		if err != nil {
+			t.Errorf("%v", err)
+			continue
+		}
+		for _, engine := range testEngines {
+			for _, topology := range c.topologies {
+				t.Run(fmt.Sprintf("%s/%s/%s", c.name, engine.name, topology), func(t *testing.T) {
+					c.run(t, engine.prepare, topology)
+				})
+			}
+		}
+	}
+}
+
+func TestCellDiff(t *testing.T) {
+	want := map[Cell]struct{}{{0, 0}: {}, {0, 1}: {}}
+	got := map[Cell]struct{}{{0, 1}: {}, {1, 2}: {}}
+	diff := cellDiff(want, got)
+	if wantDiff := "rows 0..1, columns 0..2 (O both, - missing, + extra):\n-O.\n..+\n"; diff != wantDiff {
+		t.Errorf("cellDiff() = %q; want %q", diff, wantDiff)
+	}
+}
@@ -0,0 +1,286 @@
+package gameoflife
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// History is a bounded record of past generations of a universe.
+// The Game of Life is not reversible, so going back in time is only possible
+// for generations that were recorded while moving forward.
+//
+// Every keyframeEvery generations a full snapshot is stored, and every generation
+// in between is stored as the cells born and the cells that died. Restoring a
+// generation replays the diffs forward from the nearest earlier keyframe.
+// When the estimated memory use exceeds the budget, the oldest keyframe together
+// with its diffs is dropped.
+//
+// Colours and cell ages are recorded too, so a rewound universe continues exactly as it
+// did the first time: keyframes hold the colour of every cell and the whole state of age
+// tracking, and diffs the colour of every cell born.
+type History struct {
+	keyframeEvery int
+	budgetBytes   int
+	usedBytes     int
+	entries       []historyEntry
+	// coloured and aged are whether the entries since the last keyframe record colours
+	// and ages
+	coloured, aged bool
+}
+
+// historyEntry records a single generation, either as a full snapshot (keyframe)
+// or as a diff against the previous generation.
+type historyEntry struct {
+	generation int
+	keyframe   []Cell
+	born       []Cell
+	died       []Cell
+	// colours are the colours of the keyframe's cells, or of the cells born, in the same
+	// order; a keyframe of a universe without colours has none
+	colours []int
+	// ages is the state of age tracking at a keyframe, nil without age tracking
+	ages *cellAges
+}
+
+// historyState is a generation restored from the history. colours and ages are nil if
+// they were not recorded.
+type historyState struct {
+	universe map[Cell]struct{}
+	colours  map[Cell]int
+	ages     *cellAges
+}
+
+// historyEntryOverhead is a rough per-entry cost on top of the cells it holds.
+const historyEntryOverhead = 96
+
+// NewHistory returns an empty history that stores a full snapshot every keyframeEvery
+// generations and uses at most roughly budgetBytes of memory. A non-positive budget
+// means unbounded.
+func NewHistory(keyframeEvery, budgetBytes int) *History {
+	if keyframeEvery <= 0 {
+		keyframeEvery = 1
+	}
+	return &History{keyframeEvery: keyframeEvery, budgetBytes: budgetBytes}
+}
+
+// Oldest returns the earliest generation that can be restored.
+func (h *History) Oldest() (int, bool) {
+	if len(h.entries) == 0 {
+		return 0, false
+	}
+	return h.entries[0].generation, true
+}
+
+// Newest returns the latest recorded generation.
+func (h *History) Newest() (int, bool) {
+	if len(h.entries) == 0 {
+		return 0, false
+	}
+	return h.entries[len(h.entries)-1].generation, true
+}
+
+// UsedBytes returns the estimated memory held by the recorded generations.
+func (h *History) UsedBytes() int {
+	return h.usedBytes
+}
+
+// record stores the given generation, with its colours and ages if the universe has
+// them. previous is the universe of generation-1 and may be nil when no earlier
+// generation is known, in which case a keyframe is forced, as it is when colours or ages
+// start or stop being recorded. Generations that are already recorded are ignored:
+// stepping is deterministic, so re-computing a generation after rewinding yields the
+// same universe.
+func (h *History) record(generation int, previous, current map[Cell]struct{}, colours map[Cell]int, ages *cellAges) {
+	if newest, ok := h.Newest(); ok {
+		if generation <= newest {
+			return
+		}
+		if generation != newest+1 {
+			// a gap would make the diffs meaningless, start over
+			h.entries = nil
+			h.usedBytes = 0
+		}
+	}
+
+	entry := historyEntry{generation: generation}
+	if len(h.entries) == 0 || previous == nil || generation%h.keyframeEvery == 0 ||
+		h.coloured != (colours != nil) || h.aged != (ages != nil) {
+		entry.keyframe = cellsOf(current)
+		if colours != nil {
+			entry.colours = make([]int, len(entry.keyframe))
+			for i, cell := range entry.keyframe {
+				entry.colours[i] = colours[cell]
+			}
+		}
+		if ages != nil {
+			entry.ages = ages.clone()
+		}
+		h.coloured, h.aged = colours != nil, ages != nil
+	} else {
+		for cell := range current {
+			if _, ok := previous[cell]; !ok {
+				entry.born = append(entry.born, cell)
+				if colours != nil {
+					entry.colours = append(entry.colours, colours[cell])
+				}
+			}
+		}
+		for cell := range previous {
+			if _, ok := current[cell]; !ok {
+				entry.died = append(entry.died, cell)
+			}
+		}
+	}
+
+	h.entries = append(h.entries, entry)
+	h.usedBytes += entry.size()
+	h.enforceBudget()
+}
+
+// enforceBudget drops the oldest keyframe and its diffs until the history fits its budget.
+// The most recent keyframe run is always kept.
+func (h *History) enforceBudget() {
+	for h.budgetBytes > 0 && h.usedBytes > h.budgetBytes {
+		next := -1
+		for i := 1; i < len(h.entries); i++ {
+			if h.entries[i].keyframe != nil {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return
+		}
+		for _, entry := range h.entries[:next] {
+			h.usedBytes -= entry.size()
+		}
+		h.entries = h.entries[next:]
+	}
+}
+
+// stateAt reconstructs the given generation.
+func (h *History) stateAt(generation int) (historyState, error) {
+	oldest, ok := h.Oldest()
+	newest, _ := h.Newest()
+	if !ok || generation < oldest || generation > newest {
+		return historyState{}, fmt.Errorf("generation %d is not in history", generation)
+	}
+
+	target := generation - oldest
+	start := target
+	for h.entries[start].keyframe == nil {
+		start--
+	}
+
+	keyframe := h.entries[start]
+	state := historyState{universe: make(map[Cell]struct{}, len(keyframe.keyframe))}
+	for _, cell := range keyframe.keyframe {
+		state.universe[cell] = struct{}{}
+	}
+	if keyframe.colours != nil {
+		state.colours = make(map[Cell]int, len(keyframe.keyframe))
+		for i, cell := range keyframe.keyframe {
+			state.colours[cell] = keyframe.colours[i]
+		}
+	}
+	if keyframe.ages != nil {
+		state.ages = keyframe.ages.clone()
+	}
+	for _, entry := range h.entries[start+1 : target+1] {
+		for _, cell := range entry.died {
+			delete(state.universe, cell)
+			delete(state.colours, cell)
+		}
+		for i, cell := range entry.born {
+			state.universe[cell] = struct{}{}
+			if state.colours != nil {
+				state.colours[cell] = entry.colours[i]
+			}
+		}
+		if state.ages != nil {
+			state.ages.replay(entry.born, entry.died, state.universe, entry.generation)
+		}
+	}
+	return state, nil
+}
+
+func (e historyEntry) size() int {
+	cellSize := int(unsafe.Sizeof(Cell{}))
+	intSize := int(unsafe.Sizeof(0))
+	size := historyEntryOverhead + cellSize*(len(e.keyframe)+len(e.born)+len(e.died)) + intSize*len(e.colours)
+	if e.ages != nil {
+		size += (cellSize + intSize) * (len(e.ages.age) + len(e.ages.lastChange) + len(e.ages.exposure))
+	}
+	return size
+}
+
+// cellsOf returns the live cells of the universe as a slice.
+func cellsOf(universe map[Cell]struct{}) []Cell {
+	cells := make([]Cell, 0, len(universe))
+	for cell := range universe {
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// EnableHistory starts recording generations so that StepBack and GoToGeneration
+// can rewind the universe. See History for the meaning of the parameters.
+func (g *GameOfLife) EnableHistory(keyframeEvery, budgetBytes int) {
+	g.history = NewHistory(keyframeEvery, budgetBytes)
+	g.history.record(g.generation, nil, g.universe, g.colours, g.ages)
+}
+
+// History returns the recorded history, or nil if recording is not enabled.
+func (g *GameOfLife) History() *History {
+	return g.history
+}
+
+// StepBack rewinds the universe by one generation.
+func (g *GameOfLife) StepBack() error {
+	return g.GoToGeneration(g.generation - 1)
+}
+
+// GoToGeneration jumps to any recorded generation. Generations after the newest
+// recorded one are reached by computing forward.
+func (g *GameOfLife) GoToGeneration(generation int) error {
+	if g.history == nil {
+		return fmt.Errorf("history is not enabled")
+	}
+
+	if newest, ok := g.history.Newest(); ok && generation > newest {
+		if err := g.GoToGeneration(newest); err != nil {
+			return err
+		}
+		for g.generation < generation {
+			g.CreateNextGeneration()
+		}
+		return nil
+	}
+
+	state, err := g.history.stateAt(generation)
+	if err != nil {
+		return err
+	}
+	g.universe = state.universe
+	g.generation = generation
+	// generations recorded before colours or ages were enabled have neither: cells keep
+	// the colour last seen at their position, or get colour 1, and ages start over
+	if g.ages != nil {
+		if state.ages != nil {
+			g.ages = state.ages
+		} else {
+			g.ages.restart(g)
+		}
+	}
+	if g.colours != nil {
+		if state.colours != nil {
+			g.colours = state.colours
+		} else {
+			g.SetColours(g.colourScheme, g.colours)
+		}
+	}
+	if g.stop != nil {
+		g.stop.restart(g)
+	}
+	return nil
+}
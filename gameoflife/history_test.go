@@ -0,0 +1,120 @@
+package gameoflife
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestHistory_StepBackAndGoToGeneration(t *testing.T) {
+	g := CreateSeedUniverse(12, 12, Glider, RuleFactory(ConwayRuleType))
+	g.EnableHistory(4, 0)
+
+	want := []map[Cell]struct{}{maps.Clone(g.universe)}
+	for range 10 {
+		g.CreateNextGeneration()
+		want = append(want, maps.Clone(g.universe))
+	}
+
+	if err := g.StepBack(); err != nil {
+		t.Fatalf("StepBack() error = %v", err)
+	}
+	if g.Generation() != 9 || !maps.Equal(g.universe, want[9]) {
+		t.Errorf("StepBack() reached generation %d with %v; want generation 9 with %v", g.Generation(), g.universe, want[9])
+	}
+
+	for _, generation := range []int{0, 3, 4, 7, 10} {
+		if err := g.GoToGeneration(generation); err != nil {
+			t.Fatalf("GoToGeneration(%d) error = %v", generation, err)
+		}
+		if !maps.Equal(g.universe, want[generation]) {
+			t.Errorf("GoToGeneration(%d) = %v; want %v", generation, g.universe, want[generation])
+		}
+	}
+
+	// re-computing after a rewind continues the same timeline
+	if err := g.GoToGeneration(5); err != nil {
+		t.Fatalf("GoToGeneration(5) error = %v", err)
+	}
+	g.CreateNextGeneration()
+	if !maps.Equal(g.universe, want[6]) {
+		t.Errorf("generation 6 after rewind = %v; want %v", g.universe, want[6])
+	}
+
+	if err := g.GoToGeneration(12); err != nil {
+		t.Fatalf("GoToGeneration(12) error = %v", err)
+	}
+	if g.Generation() != 12 {
+		t.Errorf("Generation() = %d; want 12", g.Generation())
+	}
+}
+
+func TestHistory_BudgetDropsOldestGenerations(t *testing.T) {
+	g := CreateSeedUniverse(20, 20, Glider, RuleFactory(ConwayRuleType))
+	g.EnableHistory(5, 2000)
+	for range 40 {
+		g.CreateNextGeneration()
+	}
+
+	h := g.History()
+	if h.UsedBytes() > 2000 {
+		t.Errorf("UsedBytes() = %d; want at most 2000", h.UsedBytes())
+	}
+	oldest, _ := h.Oldest()
+	if oldest == 0 {
+		t.Errorf("expected the oldest generations to be dropped")
+	}
+	if err := g.GoToGeneration(oldest - 1); err == nil {
+		t.Errorf("GoToGeneration(%d) succeeded for a dropped generation", oldest-1)
+	}
+	if err := g.GoToGeneration(oldest); err != nil {
+		t.Errorf("GoToGeneration(%d) error = %v", oldest, err)
+	}
+}
+
+func TestStepBack_WithoutHistory(t *testing.T) {
+	g := CreateSeedUniverse(5, 5, Default, RuleFactory(ConwayRuleType))
+	g.CreateNextGeneration()
+	if err := g.StepBack(); err == nil {
+		t.Errorf("StepBack() succeeded without history")
+	}
+}
+
+func TestHistory_RewindKeepsColoursAndAges(t *testing.T) {
+	g := CreateSeedUniverse(16, 16, Default, ConwayRule{})
+	g.SetRandomSeed(3)
+	g.FillRandom(0.4)
+	g.EnableColours(Immigration, ColourSeedRandom)
+	g.EnableAgeTracking()
+	g.EnableHistory(4, 0)
+
+	type state struct {
+		universe map[Cell]struct{}
+		colours  map[Cell]int
+		ages     *cellAges
+	}
+	snapshot := func() state {
+		return state{maps.Clone(g.universe), maps.Clone(g.colours), g.ages.clone()}
+	}
+	want := []state{snapshot()}
+	for range 12 {
+		g.CreateNextGeneration()
+		want = append(want, snapshot())
+	}
+
+	// rewind to a keyframe and between keyframes, then replay the same run
+	for _, generation := range []int{4, 6} {
+		if err := g.GoToGeneration(generation); err != nil {
+			t.Fatalf("GoToGeneration(%d) error = %v", generation, err)
+		}
+		for {
+			if got := snapshot(); !reflect.DeepEqual(got, want[g.Generation()]) {
+				t.Fatalf("generation %d after rewinding to %d = %+v; want %+v", g.Generation(), generation, got, want[g.Generation()])
+			}
+			if g.Generation() == 12 {
+				break
+			}
+			g.CreateNextGeneration()
+		}
+	}
+}
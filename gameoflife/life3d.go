@@ -0,0 +1,316 @@
+package gameoflife
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Cell3 is a cell of a three-dimensional universe.
+type Cell3 struct {
+	X, Y, Z int
+}
+
+// Rule3D is an outer-totalistic rule for three-dimensional Life, where each cell has 26
+// neighbours: a dead cell is born with a number of live neighbours in Birth and a live
+// cell survives with a number in Survival.
+type Rule3D struct {
+	Birth    [27]bool
+	Survival [27]bool
+}
+
+// ParseRule3D parses a 3D rule in Carter Bays' notation, four numbers E_l E_u F_l F_u
+// where live cells survive with E_l to E_u neighbours and dead cells are born with F_l to
+// F_u (e.g. 4555, or 5,7,6,6 when a number has two digits), or in B/S notation with
+// counts separated by commas, which may be left out if all counts are single digits
+// (e.g. B5/S45 or B5,14/S4,5). A rule without any comma has single-digit counts, so a
+// lone two-digit count needs a trailing comma (B/S10,).
+func ParseRule3D(s string) (Rule3D, error) {
+	var r Rule3D
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if strings.Contains(s, "/") {
+		commas := strings.Contains(s, ",")
+		for _, part := range strings.Split(s, "/") {
+			if part == "" {
+				continue
+			}
+			var counts *[27]bool
+			switch part[0] {
+			case 'B':
+				counts = &r.Birth
+			case 'S':
+				counts = &r.Survival
+			default:
+				return r, fmt.Errorf("3D rule %q: want the form B5/S4,5", s)
+			}
+			fields := strings.Split(part[1:], ",")
+			if !commas {
+				// single-digit counts may be written without commas, as in 2D rulestrings
+				fields = strings.Split(part[1:], "")
+			}
+			for _, field := range fields {
+				if field == "" {
+					continue
+				}
+				n, err := strconv.Atoi(field)
+				if err != nil || n < 0 || n > 26 {
+					return r, fmt.Errorf("3D rule %q: %q is not a neighbour count from 0 to 26", s, field)
+				}
+				counts[n] = true
+			}
+		}
+	} else {
+		var fields []string
+		if strings.Contains(s, ",") {
+			fields = strings.Split(s, ",")
+		} else {
+			fields = strings.Split(s, "")
+		}
+		if len(fields) != 4 {
+			return r, fmt.Errorf("3D rule %q: want four numbers such as 4555", s)
+		}
+		var bounds [4]int
+		for i, field := range fields {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 0 || n > 26 {
+				return r, fmt.Errorf("3D rule %q: %q is not a neighbour count from 0 to 26", s, field)
+			}
+			bounds[i] = n
+		}
+		if bounds[0] > bounds[1] || bounds[2] > bounds[3] {
+			return r, fmt.Errorf("3D rule %q: a lower bound is above its upper bound", s)
+		}
+		for n := bounds[0]; n <= bounds[1]; n++ {
+			r.Survival[n] = true
+		}
+		for n := bounds[2]; n <= bounds[3]; n++ {
+			r.Birth[n] = true
+		}
+	}
+	if r.Birth[0] {
+		return r, fmt.Errorf("3D rule %q: rules with birth on 0 neighbours are not supported", s)
+	}
+	return r, nil
+}
+
+// String returns the rule in Bays' notation when both ranges are contiguous, otherwise in
+// B/S notation.
+func (r Rule3D) String() string {
+	survival, birth := countList(r.Survival), countList(r.Birth)
+	contiguous := func(counts []int) bool {
+		return len(counts) > 0 && counts[len(counts)-1]-counts[0] == len(counts)-1
+	}
+	if contiguous(survival) && contiguous(birth) {
+		bounds := []int{survival[0], survival[len(survival)-1], birth[0], birth[len(birth)-1]}
+		if slices.Max(bounds) < 10 {
+			return fmt.Sprintf("%d%d%d%d", bounds[0], bounds[1], bounds[2], bounds[3])
+		}
+		return fmt.Sprintf("%d,%d,%d,%d", bounds[0], bounds[1], bounds[2], bounds[3])
+	}
+	join := func(counts []int) string {
+		fields := make([]string, len(counts))
+		for i, n := range counts {
+			fields[i] = strconv.Itoa(n)
+		}
+		return strings.Join(fields, ",")
+	}
+	s := "B" + join(birth) + "/S" + join(survival)
+	if counts := slices.Concat(birth, survival); !strings.Contains(s, ",") && len(counts) > 0 && slices.Max(counts) >= 10 {
+		// without a comma the count would read as single digits
+		s += ","
+	}
+	return s
+}
+
+func countList(counts [27]bool) []int {
+	var list []int
+	for n, ok := range counts {
+		if ok {
+			list = append(list, n)
+		}
+	}
+	return list
+}
+
+// Life3D is Life in a three-dimensional universe that wraps around in all three axes.
+type Life3D struct {
+	cells      map[Cell3]struct{}
+	size       Cell3
+	rule       Rule3D
+	generation int
+	neighbours []Cell3
+}
+
+// NewLife3D returns an empty sizeX x sizeY x sizeZ universe following rule.
+func NewLife3D(sizeX, sizeY, sizeZ int, rule Rule3D) (*Life3D, error) {
+	if sizeX < 3 || sizeY < 3 || sizeZ < 3 {
+		// smaller sizes would make a cell its own neighbour through the wrap-around
+		return nil, fmt.Errorf("3D universe %dx%dx%d: every side must be at least 3", sizeX, sizeY, sizeZ)
+	}
+	l := &Life3D{cells: make(map[Cell3]struct{}), size: Cell3{sizeX, sizeY, sizeZ}, rule: rule}
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dz := -1; dz <= 1; dz++ {
+				if dx != 0 || dy != 0 || dz != 0 {
+					l.neighbours = append(l.neighbours, Cell3{dx, dy, dz})
+				}
+			}
+		}
+	}
+	return l, nil
+}
+
+func (l *Life3D) wrap(c Cell3) Cell3 {
+	return Cell3{(c.X%l.size.X + l.size.X) % l.size.X, (c.Y%l.size.Y + l.size.Y) % l.size.Y, (c.Z%l.size.Z + l.size.Z) % l.size.Z}
+}
+
+// Set makes a cell alive or dead.
+func (l *Life3D) Set(c Cell3, alive bool) {
+	if alive {
+		l.cells[l.wrap(c)] = struct{}{}
+	} else {
+		delete(l.cells, l.wrap(c))
+	}
+}
+
+// Alive reports whether a cell is alive.
+func (l *Life3D) Alive(c Cell3) bool {
+	_, alive := l.cells[l.wrap(c)]
+	return alive
+}
+
+// Cells returns the live cells.
+func (l *Life3D) Cells() map[Cell3]struct{} {
+	return maps.Clone(l.cells)
+}
+
+// Population returns the number of live cells.
+func (l *Life3D) Population() int {
+	return len(l.cells)
+}
+
+// Generation returns the number of generations computed since the seed.
+func (l *Life3D) Generation() int {
+	return l.generation
+}
+
+// Soup fills a cube of side `side` in the middle of the universe at random, each cell
+// alive with the given density, reproducibly for a seed.
+func (l *Life3D) Soup(side int, density float64, seed uint64) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	origin := Cell3{(l.size.X - side) / 2, (l.size.Y - side) / 2, (l.size.Z - side) / 2}
+	for z := range side {
+		for y := range side {
+			for x := range side {
+				if rng.Float64() < density {
+					l.Set(Cell3{origin.X + x, origin.Y + y, origin.Z + z}, true)
+				}
+			}
+		}
+	}
+}
+
+// Step advances the universe by one generation.
+func (l *Life3D) Step() {
+	counts := make(map[Cell3]int, 26*len(l.cells))
+	for cell := range l.cells {
+		for _, offset := range l.neighbours {
+			counts[l.wrap(Cell3{cell.X + offset.X, cell.Y + offset.Y, cell.Z + offset.Z})]++
+		}
+	}
+	next := make(map[Cell3]struct{}, len(l.cells))
+	for cell, count := range counts {
+		if _, alive := l.cells[cell]; alive && l.rule.Survival[count] || !alive && l.rule.Birth[count] {
+			next[cell] = struct{}{}
+		}
+	}
+	// live cells without live neighbours are not in counts
+	if l.rule.Survival[0] {
+		for cell := range l.cells {
+			if counts[cell] == 0 {
+				next[cell] = struct{}{}
+			}
+		}
+	}
+	l.cells = next
+	l.generation++
+}
+
+// Display prints the universe slice by slice, one slice per Z, with X across and Y down.
+func (l *Life3D) Display() {
+	fmt.Println("==============")
+	for z := range l.size.Z {
+		fmt.Printf("z = %d\n", z)
+		for y := range l.size.Y {
+			for x := range l.size.X {
+				if l.Alive(Cell3{x, y, z}) {
+					fmt.Print(" ", whiteChar)
+				} else {
+					fmt.Print(" ", blackChar)
+				}
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+}
+
+// sortedCells returns the live cells ordered by Z, then Y, then X.
+func (l *Life3D) sortedCells() []Cell3 {
+	return slices.SortedFunc(maps.Keys(l.cells), func(a, b Cell3) int {
+		if a.Z != b.Z {
+			return a.Z - b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+}
+
+// WritePLY writes the live cells as an ASCII PLY point cloud, one vertex per cell.
+func (l *Life3D) WritePLY(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	cells := l.sortedCells()
+	fmt.Fprintf(out, "ply\nformat ascii 1.0\ncomment 3D Life %s generation %d\n", l.rule, l.generation)
+	fmt.Fprintf(out, "element vertex %d\nproperty int x\nproperty int y\nproperty int z\nend_header\n", len(cells))
+	for _, cell := range cells {
+		fmt.Fprintf(out, "%d %d %d\n", cell.X, cell.Y, cell.Z)
+	}
+	return out.Flush()
+}
+
+// WriteVOX writes the universe in MagicaVoxel's VOX format, with every live cell a voxel
+// of palette colour 1. VOX models are at most 256 voxels along each axis.
+func (l *Life3D) WriteVOX(w io.Writer) error {
+	if l.size.X > 256 || l.size.Y > 256 || l.size.Z > 256 {
+		return fmt.Errorf("VOX models are at most 256 cells along each axis, universe is %dx%dx%d", l.size.X, l.size.Y, l.size.Z)
+	}
+	cells := l.sortedCells()
+	sizeChunk := binary.LittleEndian.AppendUint32(nil, uint32(l.size.X))
+	sizeChunk = binary.LittleEndian.AppendUint32(sizeChunk, uint32(l.size.Y))
+	sizeChunk = binary.LittleEndian.AppendUint32(sizeChunk, uint32(l.size.Z))
+	voxels := binary.LittleEndian.AppendUint32(nil, uint32(len(cells)))
+	for _, cell := range cells {
+		voxels = append(voxels, byte(cell.X), byte(cell.Y), byte(cell.Z), 1)
+	}
+	children := slices.Concat(voxChunk("SIZE", sizeChunk, nil), voxChunk("XYZI", voxels, nil))
+
+	file := slices.Concat([]byte("VOX "), binary.LittleEndian.AppendUint32(nil, 150), voxChunk("MAIN", nil, children))
+	_, err := w.Write(file)
+	return err
+}
+
+// voxChunk encodes a VOX chunk: its id, content and children sizes, content and children.
+func voxChunk(id string, content, children []byte) []byte {
+	chunk := []byte(id)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(content)))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(children)))
+	return slices.Concat(chunk, content, children)
+}
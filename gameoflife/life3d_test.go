@@ -0,0 +1,128 @@
+package gameoflife
+
+import (
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseRule3D(t *testing.T) {
+	tests := []struct {
+		input, want string
+		survival    []int
+		birth       []int
+	}{
+		{"4555", "4555", []int{4, 5}, []int{5}},
+		{"5766", "5766", []int{5, 6, 7}, []int{6}},
+		{"b5/s45", "4555", []int{4, 5}, []int{5}},
+		{"5,7,6,6", "5766", []int{5, 6, 7}, []int{6}},
+		{"B5,14/S4,5", "B5,14/S4,5", []int{4, 5}, []int{5, 14}},
+		{"10,12,11,11", "10,12,11,11", []int{10, 11, 12}, []int{11}},
+		{"B5,7/S10", "B5,7/S10", []int{10}, []int{5, 7}},
+		{"B/S10,", "B/S10,", []int{10}, nil},
+		{"B5/S", "B5/S", nil, []int{5}},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRule3D(tt.input)
+		if err != nil {
+			t.Errorf("ParseRule3D(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("ParseRule3D(%q).String() = %q; want %q", tt.input, got, tt.want)
+		}
+		if got := countList(rule.Survival); !slices.Equal(got, tt.survival) {
+			t.Errorf("ParseRule3D(%q) survives on %v; want %v", tt.input, got, tt.survival)
+		}
+		if got := countList(rule.Birth); !slices.Equal(got, tt.birth) {
+			t.Errorf("ParseRule3D(%q) births on %v; want %v", tt.input, got, tt.birth)
+		}
+	}
+
+	for _, input := range []string{"455", "B5/S4,27", "4505", "5455", "B5/X4", "4a55"} {
+		if _, err := ParseRule3D(input); err == nil {
+			t.Errorf("ParseRule3D(%q) succeeded; want an error", input)
+		}
+	}
+}
+
+func TestLife3D_StepMatchesBruteForce(t *testing.T) {
+	rule, _ := ParseRule3D("B5/S456")
+	l, err := NewLife3D(5, 4, 6, rule)
+	if err != nil {
+		t.Fatalf("NewLife3D() error = %v", err)
+	}
+	l.Soup(4, 0.4, 3)
+
+	for generation := 1; generation <= 10; generation++ {
+		want := make(map[Cell3]struct{})
+		for x := range 5 {
+			for y := range 4 {
+				for z := range 6 {
+					count := 0
+					for _, offset := range l.neighbours {
+						if l.Alive(Cell3{x + offset.X, y + offset.Y, z + offset.Z}) {
+							count++
+						}
+					}
+					alive := l.Alive(Cell3{x, y, z})
+					if alive && rule.Survival[count] || !alive && rule.Birth[count] {
+						want[Cell3{x, y, z}] = struct{}{}
+					}
+				}
+			}
+		}
+		l.Step()
+		if !maps.Equal(l.Cells(), want) {
+			t.Fatalf("generation %d = %v; want %v", generation, l.Cells(), want)
+		}
+	}
+
+	if _, err := NewLife3D(2, 5, 5, rule); err == nil {
+		t.Errorf("NewLife3D() with a side of 2 succeeded; want an error")
+	}
+}
+
+func TestLife3D_Exports(t *testing.T) {
+	rule, _ := ParseRule3D("4555")
+	l, _ := NewLife3D(4, 5, 6, rule)
+	cells := []Cell3{{0, 0, 0}, {3, 4, 5}, {1, 2, 3}}
+	for _, cell := range cells {
+		l.Set(cell, true)
+	}
+
+	var ply bytes.Buffer
+	if err := l.WritePLY(&ply); err != nil {
+		t.Fatalf("WritePLY() error = %v", err)
+	}
+	header, body, ok := strings.Cut(ply.String(), "end_header\n")
+	if !ok || !strings.Contains(header, "element vertex 3\n") {
+		t.Errorf("WritePLY() header = %q; want 3 vertices", header)
+	}
+	if body != "0 0 0\n1 2 3\n3 4 5\n" {
+		t.Errorf("WritePLY() vertices = %q", body)
+	}
+
+	var vox bytes.Buffer
+	if err := l.WriteVOX(&vox); err != nil {
+		t.Fatalf("WriteVOX() error = %v", err)
+	}
+	data := vox.Bytes()
+	if string(data[:4]) != "VOX " || binary.LittleEndian.Uint32(data[4:]) != 150 || string(data[8:12]) != "MAIN" {
+		t.Fatalf("WriteVOX() header = %q", data[:12])
+	}
+	size := data[20:]
+	if string(size[:4]) != "SIZE" || binary.LittleEndian.Uint32(size[12:]) != 4 || binary.LittleEndian.Uint32(size[16:]) != 5 || binary.LittleEndian.Uint32(size[20:]) != 6 {
+		t.Errorf("WriteVOX() SIZE chunk = %v", size[:24])
+	}
+	xyzi := size[24:]
+	if string(xyzi[:4]) != "XYZI" || binary.LittleEndian.Uint32(xyzi[12:]) != 3 || len(xyzi) != 16+4*3 {
+		t.Errorf("WriteVOX() XYZI chunk = %v", xyzi)
+	}
+	if got := xyzi[16+4*2 : 16+4*3]; !bytes.Equal(got, []byte{3, 4, 5, 1}) {
+		t.Errorf("WriteVOX() last voxel = %v; want [3 4 5 1]", got)
+	}
+}
@@ -0,0 +1,214 @@
+package gameoflife
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// MatchOptions configure FindPattern.
+type MatchOptions struct {
+	// AllOrientations also finds rotated and reflected copies of the pattern.
+	AllOrientations bool
+	// AllPhases also finds the other phases of an oscillating or moving pattern,
+	// as evolved under the universe's rules.
+	AllPhases bool
+	// DeadBorder is the width of the border around the pattern that must be dead
+	// for a match; 0 allows the pattern to touch other live cells.
+	DeadBorder int
+}
+
+// DefaultMatchOptions finds isolated copies of a pattern in any phase and orientation.
+func DefaultMatchOptions() MatchOptions {
+	return MatchOptions{AllOrientations: true, AllPhases: true, DeadBorder: 1}
+}
+
+// Match is one occurrence of a pattern in the universe.
+type Match struct {
+	// Cells are the matched live cells, in universe coordinates.
+	Cells []Cell
+	// Origin is where the top-left corner of the matched variant's bounding box lies.
+	Origin Cell
+	// Phase and Orientation identify the variant of the pattern that matched:
+	// Phase is the number of generations it was evolved, Orientation an index into
+	// the 8 rotations and reflections.
+	Phase       int
+	Orientation int
+}
+
+// matchVariant is one phase and orientation of the searched pattern, with its bounding
+// box at the origin and the cells of its dead border.
+type matchVariant struct {
+	cells       []Cell
+	border      []Cell
+	phase       int
+	orientation int
+}
+
+// FindPattern locates every occurrence of the pattern in the universe.
+// Every live cell is tried as the position of the first cell of each variant, so the
+// cost grows with the number of live cells rather than the size of the universe.
+func (g *GameOfLife) FindPattern(pattern map[Cell]struct{}, opts MatchOptions) []Match {
+	if len(pattern) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	seen := make(map[string]struct{})
+	for _, variant := range g.matchVariants(pattern, opts) {
+		anchor := variant.cells[0]
+		for live := range g.universe {
+			origin := Cell{live.R - anchor.R, live.C - anchor.C}
+			matched, ok := g.matchAt(variant, origin)
+			if !ok {
+				continue
+			}
+			slices.SortFunc(matched, compareCells)
+			key := fmt.Sprint(matched)
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			if wrapped, ok := g.topology.wrap(origin, g.numRows, g.numCols); ok {
+				origin = wrapped
+			}
+			matches = append(matches, Match{Cells: matched, Origin: origin, Phase: variant.phase, Orientation: variant.orientation})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b Match) int { return compareCells(a.Cells[0], b.Cells[0]) })
+	return matches
+}
+
+// matchAt checks whether the variant lies at origin, returning its cells in universe coordinates.
+func (g *GameOfLife) matchAt(variant matchVariant, origin Cell) ([]Cell, bool) {
+	matched := make([]Cell, 0, len(variant.cells))
+	for _, cell := range variant.cells {
+		wrapped, ok := g.neighbour(origin, cell)
+		if _, alive := g.universe[wrapped]; !ok || !alive {
+			return nil, false
+		}
+		matched = append(matched, wrapped)
+	}
+	// cells beyond an edge that is not joined are dead, so border there is always clear
+	for _, cell := range variant.border {
+		wrapped, ok := g.neighbour(origin, cell)
+		if _, alive := g.universe[wrapped]; ok && alive {
+			return nil, false
+		}
+	}
+	return matched, true
+}
+
+// matchVariants returns the distinct phases and orientations of the pattern to look for.
+func (g *GameOfLife) matchVariants(pattern map[Cell]struct{}, opts MatchOptions) []matchVariant {
+	phases := []map[Cell]struct{}{pattern}
+	if opts.AllPhases {
+		const maxPhases = 64
+		if behaviour := classifyAlone(pattern, g.rules, maxPhases); behaviour.Period > 1 {
+			phases = evolveAlone(pattern, g.rules, behaviour.Period-1)
+		}
+	}
+	orientations := symmetries[:1]
+	if opts.AllOrientations {
+		orientations = symmetries
+	}
+
+	var variants []matchVariant
+	seen := make(map[string]struct{})
+	for phase, cells := range phases {
+		for orientation, symmetry := range orientations {
+			transformed := transformCells(cells, symmetry)
+			key := shapeKey(transformed)
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			variant := matchVariant{cells: normalisedCells(transformed), phase: phase, orientation: orientation}
+			variant.border = deadBorder(variant.cells, opts.DeadBorder)
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+// deadBorder returns the cells within the given distance of the pattern that are not
+// part of it.
+func deadBorder(cells []Cell, width int) []Cell {
+	if width <= 0 {
+		return nil
+	}
+	inPattern := make(map[Cell]struct{}, len(cells))
+	for _, cell := range cells {
+		inPattern[cell] = struct{}{}
+	}
+	border := make(map[Cell]struct{})
+	for _, cell := range cells {
+		for dr := -width; dr <= width; dr++ {
+			for dc := -width; dc <= width; dc++ {
+				neighbour := Cell{cell.R + dr, cell.C + dc}
+				if _, ok := inPattern[neighbour]; !ok {
+					border[neighbour] = struct{}{}
+				}
+			}
+		}
+	}
+	return cellsOf(border)
+}
+
+// CatalogueEntryByName returns the catalogue entry with the given name.
+func CatalogueEntryByName(name string) (CatalogueEntry, bool) {
+	for _, entry := range Catalogue() {
+		if strings.EqualFold(entry.Name, name) {
+			return entry, true
+		}
+	}
+	return CatalogueEntry{}, false
+}
+
+// ResolvePattern turns a user-supplied pattern reference into cells: a catalogue name
+// (e.g. "glider"), an apgcode (e.g. "xq4_153") or the path of a pattern file.
+func ResolvePattern(reference string) (*Pattern, error) {
+	if entry, ok := CatalogueEntryByName(reference); ok {
+		return &Pattern{Name: entry.Name, Cells: entry.Cells}, nil
+	}
+	if _, err := os.Stat(reference); err == nil {
+		return LoadPattern(reference)
+	}
+	if p, err := DecodeApgcode(reference); err == nil && len(p.Cells) > 0 {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%q is neither a catalogue name, a pattern file nor an apgcode", reference)
+}
+
+// HighlightPattern makes Display mark every occurrence of the pattern in a different colour.
+// A nil pattern turns highlighting off.
+func (g *GameOfLife) HighlightPattern(pattern map[Cell]struct{}, opts MatchOptions) {
+	if pattern == nil {
+		g.highlight = nil
+		return
+	}
+	g.highlight = &highlightQuery{pattern: pattern, opts: opts}
+}
+
+// highlightQuery is the pattern Display highlights.
+type highlightQuery struct {
+	pattern map[Cell]struct{}
+	opts    MatchOptions
+}
+
+// highlightedCells returns the cells of all matches of the highlighted pattern.
+func (g *GameOfLife) highlightedCells() map[Cell]struct{} {
+	if g.highlight == nil {
+		return nil
+	}
+	cells := make(map[Cell]struct{})
+	for _, match := range g.FindPattern(g.highlight.pattern, g.highlight.opts) {
+		for _, cell := range match.Cells {
+			cells[cell] = struct{}{}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,87 @@
+package gameoflife
+
+import "testing"
+
+func TestFindPattern(t *testing.T) {
+	g := CreateSeedUniverse(40, 40, Default, RuleFactory(ConwayRuleType))
+	g.universe = map[Cell]struct{}{}
+	place := func(drawing string, r, c int) {
+		for cell := range parseDrawing(drawing) {
+			g.universe[g._wrapCellWithinUniverse(Cell{cell.R + r, cell.C + c})] = struct{}{}
+		}
+	}
+	place(".O./..O/OOO", 2, 2)   // glider
+	place("O.O/.OO/.O.", 10, 10) // glider, other phase
+	place("OOO/O../.O.", 20, 2)  // glider, rotated
+	place(".O./..O/OOO", 38, 38) // glider across the wrapped corner
+	place(".O./..O/OOO", 30, 20) // glider touching a block
+	place("OO/OO", 33, 20)
+	glider, _ := CatalogueEntryByName("glider")
+
+	tests := []struct {
+		name string
+		opts MatchOptions
+		want int
+	}{
+		{"exact", MatchOptions{}, 3},
+		{"exact-isolated", MatchOptions{DeadBorder: 1}, 2},
+		// without a dead border, the glider and block together also contain
+		// a rotated glider and a glider in another phase
+		{"orientations", MatchOptions{AllOrientations: true}, 5},
+		{"all", MatchOptions{AllOrientations: true, AllPhases: true}, 7},
+		{"all-isolated", DefaultMatchOptions(), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := g.FindPattern(glider.Cells, tt.opts)
+			if len(matches) != tt.want {
+				t.Errorf("FindPattern() found %d matches %v; want %d", len(matches), matches, tt.want)
+			}
+			for _, match := range matches {
+				if len(match.Cells) != 5 {
+					t.Errorf("match %v has %d cells; want 5", match, len(match.Cells))
+				}
+			}
+		})
+	}
+}
+
+func TestFindPattern_SymmetricPatternMatchesOnce(t *testing.T) {
+	g := CreateSeedUniverse(10, 10, Default, RuleFactory(ConwayRuleType))
+	g.universe = parseDrawing("OO/OO")
+	if matches := g.FindPattern(parseDrawing("OO/OO"), DefaultMatchOptions()); len(matches) != 1 {
+		t.Errorf("FindPattern() found %d matches; want 1", len(matches))
+	}
+}
+
+func TestFindPattern_FollowsTopology(t *testing.T) {
+	// a block split across the left and right edges, and one against the top edge
+	g := CreateSeedUniverse(10, 10, Default, RuleFactory(ConwayRuleType))
+	g.universe = map[Cell]struct{}{{4, 9}: {}, {4, 0}: {}, {5, 9}: {}, {5, 0}: {}, {0, 4}: {}, {0, 5}: {}, {1, 4}: {}, {1, 5}: {}}
+	block := parseDrawing("OO/OO")
+	for _, tt := range []struct {
+		topology Topology
+		want     int
+	}{{TopologyTorus, 2}, {TopologyCylinder, 2}, {TopologyPlane, 1}} {
+		g.SetTopology(tt.topology)
+		if matches := g.FindPattern(block, DefaultMatchOptions()); len(matches) != tt.want {
+			t.Errorf("FindPattern() on a %s found %d matches %v; want %d", tt.topology, len(matches), matches, tt.want)
+		}
+	}
+	if _, ok := g.Colour(Cell{4, 10}); ok {
+		t.Errorf("Colour() of a cell beyond the plane's edge reports it alive")
+	}
+}
+
+func TestResolvePattern(t *testing.T) {
+	for _, reference := range []string{"glider", "Glider", "xq4_153"} {
+		p, err := ResolvePattern(reference)
+		if err != nil || len(p.Cells) != 5 {
+			t.Errorf("ResolvePattern(%q) = %v, %v; want a glider", reference, p, err)
+		}
+	}
+	if _, err := ResolvePattern("no-such-thing"); err == nil {
+		t.Errorf("ResolvePattern() of an unknown reference succeeded")
+	}
+}
@@ -0,0 +1,268 @@
+package gameoflife
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// MethuselahOptions configure FindMethuselahs. Zero values select the defaults.
+type MethuselahOptions struct {
+	// Size is the width and height of the box the starting patterns are drawn in (8).
+	Size int
+	// Population is the number of patterns in each generation of the search (50).
+	Population int
+	// Generations is the number of generations of the search (20).
+	Generations int
+	// TournamentSize is how many patterns compete to become a parent (3).
+	TournamentSize int
+	// MutationRate is the chance that each cell of a child is flipped (0.05).
+	MutationRate float64
+	// CrossoverRate is the chance that a child mixes two parents rather than copying one (0.7).
+	CrossoverRate float64
+	// Elite is the number of best patterns carried over unchanged to the next generation (2).
+	Elite int
+	// MaxSteps is how long a pattern is run before it is considered to never stabilise (5000).
+	MaxSteps int
+	// PopulationWeight is how much each cell of the final population adds to the
+	// fitness, on top of one per generation of lifespan (0.1).
+	PopulationWeight float64
+	Rules            []Rule
+	// Seed makes the search reproducible: the same options always give the same results.
+	Seed uint64
+	// Workers is the number of patterns simulated in parallel, defaulting to the number of CPUs.
+	Workers int
+	// Leaderboard is the number of best distinct patterns returned (10).
+	Leaderboard int
+	// Progress, if set, receives a line after every generation of the search.
+	Progress io.Writer
+}
+
+func (o *MethuselahOptions) setDefaults() {
+	setDefault := func(value *int, fallback int) {
+		if *value <= 0 {
+			*value = fallback
+		}
+	}
+	setDefault(&o.Size, 8)
+	setDefault(&o.Population, 50)
+	setDefault(&o.Generations, 20)
+	setDefault(&o.TournamentSize, 3)
+	setDefault(&o.Elite, 2)
+	setDefault(&o.MaxSteps, 5000)
+	setDefault(&o.Workers, runtime.NumCPU())
+	setDefault(&o.Leaderboard, 10)
+	if o.MutationRate <= 0 {
+		o.MutationRate = 0.05
+	}
+	if o.CrossoverRate <= 0 {
+		o.CrossoverRate = 0.7
+	}
+	if o.PopulationWeight <= 0 {
+		o.PopulationWeight = 0.1
+	}
+	if len(o.Rules) == 0 {
+		o.Rules = []Rule{ConwayRule{}}
+	}
+}
+
+// Methuselah is a starting pattern and how it evolved.
+type Methuselah struct {
+	// Cells are the starting pattern, in the coordinates of the Size x Size box.
+	Cells map[Cell]struct{}
+	// Lifespan is the number of generations until the population settled into a short cycle.
+	Lifespan int
+	// FinalPopulation is the population once settled.
+	FinalPopulation int
+	// Stabilised is false for patterns still active after MaxSteps; their fitness is zero,
+	// since they are usually growing forever rather than long-lived.
+	Stabilised bool
+	Fitness    float64
+}
+
+// FindMethuselahs searches for small patterns that take long to stabilise, with a genetic
+// algorithm: a population of random patterns in a Size x Size box is evolved by tournament
+// selection, crossover and mutation, with each pattern's fitness taken from running it.
+// It returns the best distinct patterns ever seen, fittest first.
+func FindMethuselahs(opts MethuselahOptions) []Methuselah {
+	opts.setDefaults()
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+	cellCount := opts.Size * opts.Size
+
+	population := make([][]bool, opts.Population)
+	for i := range population {
+		genome := make([]bool, cellCount)
+		for j := range genome {
+			genome[j] = rng.IntN(2) == 1
+		}
+		genome[rng.IntN(cellCount)] = true
+		population[i] = genome
+	}
+
+	evaluated := make(map[string]Methuselah)   // by genome
+	leaderboard := make(map[string]Methuselah) // by canonical shape
+	for generation := range opts.Generations {
+		scores := evaluateGenomes(population, evaluated, opts)
+		for i, genome := range population {
+			m := scores[i]
+			key := canonicalShapeKey(m.Cells)
+			if best, ok := leaderboard[key]; !ok || m.Fitness > best.Fitness {
+				leaderboard[key] = m
+			}
+			evaluated[genomeKey(genome)] = m
+		}
+
+		order := make([]int, len(population))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return compareFitness(scores[a], scores[b]) })
+		if opts.Progress != nil {
+			best := scores[order[0]]
+			fmt.Fprintf(opts.Progress, "generation %d: best lifespan %d, final population %d, fitness %.1f\n", generation, best.Lifespan, best.FinalPopulation, best.Fitness)
+		}
+		if generation == opts.Generations-1 {
+			break
+		}
+
+		next := make([][]bool, 0, opts.Population)
+		for _, i := range order[:min(opts.Elite, len(order))] {
+			next = append(next, population[i])
+		}
+		tournament := func() []bool {
+			winner := rng.IntN(len(population))
+			for range opts.TournamentSize - 1 {
+				if challenger := rng.IntN(len(population)); scores[challenger].Fitness > scores[winner].Fitness {
+					winner = challenger
+				}
+			}
+			return population[winner]
+		}
+		for len(next) < opts.Population {
+			child := slices.Clone(tournament())
+			if rng.Float64() < opts.CrossoverRate {
+				// rows above the cut come from one parent, the rest from the other,
+				// which keeps local structure intact better than mixing single cells
+				other := tournament()
+				cut := rng.IntN(cellCount/opts.Size+1) * opts.Size
+				copy(child[cut:], other[cut:])
+			}
+			for j := range child {
+				if rng.Float64() < opts.MutationRate {
+					child[j] = !child[j]
+				}
+			}
+			if !slices.Contains(child, true) {
+				child[rng.IntN(cellCount)] = true
+			}
+			next = append(next, child)
+		}
+		population = next
+	}
+
+	best := make([]Methuselah, 0, len(leaderboard))
+	for _, m := range leaderboard {
+		best = append(best, m)
+	}
+	slices.SortFunc(best, compareFitness)
+	return best[:min(opts.Leaderboard, len(best))]
+}
+
+// compareFitness orders the fittest first, breaking ties so the order does not depend on
+// map iteration.
+func compareFitness(a, b Methuselah) int {
+	switch {
+	case a.Fitness != b.Fitness:
+		if a.Fitness > b.Fitness {
+			return -1
+		}
+		return 1
+	case len(a.Cells) != len(b.Cells):
+		return len(a.Cells) - len(b.Cells)
+	default:
+		return slices.CompareFunc(normalisedCells(a.Cells), normalisedCells(b.Cells), compareCells)
+	}
+}
+
+// evaluateGenomes runs every genome not evaluated before, in parallel.
+func evaluateGenomes(genomes [][]bool, evaluated map[string]Methuselah, opts MethuselahOptions) []Methuselah {
+	scores := make([]Methuselah, len(genomes))
+	var pending []int
+	for i, genome := range genomes {
+		if m, ok := evaluated[genomeKey(genome)]; ok {
+			scores[i] = m
+		} else {
+			pending = append(pending, i)
+		}
+	}
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				scores[i] = runMethuselah(genomeCells(genomes[i], opts.Size), opts)
+			}
+		}()
+	}
+	for _, i := range pending {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return scores
+}
+
+// runMethuselah runs a pattern with CreateNextGeneration until its population settles.
+func runMethuselah(cells map[Cell]struct{}, opts MethuselahOptions) Methuselah {
+	m := Methuselah{Cells: cells}
+	// nothing moves faster than a cell a generation, so on a plane this large nothing
+	// reaches the edge within MaxSteps and escaping spaceships never come back
+	side := opts.Size + 2*opts.MaxSteps + 2
+	g := CreateSeedUniverse(side, side, Default, opts.Rules...)
+	g.SetTopology(TopologyPlane)
+	g.universe = make(map[Cell]struct{}, len(cells))
+	offset := (side - opts.Size) / 2
+	for cell := range cells {
+		g.universe[Cell{cell.R + offset, cell.C + offset}] = struct{}{}
+	}
+
+	populations := []int{len(g.universe)}
+	for g.generation < opts.MaxSteps {
+		g.CreateNextGeneration()
+		populations = append(populations, len(g.universe))
+		if period := populationPeriod(populations); period > 0 {
+			m.Stabilised = true
+			m.Lifespan = settleTime(populations, period)
+			m.FinalPopulation = len(g.universe)
+			m.Fitness = float64(m.Lifespan) + opts.PopulationWeight*float64(m.FinalPopulation)
+			break
+		}
+	}
+	return m
+}
+
+func genomeCells(genome []bool, size int) map[Cell]struct{} {
+	cells := make(map[Cell]struct{})
+	for i, alive := range genome {
+		if alive {
+			cells[Cell{i / size, i % size}] = struct{}{}
+		}
+	}
+	return cells
+}
+
+func genomeKey(genome []bool) string {
+	key := make([]byte, len(genome))
+	for i, alive := range genome {
+		if alive {
+			key[i] = 1
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,45 @@
+package gameoflife
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestRunMethuselah_RPentomino(t *testing.T) {
+	rPentomino := map[Cell]struct{}{{0, 1}: {}, {0, 2}: {}, {1, 0}: {}, {1, 1}: {}, {2, 1}: {}}
+	opts := MethuselahOptions{Size: 3}
+	opts.setDefaults()
+
+	m := runMethuselah(rPentomino, opts)
+	if !m.Stabilised || m.Lifespan < 1100 || m.Lifespan > 1110 {
+		t.Errorf("R-pentomino: stabilised %v after %d generations; want about 1103", m.Stabilised, m.Lifespan)
+	}
+	if m.FinalPopulation != 116 {
+		t.Errorf("R-pentomino: final population %d; want 116", m.FinalPopulation)
+	}
+}
+
+func TestFindMethuselahs(t *testing.T) {
+	opts := MethuselahOptions{Size: 4, Population: 12, Generations: 3, MaxSteps: 400, Seed: 7, Workers: 3, Leaderboard: 5}
+	best := FindMethuselahs(opts)
+	if len(best) != 5 {
+		t.Fatalf("leaderboard has %d entries; want 5", len(best))
+	}
+	if !slices.IsSortedFunc(best, compareFitness) {
+		t.Errorf("leaderboard is not sorted by fitness")
+	}
+	seen := make(map[string]bool)
+	for _, m := range best {
+		key := canonicalShapeKey(m.Cells)
+		if seen[key] {
+			t.Errorf("pattern %v appears twice on the leaderboard", normalisedCells(m.Cells))
+		}
+		seen[key] = true
+	}
+
+	opts.Workers = 1
+	if again := FindMethuselahs(opts); !reflect.DeepEqual(again, best) {
+		t.Errorf("the same seed gave a different leaderboard")
+	}
+}
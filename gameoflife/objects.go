@@ -0,0 +1,397 @@
+package gameoflife
+
+import (
+	"maps"
+	"slices"
+	"sort"
+)
+
+// Connectivity decides which live cells are considered touching when splitting
+// a universe into objects.
+type Connectivity int
+
+const (
+	// ConnectMoore joins cells that are horizontally, vertically or diagonally adjacent.
+	ConnectMoore Connectivity = iota
+	// ConnectVonNeumann joins only horizontally or vertically adjacent cells.
+	ConnectVonNeumann
+	// ConnectExtended joins cells up to two cells apart, which keeps objects that
+	// influence each other's neighbours together.
+	ConnectExtended
+)
+
+func (c Connectivity) offsets() []Cell {
+	var offsets []Cell
+	reach := 1
+	if c == ConnectExtended {
+		reach = 2
+	}
+	for dr := -reach; dr <= reach; dr++ {
+		for dc := -reach; dc <= reach; dc++ {
+			if dr == 0 && dc == 0 || c == ConnectVonNeumann && dr != 0 && dc != 0 {
+				continue
+			}
+			offsets = append(offsets, Cell{dr, dc})
+		}
+	}
+	return offsets
+}
+
+// AnalyserOptions configure how a universe is split into objects.
+type AnalyserOptions struct {
+	Connectivity Connectivity
+	// EvolutionSteps is the number of generations over which parts that interact
+	// are joined into a single object ("separation by evolution").
+	EvolutionSteps int
+	// MaxPeriod is the longest period looked for when classifying objects.
+	MaxPeriod int
+}
+
+// DefaultAnalyserOptions returns the options used by Census.
+func DefaultAnalyserOptions() AnalyserOptions {
+	return AnalyserOptions{Connectivity: ConnectMoore, EvolutionSteps: 4, MaxPeriod: catalogueMaxPeriod}
+}
+
+// Object is one independent part of a universe.
+type Object struct {
+	// Cells are the object's live cells in universe coordinates. Objects that cross
+	// the universe border are unwrapped, so coordinates may lie outside the grid.
+	Cells     map[Cell]struct{}
+	Behaviour Behaviour
+	// Name is the catalogue name of the object, empty if it is not in the catalogue.
+	Name string
+	// Apgcode is the object's canonical apgcode, see Apgcode.
+	Apgcode string
+}
+
+// Label returns the object's name, or its apgcode for objects not in the catalogue.
+func (o Object) Label() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.Apgcode
+}
+
+// Census is the list of objects in a universe and how many there are of each.
+type Census struct {
+	Objects []Object
+	Counts  map[string]int
+}
+
+// Labels returns the census labels, most common first.
+func (c Census) Labels() []string {
+	labels := slices.Collect(maps.Keys(c.Counts))
+	sort.Slice(labels, func(i, j int) bool {
+		if c.Counts[labels[i]] != c.Counts[labels[j]] {
+			return c.Counts[labels[i]] > c.Counts[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	return labels
+}
+
+// Census splits the current universe into objects and identifies them using the
+// default options.
+func (g *GameOfLife) Census() Census {
+	return g.Analyse(DefaultAnalyserOptions())
+}
+
+// Analyse splits the current universe into objects, classifies each object on its own
+// and identifies it against the catalogue (names are only assigned under Conway's rule,
+// however it is written).
+//
+// Cells that are connected, or whose descendants become connected within
+// EvolutionSteps generations, belong to the same object. Still lifes that merely
+// touch, such as two beehives sharing a corner, are then split into the smallest
+// parts that are stable on their own.
+func (g *GameOfLife) Analyse(opts AnalyserOptions) Census {
+	census := Census{Counts: make(map[string]int)}
+	conway := conwayRules(g.rules)
+
+	for _, group := range g.separateByEvolution(opts) {
+		cells := g.unwrapCells(group)
+		behaviour := classifyAlone(cells, g.rules, opts.MaxPeriod)
+
+		parts := []map[Cell]struct{}{cells}
+		if behaviour.Kind == KindStillLife {
+			if _, known := LookupCatalogue(cells); !known || !conway {
+				parts = splitStillLife(cells, g.rules)
+			}
+		}
+
+		for _, part := range parts {
+			object := Object{Cells: part, Behaviour: behaviour}
+			if len(parts) > 1 {
+				object.Behaviour = classifyAlone(part, g.rules, opts.MaxPeriod)
+			}
+			if entry, ok := LookupCatalogue(part); ok && conway {
+				object.Name = entry.Name
+			}
+			object.Apgcode = objectApgcode(part, object.Behaviour, g.rules)
+			census.Objects = append(census.Objects, object)
+			census.Counts[object.Label()]++
+		}
+	}
+	return census
+}
+
+// separateByEvolution groups the live cells into objects: cells connected under the
+// chosen connectivity start in the same group, and groups whose descendants touch
+// in any of the following generations are merged.
+func (g *GameOfLife) separateByEvolution(opts AnalyserOptions) []map[Cell]struct{} {
+	parent := make(map[Cell]Cell, len(g.universe))
+	var find func(c Cell) Cell
+	find = func(c Cell) Cell {
+		for parent[c] != c {
+			parent[c] = parent[parent[c]]
+			c = parent[c]
+		}
+		return c
+	}
+	union := func(a, b Cell) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[ra] = rb
+		}
+	}
+
+	for _, component := range g.components(opts.Connectivity.offsets()) {
+		for _, cell := range component {
+			parent[cell] = component[0]
+		}
+	}
+
+	// label[c] is the generation-0 cell whose group the live cell c belongs to
+	label := make(map[Cell]Cell, len(g.universe))
+	for cell := range g.universe {
+		label[cell] = cell
+	}
+	scratch := &GameOfLife{
+		universe:          g.universe,
+		numRows:           g.numRows,
+		numCols:           g.numCols,
+		neighbouringCells: g.neighbouringCells,
+		rules:             g.rules,
+		topology:          g.topology,
+	}
+	// a cell's ancestors are the live cells around it, and the cell itself
+	ancestors := append(ConnectMoore.offsets(), Cell{})
+	for range opts.EvolutionSteps {
+		previous := scratch.universe
+		scratch.CreateNextGeneration()
+		next := make(map[Cell]Cell, len(scratch.universe))
+		for cell := range scratch.universe {
+			var group *Cell
+			for _, offset := range ancestors {
+				ancestor, ok := scratch.neighbour(cell, offset)
+				if _, alive := previous[ancestor]; !ok || !alive {
+					continue
+				}
+				if group == nil {
+					l := label[ancestor]
+					group = &l
+				} else {
+					union(*group, label[ancestor])
+				}
+			}
+			if group != nil {
+				next[cell] = *group
+			}
+		}
+		label = next
+		// descendants that touch join their ancestors' groups
+		for _, component := range scratch.components(opts.Connectivity.offsets()) {
+			for _, cell := range component[1:] {
+				if a, ok := label[cell]; ok {
+					if b, ok := label[component[0]]; ok {
+						union(a, b)
+					}
+				}
+			}
+		}
+	}
+
+	groups := make(map[Cell]map[Cell]struct{})
+	for cell := range g.universe {
+		root := find(cell)
+		if groups[root] == nil {
+			groups[root] = make(map[Cell]struct{})
+		}
+		groups[root][cell] = struct{}{}
+	}
+
+	result := slices.Collect(maps.Values(groups))
+	// deterministic order: by top-left cell
+	slices.SortFunc(result, func(a, b map[Cell]struct{}) int {
+		ma, _, _ := boundingBox(a)
+		mb, _, _ := boundingBox(b)
+		return compareCells(ma, mb)
+	})
+	return result
+}
+
+// conwayRules reports whether the rules together are Conway's Life, B3/S23, whether
+// given as ConwayRule, as a rulestring or otherwise.
+func conwayRules(rules []Rule) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	for count := range 9 {
+		for _, alive := range []bool{false, true} {
+			next := false
+			for _, rule := range rules {
+				totalistic, ok := rule.(TotalisticRule)
+				if !ok {
+					return false
+				}
+				next = next || totalistic.Next(alive, count)
+			}
+			if next != (ConwayRule{}).Next(alive, count) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// unwrapCells shifts the cells of an object that crosses an edge the topology joins so
+// that the object is contiguous. The object is taken to start just after the largest
+// run of empty rows (and columns), wrapping around the edge. On a Klein bottle the
+// cells moved across the top and bottom edge are mirrored, as they are when crossing it.
+func (g *GameOfLife) unwrapCells(cells map[Cell]struct{}) map[Cell]struct{} {
+	joinedRows := g.topology == TopologyTorus || g.topology == TopologyKlein
+	joinedCols := g.topology != TopologyPlane
+
+	firstRow := 0
+	if joinedRows {
+		rows := make([]bool, g.numRows)
+		for cell := range cells {
+			rows[cell.R] = true
+		}
+		firstRow = unwrapStart(rows)
+	}
+	if firstRow > 0 {
+		shifted := make(map[Cell]struct{}, len(cells))
+		for cell := range cells {
+			if cell.R < firstRow {
+				cell.R += g.numRows
+				if g.topology == TopologyKlein {
+					cell.C = g.numCols - 1 - cell.C
+				}
+			}
+			shifted[cell] = struct{}{}
+		}
+		cells = shifted
+	}
+
+	firstCol := 0
+	if joinedCols {
+		cols := make([]bool, g.numCols)
+		for cell := range cells {
+			cols[cell.C] = true
+		}
+		firstCol = unwrapStart(cols)
+	}
+	if firstCol == 0 {
+		return cells
+	}
+	unwrapped := make(map[Cell]struct{}, len(cells))
+	for cell := range cells {
+		if cell.C < firstCol {
+			cell.C += g.numCols
+		}
+		unwrapped[cell] = struct{}{}
+	}
+	return unwrapped
+}
+
+// unwrapStart returns the index following the longest circular run of unoccupied
+// positions, or 0 if that run already spans the end of the range.
+func unwrapStart(occupied []bool) int {
+	n := len(occupied)
+	bestLength, bestStart := 0, 0
+	for start := 0; start < n; start++ {
+		// only consider runs beginning right after an occupied position
+		if occupied[start] || !occupied[(start+n-1)%n] {
+			continue
+		}
+		length := 0
+		for length < n && !occupied[(start+length)%n] {
+			length++
+		}
+		if length > bestLength {
+			bestLength, bestStart = length, start
+		}
+	}
+	if bestLength == 0 || bestStart+bestLength >= n {
+		return 0
+	}
+	return bestStart + bestLength
+}
+
+// splitStillLife splits a stable set of cells into the smallest parts that are each
+// stable on their own. Starting from a single cell, the part is grown with the live
+// cells around every cell that would change, until it is stable.
+func splitStillLife(cells map[Cell]struct{}, rules []Rule) []map[Cell]struct{} {
+	if len(cells) < 8 {
+		// no still life can be split into two parts this small
+		return []map[Cell]struct{}{cells}
+	}
+
+	moore := append(ConnectMoore.offsets(), Cell{})
+	order := slices.SortedFunc(maps.Keys(cells), compareCells)
+	for _, start := range order {
+		part := map[Cell]struct{}{start: {}}
+		for len(part) < len(cells) {
+			next := evolveAlone(part, rules, 1)[1]
+			grown := false
+			for changed := range symmetricDifference(part, next) {
+				for _, offset := range moore {
+					neighbour := Cell{changed.R + offset.R, changed.C + offset.C}
+					if _, ok := cells[neighbour]; !ok {
+						continue
+					}
+					if _, ok := part[neighbour]; !ok {
+						part[neighbour] = struct{}{}
+						grown = true
+					}
+				}
+			}
+			if !grown {
+				break
+			}
+		}
+
+		if len(part) == len(cells) || !isStable(part, rules) {
+			continue
+		}
+		rest := make(map[Cell]struct{}, len(cells)-len(part))
+		for cell := range cells {
+			if _, ok := part[cell]; !ok {
+				rest[cell] = struct{}{}
+			}
+		}
+		if isStable(rest, rules) {
+			return append(splitStillLife(part, rules), splitStillLife(rest, rules)...)
+		}
+	}
+	return []map[Cell]struct{}{cells}
+}
+
+func isStable(cells map[Cell]struct{}, rules []Rule) bool {
+	return len(symmetricDifference(cells, evolveAlone(cells, rules, 1)[1])) == 0
+}
+
+func symmetricDifference(a, b map[Cell]struct{}) map[Cell]struct{} {
+	diff := make(map[Cell]struct{})
+	for cell := range a {
+		if _, ok := b[cell]; !ok {
+			diff[cell] = struct{}{}
+		}
+	}
+	for cell := range b {
+		if _, ok := a[cell]; !ok {
+			diff[cell] = struct{}{}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,119 @@
+package gameoflife
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestLookupCatalogue_AnyPhaseAndOrientation(t *testing.T) {
+	tests := []struct {
+		name    string
+		drawing string
+		want    string
+		kind    ObjectKind
+	}{
+		{"block", "OO/OO", "block", KindStillLife},
+		{"beehive-rotated", ".O./O.O/O.O/.O.", "beehive", KindStillLife},
+		{"vertical-blinker", "O/O/O", "blinker", KindOscillator},
+		{"glider-other-phase", "O.O/.OO/.O.", "glider", KindSpaceship},
+		{"glider-reflected", ".O./O../OOO", "glider", KindSpaceship},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := LookupCatalogue(parseDrawing(tt.drawing))
+			if !ok {
+				t.Fatalf("LookupCatalogue(%q) found nothing", tt.drawing)
+			}
+			if entry.Name != tt.want || entry.Behaviour.Kind != tt.kind {
+				t.Errorf("LookupCatalogue(%q) = %s (%v); want %s (%v)", tt.drawing, entry.Name, entry.Behaviour.Kind, tt.want, tt.kind)
+			}
+		})
+	}
+}
+
+func TestCatalogue_Behaviours(t *testing.T) {
+	for _, entry := range Catalogue() {
+		if entry.Behaviour.Kind == KindUnknown || entry.Behaviour.Kind == KindExtinct {
+			t.Errorf("catalogue entry %q classified as %v", entry.Name, entry.Behaviour.Kind)
+		}
+	}
+}
+
+func TestCensus(t *testing.T) {
+	g := CreateSeedUniverse(30, 30, Default, RuleFactory(ConwayRuleType))
+	g.universe = map[Cell]struct{}{}
+	place := func(drawing string, r, c int) {
+		for cell := range parseDrawing(drawing) {
+			g.universe[g._wrapCellWithinUniverse(Cell{cell.R + r, cell.C + c})] = struct{}{}
+		}
+	}
+	place("OO/OO", 2, 2)
+	place("OO/OO", 2, 20)
+	place("OOO", 10, 10)
+	place(".O./..O/OOO", 20, 2)
+	// two beehives sharing a corner form one connected still life
+	place(".OO./O..O/.OO.", 20, 20)
+	place(".OO./O..O/.OO.", 23, 24)
+	// a block straddling the wrapped border
+	place("OO/OO", 29, 15)
+
+	census := g.Census()
+	want := map[string]int{"block": 3, "blinker": 1, "glider": 1, "beehive": 2}
+	if !maps.Equal(census.Counts, want) {
+		t.Errorf("Census().Counts = %v; want %v", census.Counts, want)
+	}
+	if labels := census.Labels(); labels[0] != "block" {
+		t.Errorf("Labels() = %v; want block first", labels)
+	}
+}
+
+func TestAnalyse_SeparatesByEvolution(t *testing.T) {
+	// a glider about to crash into a block is one interacting object
+	g := CreateSeedUniverse(20, 20, Default, RuleFactory(ConwayRuleType))
+	g.universe = map[Cell]struct{}{}
+	for cell := range parseDrawing(".O./..O/OOO") {
+		g.universe[cell] = struct{}{}
+	}
+	for cell := range parseDrawing("OO/OO") {
+		g.universe[Cell{cell.R + 5, cell.C + 4}] = struct{}{}
+	}
+
+	opts := DefaultAnalyserOptions()
+	opts.EvolutionSteps = 0
+	if got := len(g.Analyse(opts).Objects); got != 2 {
+		t.Errorf("without evolution got %d objects; want 2", got)
+	}
+	opts.EvolutionSteps = 8
+	if got := len(g.Analyse(opts).Objects); got != 1 {
+		t.Errorf("with evolution got %d objects; want 1", got)
+	}
+}
+
+func TestCensus_TopologiesAndRulestrings(t *testing.T) {
+	// a boat straddling the top and bottom edge, which a Klein bottle joins mirrored
+	boat := parseDrawing("OO./O.O/.O.")
+	for _, topology := range []Topology{TopologyTorus, TopologyKlein} {
+		for _, rules := range []string{"conway", "B3/S23"} {
+			g := CreateSeedUniverse(12, 12, Default, ParseRulesFromString(rules)...)
+			g.SetTopology(topology)
+			g.universe = map[Cell]struct{}{}
+			for cell := range boat {
+				wrapped, _ := topology.wrap(Cell{cell.R - 1, cell.C + 2}, 12, 12)
+				g.universe[wrapped] = struct{}{}
+			}
+			if got := g.Census().Counts; !maps.Equal(got, map[string]int{"boat": 1}) {
+				t.Errorf("%s under %s: Census().Counts = %v; want one boat", topology, rules, got)
+			}
+		}
+	}
+}
+
+func TestCensus_SmallUniverse(t *testing.T) {
+	// the blinker spans more than half of the universe without crossing its border
+	g := CreateSeedUniverse(5, 5, Default, RuleFactory(ConwayRuleType))
+	g.CreateNextGeneration()
+	if got := g.Census().Counts; !maps.Equal(got, map[string]int{"blinker": 1}) {
+		t.Errorf("Census().Counts = %v; want one blinker", got)
+	}
+}
@@ -0,0 +1,357 @@
+package gameoflife
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PatternFormat identifies a pattern file format.
+type PatternFormat int
+
+const (
+	// FormatUnknown means the format could not be detected.
+	FormatUnknown PatternFormat = iota
+	// FormatRLE is the run length encoded format used by Golly and the LifeWiki.
+	FormatRLE
+	// FormatPlaintext is the `.cells` format: one text line per row, `.` dead and `O` alive.
+	FormatPlaintext
+	// FormatLife105 is the Life 1.05 format: `#P x y` blocks of `.` and `*` rows.
+	FormatLife105
+	// FormatLife106 is the Life 1.06 format: one `x y` coordinate pair per live cell.
+	FormatLife106
+	// FormatMacrocell is Golly's `.mc` quadtree format; see ReadMacrocell.
+	FormatMacrocell
+)
+
+func (f PatternFormat) String() string {
+	switch f {
+	case FormatRLE:
+		return "rle"
+	case FormatPlaintext:
+		return "cells"
+	case FormatLife105:
+		return "life105"
+	case FormatLife106:
+		return "life106"
+	case FormatMacrocell:
+		return "mc"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePatternFormat returns the pattern format for a name as printed by PatternFormat.String.
+func ParsePatternFormat(name string) (PatternFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "rle":
+		return FormatRLE, nil
+	case "cells", "plaintext", "txt":
+		return FormatPlaintext, nil
+	case "life105", "1.05", "lif105":
+		return FormatLife105, nil
+	case "life106", "1.06", "lif106", "lif", "life":
+		return FormatLife106, nil
+	case "mc", "macrocell":
+		return FormatMacrocell, nil
+	default:
+		return FormatUnknown, fmt.Errorf("unknown pattern format %q", name)
+	}
+}
+
+// Pattern is a set of live cells read from or written to a pattern file.
+// Coordinates are relative to the pattern's own origin and may be negative;
+// Place maps them onto a universe grid.
+type Pattern struct {
+	Name     string
+	Comments []string
+	// Rule is the rulestring stored in the file, if any (e.g. "B3/S23").
+	Rule  string
+	Cells map[Cell]struct{}
+	// States are the states of live cells in multistate patterns, from 1; nil for
+	// two-state patterns. Colour schemes such as Immigration store colours here.
+	States map[Cell]int
+}
+
+// NewPattern returns a pattern holding the given cells.
+func NewPattern(cells map[Cell]struct{}) *Pattern {
+	return &Pattern{Cells: cells}
+}
+
+// Bounds returns the smallest rectangle containing every live cell.
+// An empty pattern has zero width and height.
+func (p *Pattern) Bounds() (minCell Cell, height, width int) {
+	return boundingBox(p.Cells)
+}
+
+// Place normalises the pattern to the universe grid: the pattern is shifted so that
+// its bounding box starts at the origin, then centred in a rows x cols universe.
+// It returns an error if the pattern does not fit.
+func (p *Pattern) Place(rows, cols int) (map[Cell]struct{}, error) {
+	offset, err := p.placement(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	placed := make(map[Cell]struct{}, len(p.Cells))
+	for cell := range p.Cells {
+		placed[Cell{cell.R + offset.R, cell.C + offset.C}] = struct{}{}
+	}
+	return placed, nil
+}
+
+// placement returns the offset Place moves the pattern by.
+func (p *Pattern) placement(rows, cols int) (Cell, error) {
+	minCell, height, width := p.Bounds()
+	if height > rows || width > cols {
+		return Cell{}, fmt.Errorf("pattern %s is %dx%d (rows x cols) and does not fit a %dx%d universe", p.displayName(), height, width, rows, cols)
+	}
+	return Cell{(rows-height)/2 - minCell.R, (cols-width)/2 - minCell.C}, nil
+}
+
+func (p *Pattern) hasCell(cell Cell) bool {
+	_, ok := p.Cells[cell]
+	return ok
+}
+
+func (p *Pattern) displayName() string {
+	if p.Name == "" {
+		return "(unnamed)"
+	}
+	return fmt.Sprintf("%q", p.Name)
+}
+
+// boundingBox returns the top-left corner and size of the smallest rectangle containing all cells.
+func boundingBox(cells map[Cell]struct{}) (minCell Cell, height, width int) {
+	first := true
+	var maxCell Cell
+	for cell := range cells {
+		if first {
+			minCell, maxCell = cell, cell
+			first = false
+			continue
+		}
+		minCell.R, minCell.C = min(minCell.R, cell.R), min(minCell.C, cell.C)
+		maxCell.R, maxCell.C = max(maxCell.R, cell.R), max(maxCell.C, cell.C)
+	}
+	if first {
+		return Cell{}, 0, 0
+	}
+	return minCell, maxCell.R - minCell.R + 1, maxCell.C - minCell.C + 1
+}
+
+// DetectPatternFormat guesses the format of a pattern from its file name and first bytes.
+// Content wins over the extension when the content is unambiguous.
+func DetectPatternFormat(name string, head []byte) PatternFormat {
+	if format := sniffPatternFormat(head); format != FormatUnknown {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".rle":
+		return FormatRLE
+	case ".cells":
+		return FormatPlaintext
+	case ".lif", ".life":
+		return FormatLife106
+	case ".mc":
+		return FormatMacrocell
+	}
+	return FormatUnknown
+}
+
+// sniffPatternFormat looks at the first meaningful line of the content.
+func sniffPatternFormat(head []byte) PatternFormat {
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		switch {
+		case len(line) == 0:
+			continue
+		case bytes.HasPrefix(line, []byte("#Life 1.06")):
+			return FormatLife106
+		case bytes.HasPrefix(line, []byte("#Life 1.05")):
+			return FormatLife105
+		case bytes.HasPrefix(line, []byte("[M2]")):
+			return FormatMacrocell
+		case line[0] == '!':
+			return FormatPlaintext
+		case line[0] == '#':
+			// RLE comments look the same as Life 1.05 ones, keep looking
+			continue
+		case line[0] == 'x' && bytes.Contains(line, []byte("=")):
+			return FormatRLE
+		case len(bytes.Trim(line, ".O*")) == 0:
+			return FormatPlaintext
+		}
+		return FormatUnknown
+	}
+	return FormatUnknown
+}
+
+// ReadPattern reads a pattern in the given format. With FormatUnknown the format
+// is sniffed from the content.
+func ReadPattern(r io.Reader, format PatternFormat) (*Pattern, error) {
+	br := bufio.NewReader(r)
+	if format == FormatUnknown {
+		head, _ := br.Peek(4096)
+		if format = sniffPatternFormat(head); format == FormatUnknown {
+			return nil, fmt.Errorf("cannot detect pattern format")
+		}
+	}
+
+	var p *Pattern
+	var err error
+	switch format {
+	case FormatRLE:
+		p, err = readRLE(br)
+	case FormatPlaintext:
+		p, err = readPlaintext(br)
+	case FormatLife105:
+		p, err = readLife105(br)
+	case FormatLife106:
+		p, err = readLife106(br)
+	case FormatMacrocell:
+		p, err = readMacrocellPattern(br)
+	default:
+		return nil, fmt.Errorf("unsupported pattern format %v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExtent(p.Cells); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// checkExtent returns an error if the bounding box of the cells is too tall or wide for
+// its height or width to fit in an int, as can happen with coordinates near the limits.
+func checkExtent(cells map[Cell]struct{}) error {
+	first := true
+	var minCell, maxCell Cell
+	for cell := range cells {
+		if first {
+			minCell, maxCell, first = cell, cell, false
+			continue
+		}
+		minCell.R, minCell.C = min(minCell.R, cell.R), min(minCell.C, cell.C)
+		maxCell.R, maxCell.C = max(maxCell.R, cell.R), max(maxCell.C, cell.C)
+	}
+	// the differences may wrap around as ints but not as uint64s
+	if uint64(maxCell.R-minCell.R) >= math.MaxInt || uint64(maxCell.C-minCell.C) >= math.MaxInt {
+		return fmt.Errorf("pattern spans rows %d..%d and columns %d..%d, too far apart to measure", minCell.R, maxCell.R, minCell.C, maxCell.C)
+	}
+	return nil
+}
+
+// WritePattern writes the pattern in the given format.
+func WritePattern(w io.Writer, p *Pattern, format PatternFormat) error {
+	bw := bufio.NewWriter(w)
+	var err error
+	switch format {
+	case FormatRLE:
+		err = writeRLE(bw, p)
+	case FormatPlaintext:
+		err = writePlaintext(bw, p)
+	case FormatLife105:
+		err = writeLife105(bw, p)
+	case FormatLife106:
+		err = writeLife106(bw, p)
+	case FormatMacrocell:
+		err = writeMacrocellPattern(bw, p)
+	default:
+		return fmt.Errorf("unsupported pattern format %v", format)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// LoadPattern reads the pattern file at path, detecting its format from the
+// extension and content.
+func LoadPattern(path string) (*Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(4096)
+	format := DetectPatternFormat(path, head)
+	if format == FormatUnknown {
+		return nil, fmt.Errorf("%s: cannot detect pattern format", path)
+	}
+
+	p, err := ReadPattern(br, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return p, nil
+}
+
+// CreateUniverseFromPattern creates a universe of the given size with the pattern
+// centred in it. It returns an error if the dimensions are invalid or the pattern
+// does not fit.
+func CreateUniverseFromPattern(row, col int, pattern *Pattern, rules ...Rule) (*GameOfLife, error) {
+	g := CreateSeedUniverse(row, col, Default, rules...)
+	if g == nil {
+		return nil, fmt.Errorf("invalid universe dimensions %dx%d", row, col)
+	}
+
+	cells, err := pattern.Place(row, col)
+	if err != nil {
+		return nil, err
+	}
+	g.universe = cells
+
+	// multistate patterns of a colour scheme, such as Golly's Immigration files, keep their colours
+	if scheme, err := ParseColourScheme(pattern.Rule); err == nil && scheme != Monochrome && pattern.States != nil {
+		offset, _ := pattern.placement(row, col)
+		colours := make(map[Cell]int, len(pattern.States))
+		for cell, state := range pattern.States {
+			colours[Cell{cell.R + offset.R, cell.C + offset.C}] = state
+		}
+		g.SetColours(scheme, colours)
+	}
+	return g, nil
+}
+
+// Pattern returns the current universe as a pattern in universe coordinates.
+func (g *GameOfLife) Pattern() *Pattern {
+	p := &Pattern{Cells: make(map[Cell]struct{}, len(g.universe))}
+	for cell := range g.universe {
+		p.Cells[cell] = struct{}{}
+	}
+	p.Rule = Rulestring(g.rules)
+	if g.colours != nil {
+		p.States = maps.Clone(g.colours)
+		if g.colourScheme != Monochrome && p.Rule == "B3/S23" {
+			p.Rule = g.colourScheme.String()
+		}
+	}
+	return p
+}
+
+// Rulestring returns the B/S rulestring pattern files use for a set of rules, or an empty
+// string if the rules cannot be written as one.
+func Rulestring(rules []Rule) string {
+	if len(rules) != 1 {
+		return ""
+	}
+	switch rule := rules[0].(type) {
+	case ConwayRule:
+		return "B3/S23"
+	case LifeLikeRule:
+		return rule.String()
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// readLife106 parses the Life 1.06 format: a `#Life 1.06` header followed by one
+// `x y` pair per live cell. Coordinates are commonly centred on the origin and may be negative.
+func readLife106(r *bufio.Reader) (*Pattern, error) {
+	p := &Pattern{Cells: make(map[Cell]struct{})}
+	lineNumber := 0
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		lineNumber++
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			x, y, parseErr := parseCoordinates(strings.Fields(trimmed))
+			if parseErr != nil {
+				return nil, fmt.Errorf("life 1.06 line %d: %w", lineNumber, parseErr)
+			}
+			p.Cells[Cell{y, x}] = struct{}{}
+		}
+
+		if err == io.EOF {
+			return p, nil
+		}
+	}
+}
+
+// readLife105 parses the Life 1.05 format:
+//
+//	#Life 1.05
+//	#D description
+//	#N
+//	#P -1 -1
+//	.*.
+//	..*
+//	***
+//
+// Each `#P x y` line starts a block of rows whose top-left cell is at (x, y).
+// `#N` selects the normal Conway rule and `#R s/b` a custom one.
+func readLife105(r *bufio.Reader) (*Pattern, error) {
+	p := &Pattern{Cells: make(map[Cell]struct{})}
+	origin := Cell{}
+	row := 0
+	lineNumber := 0
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		lineNumber++
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "#P"):
+			x, y, parseErr := parseCoordinates(strings.Fields(trimmed[2:]))
+			if parseErr != nil {
+				return nil, fmt.Errorf("life 1.05 line %d: %w", lineNumber, parseErr)
+			}
+			origin, row = Cell{y, x}, 0
+		case strings.HasPrefix(trimmed, "#D"), strings.HasPrefix(trimmed, "#C"):
+			p.Comments = append(p.Comments, strings.TrimSpace(trimmed[2:]))
+		case strings.HasPrefix(trimmed, "#N"):
+			p.Rule = "B3/S23"
+		case strings.HasPrefix(trimmed, "#R"):
+			// Life 1.05 writes rules survival first: `#R 23/3`
+			survive, birth, _ := strings.Cut(strings.TrimSpace(trimmed[2:]), "/")
+			p.Rule = "B" + birth + "/S" + survive
+		case strings.HasPrefix(trimmed, "#"):
+		default:
+			for col, ch := range trimmed {
+				switch ch {
+				case '.':
+				case '*', 'O', 'o':
+					p.Cells[Cell{origin.R + row, origin.C + col}] = struct{}{}
+				default:
+					return nil, fmt.Errorf("life 1.05 line %d: unexpected character %q", lineNumber, ch)
+				}
+			}
+			row++
+		}
+
+		if err == io.EOF {
+			return p, nil
+		}
+	}
+}
+
+// writeLife106 writes one `x y` line per live cell, in row-major order, keeping the
+// pattern's own coordinates.
+func writeLife106(w *bufio.Writer, p *Pattern) error {
+	w.WriteString("#Life 1.06\n")
+	cells := cellsOf(p.Cells)
+	slices.SortFunc(cells, compareCells)
+	for _, cell := range cells {
+		if _, err := fmt.Fprintf(w, "%d %d\n", cell.C, cell.R); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLife105 writes the whole pattern as a single `#P` block.
+func writeLife105(w *bufio.Writer, p *Pattern) error {
+	minCell, height, width := p.Bounds()
+
+	w.WriteString("#Life 1.05\n")
+	if p.Name != "" {
+		fmt.Fprintf(w, "#D %s\n", p.Name)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(w, "#D %s\n", comment)
+	}
+	if p.Rule == "" || strings.EqualFold(p.Rule, "B3/S23") {
+		w.WriteString("#N\n")
+	} else if birth, survive, ok := strings.Cut(strings.ToUpper(p.Rule), "/"); ok {
+		fmt.Fprintf(w, "#R %s/%s\n", strings.TrimPrefix(survive, "S"), strings.TrimPrefix(birth, "B"))
+	}
+	fmt.Fprintf(w, "#P %d %d\n", minCell.C, minCell.R)
+
+	for r := range height {
+		line := make([]byte, width)
+		for c := range width {
+			line[c] = '.'
+			if p.hasCell(Cell{minCell.R + r, minCell.C + c}) {
+				line[c] = '*'
+			}
+		}
+		trimmed := strings.TrimRight(string(line), ".")
+		if trimmed == "" {
+			// empty rows keep a single `.` so they are not mistaken for blank lines
+			trimmed = "."
+		}
+		w.WriteString(trimmed)
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseCoordinates parses an `x y` pair.
+func parseCoordinates(fields []string) (x, y int, err error) {
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected `x y`, got %q", strings.Join(fields, " "))
+	}
+	if x, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if y, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
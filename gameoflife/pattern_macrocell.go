@@ -0,0 +1,253 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxExpandedCells is the largest macrocell pattern ReadPattern expands into a cell set.
+// Bigger patterns must be handled as a QuadTree via ReadMacrocell.
+const maxExpandedCells = 1 << 24
+
+// Macrocell is a pattern read from or written to Golly's macrocell (`.mc`) format.
+type Macrocell struct {
+	Rule       string
+	Generation int
+	Comments   []string
+	Tree       *QuadTree
+}
+
+// ReadMacrocell parses Golly's macrocell format:
+//
+//	[M2] (golly 4.0)
+//	#R B3/S23
+//	.*$..*$***$
+//	4 1 0 0 0
+//
+// After the header and `#` lines, every line defines one node, numbered from 1.
+// A line starting with `.`, `*` or `$` is an 8x8 leaf given as rows terminated by `$`.
+// A line `k nw ne sw se` is a node of level k (2^k cells wide) whose children are
+// earlier nodes, 0 meaning empty. The last node is the root.
+func ReadMacrocell(r io.Reader) (*Macrocell, error) {
+	store := newQuadStore()
+	mc := &Macrocell{}
+	nodes := []*quadNode{nil} // node 0 is the empty node of whatever level is needed
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "[M2]"):
+		case strings.HasPrefix(line, "#R"):
+			mc.Rule = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "#G"):
+			mc.Generation, _ = strconv.Atoi(strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "#"):
+			mc.Comments = append(mc.Comments, strings.TrimSpace(line[min(2, len(line)):]))
+		case line[0] == '.' || line[0] == '*' || line[0] == '$':
+			bitmap, err := parseMacrocellLeaf(line)
+			if err != nil {
+				return nil, fmt.Errorf("macrocell line %d: %w", lineNumber, err)
+			}
+			nodes = append(nodes, store.leafNode(bitmap))
+		default:
+			n, err := parseMacrocellNode(store, nodes, line)
+			if err != nil {
+				return nil, fmt.Errorf("macrocell line %d: %w", lineNumber, err)
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 1 {
+		mc.Tree = &QuadTree{store: store, root: store.emptyNode(leafLevel)}
+	} else {
+		mc.Tree = &QuadTree{store: store, root: nodes[len(nodes)-1]}
+	}
+	return mc, nil
+}
+
+func parseMacrocellLeaf(line string) (uint64, error) {
+	bitmap := uint64(0)
+	row, col := 0, 0
+	for _, ch := range line {
+		switch ch {
+		case '$':
+			row, col = row+1, 0
+			continue
+		case '*':
+			if row >= 8 || col >= 8 {
+				return 0, fmt.Errorf("leaf exceeds 8x8")
+			}
+			bitmap |= 1 << (row*8 + col)
+		case '.':
+		default:
+			return 0, fmt.Errorf("unexpected character %q in leaf", ch)
+		}
+		col++
+	}
+	return bitmap, nil
+}
+
+func parseMacrocellNode(store *quadStore, nodes []*quadNode, line string) (*quadNode, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected `level nw ne sw se`, got %q", line)
+	}
+	level, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	if level == 1 {
+		return nil, fmt.Errorf("multi-state macrocell files are not supported")
+	}
+	if level <= leafLevel || level > maxQuadTreeLevel {
+		return nil, fmt.Errorf("invalid node level %d", level)
+	}
+
+	var children [4]*quadNode
+	for i, field := range fields[1:] {
+		index, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || index >= len(nodes) {
+			return nil, fmt.Errorf("node refers to undefined node %d", index)
+		}
+		if index == 0 {
+			children[i] = store.emptyNode(uint8(level - 1))
+			continue
+		}
+		if int(nodes[index].level) != level-1 {
+			return nil, fmt.Errorf("node %d has level %d, want %d", index, nodes[index].level, level-1)
+		}
+		children[i] = nodes[index]
+	}
+	return store.node(children[0], children[1], children[2], children[3]), nil
+}
+
+// WriteMacrocell writes the pattern in macrocell format, sharing every repeated sub-tree.
+func WriteMacrocell(w io.Writer, mc *Macrocell) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[M2] (game-of-life)\n")
+	rule := mc.Rule
+	if rule == "" {
+		rule = "B3/S23"
+	}
+	fmt.Fprintf(bw, "#R %s\n", rule)
+	if mc.Generation != 0 {
+		fmt.Fprintf(bw, "#G %d\n", mc.Generation)
+	}
+	for _, comment := range mc.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+
+	indices := make(map[*quadNode]int)
+	var write func(n *quadNode) int
+	write = func(n *quadNode) int {
+		if n.population == 0 {
+			return 0
+		}
+		if index, ok := indices[n]; ok {
+			return index
+		}
+		if n.level == leafLevel {
+			bw.WriteString(formatMacrocellLeaf(n.leaf))
+		} else {
+			nw, ne, sw, se := write(n.nw), write(n.ne), write(n.sw), write(n.se)
+			fmt.Fprintf(bw, "%d %d %d %d %d", n.level, nw, ne, sw, se)
+		}
+		bw.WriteByte('\n')
+		indices[n] = len(indices) + 1
+		return indices[n]
+	}
+
+	if mc.Tree.root.population == 0 {
+		// an empty pattern still needs a root
+		bw.WriteString("$\n")
+	} else {
+		write(mc.Tree.root)
+	}
+	return bw.Flush()
+}
+
+func formatMacrocellLeaf(bitmap uint64) string {
+	var sb strings.Builder
+	lastRow := 0
+	for r := range 8 {
+		row := byte(bitmap >> (r * 8))
+		if row == 0 {
+			continue
+		}
+		sb.WriteString(strings.Repeat("$", r-lastRow))
+		lastRow = r
+		for c := 0; row>>c != 0; c++ {
+			if row&(1<<c) != 0 {
+				sb.WriteByte('*')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+	}
+	sb.WriteByte('$')
+	return sb.String()
+}
+
+// readMacrocellPattern reads a macrocell file into a pattern, as long as it is small
+// enough to expand.
+func readMacrocellPattern(r io.Reader) (*Pattern, error) {
+	mc, err := ReadMacrocell(r)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := mc.Tree.Cells(maxExpandedCells)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{Rule: mc.Rule, Comments: mc.Comments, Cells: cells}, nil
+}
+
+// LoadMacrocell reads the macrocell file at path as a QuadTree, without expanding it,
+// so it works for patterns of any size. Files in other formats are an error.
+func LoadMacrocell(path string) (*Macrocell, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(4096)
+	if format := DetectPatternFormat(path, head); format != FormatMacrocell {
+		return nil, fmt.Errorf("%s: not a macrocell file", path)
+	}
+	mc, err := ReadMacrocell(br)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return mc, nil
+}
+
+// writeMacrocellPattern writes a pattern in macrocell format, keeping its coordinates.
+func writeMacrocellPattern(w io.Writer, p *Pattern) error {
+	comments := p.Comments
+	if p.Name != "" {
+		comments = append([]string{p.Name}, comments...)
+	}
+	tree, err := NewQuadTreeFromCells(p.Cells)
+	if err != nil {
+		return err
+	}
+	return WriteMacrocell(w, &Macrocell{Rule: p.Rule, Comments: comments, Tree: tree})
+}
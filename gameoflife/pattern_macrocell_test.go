@@ -0,0 +1,120 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestReadMacrocell_Glider(t *testing.T) {
+	input := "[M2] (golly 4.2)\n#R B3/S23\n.*$..*$***$\n4 0 0 0 1\n"
+
+	mc, err := ReadMacrocell(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadMacrocell() error = %v", err)
+	}
+	if mc.Rule != "B3/S23" {
+		t.Errorf("Rule = %q; want B3/S23", mc.Rule)
+	}
+	cells, err := mc.Tree.Cells(100)
+	if err != nil {
+		t.Fatalf("Cells() error = %v", err)
+	}
+	want := map[Cell]struct{}{
+		{0, 1}: {}, {1, 2}: {}, {2, 0}: {}, {2, 1}: {}, {2, 2}: {},
+	}
+	if !maps.Equal(cells, want) {
+		t.Errorf("Cells() = %v; want %v", cells, want)
+	}
+}
+
+func TestMacrocell_PatternRoundTrip(t *testing.T) {
+	cells := map[Cell]struct{}{
+		{-20, -3}: {}, {-20, -2}: {}, {0, 0}: {}, {7, 8}: {}, {33, -40}: {},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePattern(&buf, NewPattern(cells), FormatMacrocell); err != nil {
+		t.Fatalf("WritePattern() error = %v", err)
+	}
+	p, err := ReadPattern(&buf, FormatUnknown)
+	if err != nil {
+		t.Fatalf("ReadPattern() error = %v", err)
+	}
+	if !maps.Equal(p.Cells, cells) {
+		t.Errorf("round trip = %v; want %v", p.Cells, cells)
+	}
+}
+
+func TestMacrocell_HugePatternStaysCompact(t *testing.T) {
+	// a 2^40 x 2^40 square tiled with blocks: about 2.7e23 cells before saturation
+	store := newQuadStore()
+	block := store.leafNode(0b11 | 0b11<<8)
+	n := block
+	for n.level < 40 {
+		n = store.node(n, n, n, n)
+	}
+	tree := &QuadTree{store: store, root: n}
+
+	var buf bytes.Buffer
+	if err := WriteMacrocell(&buf, &Macrocell{Tree: tree}); err != nil {
+		t.Fatalf("WriteMacrocell() error = %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines > 45 {
+		t.Errorf("macrocell file has %d lines; want one per level", lines)
+	}
+
+	mc, err := ReadMacrocell(&buf)
+	if err != nil {
+		t.Fatalf("ReadMacrocell() error = %v", err)
+	}
+	if mc.Tree.Level() != 40 || mc.Tree.Population() != n.population || mc.Tree.NodeCount() != 38 {
+		t.Errorf("read level %d, population %d, %d nodes; want 40, %d, 38",
+			mc.Tree.Level(), mc.Tree.Population(), mc.Tree.NodeCount(), n.population)
+	}
+	if _, err := mc.Tree.Cells(maxExpandedCells); err == nil {
+		t.Errorf("Cells() expanded a pattern beyond the limit")
+	}
+	// blocks fill the top-left 2x2 of every leaf, so the last row and column are empty
+	if minCell, height, width := mc.Tree.Bounds(); minCell != (Cell{-1 << 39, -1 << 39}) || height != 1<<40-6 || width != 1<<40-6 {
+		t.Errorf("Bounds() = %v, %d, %d; want the whole tree but its last 6 rows and columns", minCell, height, width)
+	}
+}
+
+func TestNewQuadTreeFromCells(t *testing.T) {
+	cells := map[Cell]struct{}{{-20, -3}: {}, {-20, -2}: {}, {7, 8}: {}, {33, -40}: {}}
+	tree, err := NewQuadTreeFromCells(cells)
+	if err != nil {
+		t.Fatalf("NewQuadTreeFromCells() error = %v", err)
+	}
+	wantMin, wantHeight, wantWidth := boundingBox(cells)
+	if minCell, height, width := tree.Bounds(); minCell != wantMin || height != wantHeight || width != wantWidth {
+		t.Errorf("Bounds() = %v, %d, %d; want %v, %d, %d", minCell, height, width, wantMin, wantHeight, wantWidth)
+	}
+
+	// cells too far out for any tree are an error rather than an endless search for a level
+	if _, err := NewQuadTreeFromCells(map[Cell]struct{}{{0, 1 << 62}: {}}); err == nil {
+		t.Errorf("NewQuadTreeFromCells() of a cell 2^62 from the origin succeeded; want an error")
+	}
+}
+
+func TestReadMacrocell_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"undefined-child", "[M2]\n4 0 0 0 7\n"},
+		{"level-mismatch", "[M2]\n*$\n5 1 0 0 0\n"},
+		{"multi-state", "[M2]\n1 0 1 2 0\n"},
+		{"bad-leaf", "[M2]\n.x$\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadMacrocell(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("ReadMacrocell() succeeded; want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readPlaintext parses the `.cells` format:
+//
+//	!Name: Glider
+//	!A comment
+//	.O.
+//	..O
+//	OOO
+//
+// `.` is a dead cell and `O` a live cell; `*` is accepted as a live cell too.
+func readPlaintext(r *bufio.Reader) (*Pattern, error) {
+	p := &Pattern{Cells: make(map[Cell]struct{})}
+	row := 0
+	lineNumber := 0
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		lineNumber++
+		line = strings.TrimRight(line, "\r\n")
+
+		if strings.HasPrefix(line, "!") {
+			text := strings.TrimSpace(line[1:])
+			if name, ok := strings.CutPrefix(text, "Name:"); ok {
+				p.Name = strings.TrimSpace(name)
+			} else {
+				p.Comments = append(p.Comments, text)
+			}
+		} else if line != "" || err == nil {
+			for col, ch := range line {
+				switch ch {
+				case '.', ' ':
+				case 'O', 'o', '*':
+					p.Cells[Cell{row, col}] = struct{}{}
+				default:
+					return nil, fmt.Errorf("cells line %d: unexpected character %q", lineNumber, ch)
+				}
+			}
+			row++
+		}
+
+		if err == io.EOF {
+			return p, nil
+		}
+	}
+}
+
+// writePlaintext writes the pattern normalised to its bounding box.
+func writePlaintext(w *bufio.Writer, p *Pattern) error {
+	minCell, height, width := p.Bounds()
+
+	if p.Name != "" {
+		fmt.Fprintf(w, "!Name: %s\n", p.Name)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(w, "!%s\n", comment)
+	}
+
+	for r := range height {
+		line := make([]byte, width)
+		for c := range width {
+			line[c] = '.'
+			if p.hasCell(Cell{minCell.R + r, minCell.C + c}) {
+				line[c] = 'O'
+			}
+		}
+		trimmed := strings.TrimRight(string(line), ".")
+		if trimmed == "" {
+			// empty rows keep a single `.` so they are not mistaken for blank lines
+			trimmed = "."
+		}
+		w.WriteString(trimmed)
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// rleLineLength is the maximum length of an RLE body line written by writeRLE.
+const rleLineLength = 70
+
+// maxRLERun is the longest run count readRLE accepts, which keeps coordinates far from
+// overflowing.
+const maxRLERun = 1 << 31
+
+// maxRLEState is the highest state multistate RLE writes, as the letter `X`.
+const maxRLEState = 24
+
+// readRLE parses the run length encoded format:
+//
+//	#N Glider
+//	x = 3, y = 3, rule = B3/S23
+//	bo$2bo$3o!
+//
+// `b` is a dead cell, `o` (or any other letter) a live cell, `$` ends a row and
+// `!` ends the pattern. Each token may be preceded by a run count. Multistate patterns
+// write dead cells as `.` and live cells as `A` for state 1, `B` for state 2 and so on
+// up to `X` for state 24; their states are kept in Pattern.States.
+func readRLE(r *bufio.Reader) (*Pattern, error) {
+	p := &Pattern{Cells: make(map[Cell]struct{})}
+	states, multistate := make(map[Cell]int), false
+	finish := func() *Pattern {
+		if multistate {
+			p.States = states
+		}
+		return p
+	}
+	headerSeen := false
+	row, col, count := 0, 0, 0
+	lineNumber := 0
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		lineNumber++
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "#"):
+			readRLEComment(p, trimmed)
+		case !headerSeen && strings.HasPrefix(trimmed, "x"):
+			headerSeen = true
+			for i, field := range strings.Split(trimmed, ",") {
+				key, _, _ := strings.Cut(field, "=")
+				if strings.TrimSpace(key) == "rule" {
+					// the rule is the rest of the line: rules such as MS,D0;8;... contain commas
+					fields := strings.SplitN(trimmed, ",", i+1)
+					_, value, _ := strings.Cut(fields[i], "=")
+					p.Rule = strings.TrimSpace(value)
+					break
+				}
+			}
+		default:
+			for _, ch := range trimmed {
+				switch {
+				case ch >= '0' && ch <= '9':
+					if count = count*10 + int(ch-'0'); count > maxRLERun {
+						return nil, fmt.Errorf("rle line %d: run count longer than %d", lineNumber, maxRLERun)
+					}
+					continue
+				case ch == ' ' || ch == '\t':
+					continue
+				case ch == '!':
+					return finish(), nil
+				}
+
+				run := max(count, 1)
+				count = 0
+				switch {
+				case ch == '$':
+					row += run
+					col = 0
+				case ch == 'b' || ch == '.':
+					col += run
+				case ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z':
+					state := 1
+					if ch >= 'A' && ch <= 'X' {
+						state, multistate = int(ch-'A')+1, true
+					}
+					if len(p.Cells)+run > maxExpandedCells {
+						return nil, fmt.Errorf("rle line %d: more than %d live cells", lineNumber, maxExpandedCells)
+					}
+					for range run {
+						p.Cells[Cell{row, col}] = struct{}{}
+						states[Cell{row, col}] = state
+						col++
+					}
+				default:
+					return nil, fmt.Errorf("rle line %d: unexpected character %q", lineNumber, ch)
+				}
+			}
+		}
+
+		if err == io.EOF {
+			// tolerate a missing `!` terminator
+			return finish(), nil
+		}
+	}
+}
+
+// readRLEComment handles `#N name`, `#C comment`, `#c comment` and `#r rule` lines.
+func readRLEComment(p *Pattern, line string) {
+	if len(line) < 2 {
+		return
+	}
+	text := strings.TrimSpace(line[2:])
+	switch line[1] {
+	case 'N':
+		p.Name = text
+	case 'C', 'c':
+		p.Comments = append(p.Comments, text)
+	case 'r':
+		p.Rule = text
+	}
+}
+
+// writeRLE writes the pattern normalised to its bounding box, in multistate notation if
+// it has States. Multistate notation has letters for states 1 to maxRLEState only.
+func writeRLE(w *bufio.Writer, p *Pattern) error {
+	for cell, state := range p.States {
+		if p.hasCell(cell) && (state < 1 || state > maxRLEState) {
+			return fmt.Errorf("rle: cell %v has state %d, want 1 to %d", cell, state, maxRLEState)
+		}
+	}
+	minCell, height, width := p.Bounds()
+
+	if p.Name != "" {
+		fmt.Fprintf(w, "#N %s\n", p.Name)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(w, "#C %s\n", comment)
+	}
+	rule := p.Rule
+	if rule == "" {
+		rule = "B3/S23"
+	}
+	fmt.Fprintf(w, "x = %d, y = %d, rule = %s\n", width, height, rule)
+
+	line := &rleLineWriter{w: w}
+	lastRow := 0
+	for r := range height {
+		c := 0
+		for c < width {
+			state := p.state(Cell{minCell.R + r, minCell.C + c})
+			run := 1
+			for c+run < width && p.state(Cell{minCell.R + r, minCell.C + c + run}) == state {
+				run++
+			}
+			if state == 0 && c+run == width {
+				// trailing dead cells of a row are implied by `$`
+				break
+			}
+			if r > lastRow {
+				line.token(r-lastRow, '$')
+				lastRow = r
+			}
+			line.token(run, p.rleTag(state))
+			c += run
+		}
+	}
+	line.token(1, '!')
+	return line.end()
+}
+
+// state returns the state of a cell: 0 if dead, otherwise its state or 1.
+func (p *Pattern) state(cell Cell) int {
+	if !p.hasCell(cell) {
+		return 0
+	}
+	if state, ok := p.States[cell]; ok {
+		return state
+	}
+	return 1
+}
+
+// rleTag returns the RLE token of a state: `b` and `o` for two-state patterns, `.` and
+// `A` to `X` for multistate ones.
+func (p *Pattern) rleTag(state int) byte {
+	switch {
+	case p.States == nil && state == 0:
+		return 'b'
+	case p.States == nil:
+		return 'o'
+	case state == 0:
+		return '.'
+	default:
+		return byte('A' + state - 1)
+	}
+}
+
+// rleLineWriter wraps RLE tokens so no body line exceeds rleLineLength.
+type rleLineWriter struct {
+	w      *bufio.Writer
+	length int
+}
+
+func (l *rleLineWriter) token(run int, ch byte) {
+	token := string(ch)
+	if run > 1 {
+		token = strconv.Itoa(run) + token
+	}
+	if l.length+len(token) > rleLineLength {
+		l.w.WriteByte('\n')
+		l.length = 0
+	}
+	l.w.WriteString(token)
+	l.length += len(token)
+}
+
+func (l *rleLineWriter) end() error {
+	_, err := l.w.WriteString("\n")
+	return err
+}
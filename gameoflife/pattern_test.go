@@ -0,0 +1,169 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+)
+
+var gliderCells = map[Cell]struct{}{
+	{0, 1}: {}, {1, 2}: {}, {2, 0}: {}, {2, 1}: {}, {2, 2}: {},
+}
+
+func TestReadPattern_Formats(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format PatternFormat
+		want   map[Cell]struct{}
+	}{
+		{
+			name:   "rle",
+			input:  "#N Glider\n#C A comment\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n",
+			format: FormatRLE,
+			want:   gliderCells,
+		},
+		{
+			name:   "rle-multiline-runs",
+			input:  "x = 4, y = 3\n4o$\n3$o\n2bo!",
+			format: FormatRLE,
+			want: map[Cell]struct{}{
+				{0, 0}: {}, {0, 1}: {}, {0, 2}: {}, {0, 3}: {}, {4, 0}: {}, {4, 3}: {},
+			},
+		},
+		{
+			name:   "cells",
+			input:  "!Name: Glider\n!\n.O.\n..O\nOOO\n",
+			format: FormatPlaintext,
+			want:   gliderCells,
+		},
+		{
+			name:   "life105",
+			input:  "#Life 1.05\n#D Glider\n#N\n#P -1 -1\n.*.\n..*\n***\n",
+			format: FormatLife105,
+			want: map[Cell]struct{}{
+				{-1, 0}: {}, {0, 1}: {}, {1, -1}: {}, {1, 0}: {}, {1, 1}: {},
+			},
+		},
+		{
+			name:   "life106",
+			input:  "#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n",
+			format: FormatLife106,
+			want: map[Cell]struct{}{
+				{-1, 0}: {}, {0, 1}: {}, {1, -1}: {}, {1, 0}: {}, {1, 1}: {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectPatternFormat("", []byte(tt.input)); got != tt.format {
+				t.Errorf("DetectPatternFormat() = %v; want %v", got, tt.format)
+			}
+			p, err := ReadPattern(strings.NewReader(tt.input), FormatUnknown)
+			if err != nil {
+				t.Fatalf("ReadPattern() error = %v", err)
+			}
+			if !maps.Equal(p.Cells, tt.want) {
+				t.Errorf("ReadPattern() cells = %v; want %v", p.Cells, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePattern_RoundTrip(t *testing.T) {
+	// a pattern with an empty row, an empty column and negative coordinates
+	cells := map[Cell]struct{}{
+		{-3, -2}: {}, {-3, 1}: {}, {-1, -1}: {}, {0, 1}: {}, {0, 0}: {},
+	}
+
+	for _, format := range []PatternFormat{FormatRLE, FormatPlaintext, FormatLife105, FormatLife106} {
+		t.Run(format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WritePattern(&buf, &Pattern{Name: "test", Cells: cells}, format); err != nil {
+				t.Fatalf("WritePattern() error = %v", err)
+			}
+			p, err := ReadPattern(&buf, format)
+			if err != nil {
+				t.Fatalf("ReadPattern() error = %v", err)
+			}
+
+			// all formats but Life 1.05/1.06 lose the absolute position
+			want, _ := NewPattern(cells).Place(10, 10)
+			got, _ := p.Place(10, 10)
+			if !maps.Equal(got, want) {
+				t.Errorf("round trip = %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDetectPatternFormat_Extension(t *testing.T) {
+	tests := []struct {
+		name string
+		want PatternFormat
+	}{
+		{"glider.rle", FormatRLE},
+		{"glider.cells", FormatPlaintext},
+		{"glider.lif", FormatLife106},
+		{"glider.txt", FormatUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectPatternFormat(tt.name, nil); got != tt.want {
+				t.Errorf("DetectPatternFormat(%q) = %v; want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPattern_Place(t *testing.T) {
+	p := NewPattern(map[Cell]struct{}{{-5, -5}: {}, {-4, -3}: {}})
+
+	got, err := p.Place(4, 5)
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	want := map[Cell]struct{}{{1, 1}: {}, {2, 3}: {}}
+	if !maps.Equal(got, want) {
+		t.Errorf("Place() = %v; want %v", got, want)
+	}
+
+	if _, err := p.Place(1, 5); err == nil {
+		t.Errorf("Place() into a too small universe succeeded")
+	}
+}
+
+func TestReadPattern_RejectsHugeExtent(t *testing.T) {
+	// the height of this pattern does not fit in an int, so Place could not check it
+	huge := "#Life 1.06\n0 -9223372036854775808\n0 9223372036854775807\n"
+	if p, err := ReadPattern(strings.NewReader(huge), FormatLife106); err == nil {
+		t.Errorf("ReadPattern() = %v; want an error for an extent beyond the int range", p.Cells)
+	}
+	wide := "#Life 1.06\n0 -2305843009213693952\n0 2305843009213693952\n"
+	if _, err := ReadPattern(strings.NewReader(wide), FormatLife106); err != nil {
+		t.Errorf("ReadPattern() of a pattern 2^62 tall error = %v", err)
+	}
+}
+
+func TestWritePattern_RLEStates(t *testing.T) {
+	cells := map[Cell]struct{}{{0, 0}: {}, {0, 1}: {}}
+	var buf bytes.Buffer
+	if err := WritePattern(&buf, &Pattern{Cells: cells, States: map[Cell]int{{0, 0}: 1, {0, 1}: 24}}, FormatRLE); err != nil {
+		t.Fatalf("WritePattern() error = %v", err)
+	}
+	p, err := ReadPattern(&buf, FormatRLE)
+	if err != nil {
+		t.Fatalf("ReadPattern() error = %v", err)
+	}
+	if want := map[Cell]int{{0, 0}: 1, {0, 1}: 24}; !maps.Equal(p.States, want) {
+		t.Errorf("round trip states = %v; want %v", p.States, want)
+	}
+
+	// RLE has no letter for state 25
+	if err := WritePattern(&buf, &Pattern{Cells: cells, States: map[Cell]int{{0, 1}: 25}}, FormatRLE); err == nil {
+		t.Errorf("WritePattern() of state 25 succeeded; want an error")
+	}
+}
@@ -0,0 +1,257 @@
+package gameoflife
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// PeriodicSearchOptions configure SearchPeriodic.
+type PeriodicSearchOptions struct {
+	// Rules default to Conway's rule. Only totalistic rules can be searched.
+	Rules []Rule
+	// Rows and Cols are the size of the box every phase of the pattern must fit in.
+	Rows, Cols int
+	// Period is the number of generations after which the pattern repeats.
+	Period int
+	// Velocity is how far the pattern moves in one period: zero for oscillators,
+	// e.g. {1, 1} with period 4 for gliders.
+	Velocity Cell
+	// Symmetry restricts the search to patterns with one of the symmetries of
+	// SearchSymmetries; "" or "C1" means none.
+	Symmetry string
+	// MaxResults stops the search after this many distinct patterns; zero finds them all.
+	MaxResults int
+	// TimeLimit bounds the search; zero means no limit.
+	TimeLimit time.Duration
+}
+
+// PeriodicResult is one pattern found by SearchPeriodic.
+type PeriodicResult struct {
+	Apgcode   string
+	Behaviour Behaviour
+	// Cells are the first phase of the pattern, with the search box at the origin.
+	Cells map[Cell]struct{}
+}
+
+// searchSymmetries maps the symmetry names used by apgsearch and Logic Life Search to
+// the reflections and rotations of the box that generate them. Names marked square need
+// a square box.
+var searchSymmetries = map[string]struct {
+	square     bool
+	generators []func(c Cell, rows, cols int) Cell
+}{
+	"C1":   {},
+	"C2":   {false, []func(Cell, int, int) Cell{rotate180}},
+	"C4":   {true, []func(Cell, int, int) Cell{rotate90}},
+	"D2-":  {false, []func(Cell, int, int) Cell{mirrorRows}},
+	"D2|":  {false, []func(Cell, int, int) Cell{mirrorCols}},
+	"D2\\": {true, []func(Cell, int, int) Cell{transpose}},
+	"D2/":  {true, []func(Cell, int, int) Cell{antiTranspose}},
+	"D4+":  {false, []func(Cell, int, int) Cell{mirrorRows, mirrorCols}},
+	"D4x":  {true, []func(Cell, int, int) Cell{transpose, antiTranspose}},
+	"D8":   {true, []func(Cell, int, int) Cell{rotate90, mirrorRows}},
+}
+
+func rotate180(c Cell, rows, cols int) Cell     { return Cell{rows - 1 - c.R, cols - 1 - c.C} }
+func rotate90(c Cell, rows, cols int) Cell      { return Cell{c.C, rows - 1 - c.R} }
+func mirrorRows(c Cell, rows, cols int) Cell    { return Cell{rows - 1 - c.R, c.C} }
+func mirrorCols(c Cell, rows, cols int) Cell    { return Cell{c.R, cols - 1 - c.C} }
+func transpose(c Cell, rows, cols int) Cell     { return Cell{c.C, c.R} }
+func antiTranspose(c Cell, rows, cols int) Cell { return Cell{cols - 1 - c.C, rows - 1 - c.R} }
+
+// SearchSymmetries returns the names of the symmetries SearchPeriodic can impose.
+func SearchSymmetries() []string {
+	names := make([]string, 0, len(searchSymmetries))
+	for name := range searchSymmetries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SearchPeriodic looks for still lifes, oscillators and spaceships that fit in a box:
+// patterns that return to themselves, moved by Velocity, after exactly Period generations
+// and no fewer. It encodes all generations of the box as one SAT problem and solves it
+// repeatedly, each time ruling out every phase, rotation, reflection and position of the
+// patterns found so far, so each result is a different object. Results are in the order
+// found. When the time limit is reached, the results so far are returned with
+// ErrSearchTimeout; otherwise the search is exhaustive.
+func SearchPeriodic(opts PeriodicSearchOptions) ([]PeriodicResult, error) {
+	if len(opts.Rules) == 0 {
+		opts.Rules = []Rule{ConwayRule{}}
+	}
+	if opts.Rows <= 0 || opts.Cols <= 0 || opts.Period <= 0 {
+		return nil, fmt.Errorf("rows, cols and period must be positive")
+	}
+	symmetry, ok := searchSymmetries[cmp.Or(opts.Symmetry, "C1")]
+	if !ok {
+		return nil, fmt.Errorf("unknown symmetry %q, want one of %v", opts.Symmetry, SearchSymmetries())
+	}
+	if symmetry.square && opts.Rows != opts.Cols {
+		return nil, fmt.Errorf("symmetry %s needs a square box", opts.Symmetry)
+	}
+	table, err := transitionTable(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newPeriodicSearch(opts, table)
+	for _, generator := range symmetry.generators {
+		for cell, v := range s.phases[0] {
+			image := s.phases[0][generator(cell, opts.Rows, opts.Cols)]
+			s.enc.solver.addClause(-v, image)
+		}
+	}
+
+	deadline := deadlineAfter(opts.TimeLimit)
+	var results []PeriodicResult
+	seen := make(map[string]bool)
+	for opts.MaxResults <= 0 || len(results) < opts.MaxResults {
+		switch s.enc.solver.solve(deadline) {
+		case satUnsatisfiable:
+			return results, nil
+		case satUnknown:
+			return results, ErrSearchTimeout
+		}
+
+		cells := s.enc.cells(s.phases[0])
+		s.exclude(cells)
+		behaviour := classifyAlone(cells, opts.Rules, opts.Period)
+		if behaviour.Period != opts.Period {
+			// cannot happen with a correct encoding, but never report a wrong period
+			continue
+		}
+		code := Apgcode(cells, opts.Rules...)
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		results = append(results, PeriodicResult{Apgcode: code, Behaviour: behaviour, Cells: cells})
+	}
+	return results, nil
+}
+
+// periodicSearch holds the SAT encoding of a periodic search: one variable per cell of
+// the box in each of the Period phases.
+type periodicSearch struct {
+	opts   PeriodicSearchOptions
+	enc    *lifeEncoding
+	phases []map[Cell]int
+}
+
+func newPeriodicSearch(opts PeriodicSearchOptions, table [2][9]bool) *periodicSearch {
+	s := &periodicSearch{opts: opts, enc: newLifeEncoding(table)}
+	for range opts.Period {
+		vars := make(map[Cell]int, opts.Rows*opts.Cols)
+		for r := range opts.Rows {
+			for c := range opts.Cols {
+				vars[Cell{r, c}] = s.enc.solver.newVar()
+			}
+		}
+		s.phases = append(s.phases, vars)
+	}
+
+	// each phase evolves into the next, and the last into the first, moved by the velocity;
+	// cells around the box must stay dead
+	first, velocity := s.phases[0], opts.Velocity
+	for t, vars := range s.phases {
+		for r := -1; r <= opts.Rows; r++ {
+			for c := -1; c <= opts.Cols; c++ {
+				cell := Cell{r, c}
+				var next int
+				if t+1 < opts.Period {
+					next = s.literal(t+1, cell)
+				} else {
+					next = s.literal(0, Cell{r - velocity.R, c - velocity.C})
+				}
+				s.enc.transition(vars[cell], mooreNeighbourVars(vars, cell), next)
+			}
+		}
+	}
+	// cells that would move beyond the reach of the last phase must be dead
+	for cell, v := range first {
+		moved := Cell{cell.R + velocity.R, cell.C + velocity.C}
+		if moved.R < -1 || moved.R > opts.Rows || moved.C < -1 || moved.C > opts.Cols {
+			s.enc.solver.addClause(-v)
+		}
+	}
+
+	// the pattern is not empty
+	live := make([]int, 0, len(first))
+	for _, v := range first {
+		live = append(live, v)
+	}
+	s.enc.solver.addClause(live...)
+
+	// and does not repeat sooner: for every shorter period that divides Period, the phase
+	// after it differs from the first, moved by the matching part of the velocity
+	for d := 1; d < opts.Period; d++ {
+		if opts.Period%d != 0 || velocity.R*d%opts.Period != 0 || velocity.C*d%opts.Period != 0 {
+			continue
+		}
+		shift := Cell{velocity.R * d / opts.Period, velocity.C * d / opts.Period}
+		var differences []int
+		for r := -1; r <= opts.Rows; r++ {
+			for c := -1; c <= opts.Cols; c++ {
+				a := s.phases[d][Cell{r, c}]
+				b := first[Cell{r - shift.R, c - shift.C}]
+				if a != 0 || b != 0 {
+					differences = append(differences, s.differ(a, b))
+				}
+			}
+		}
+		s.enc.solver.addClause(differences...)
+	}
+	return s
+}
+
+// literal returns the literal of a cell in a phase: its variable, or false outside the box.
+func (s *periodicSearch) literal(phase int, cell Cell) int {
+	if v, ok := s.phases[phase][cell]; ok {
+		return v
+	}
+	return s.enc.constant(false)
+}
+
+// differ returns a new variable that can only be true if variables a and b have different
+// values; 0 stands for a dead cell.
+func (s *periodicSearch) differ(a, b int) int {
+	y := s.enc.solver.newVar()
+	switch {
+	case a == 0:
+		s.enc.solver.addClause(-y, b)
+	case b == 0:
+		s.enc.solver.addClause(-y, a)
+	default:
+		s.enc.solver.addClause(-y, a, b)
+		s.enc.solver.addClause(-y, -a, -b)
+	}
+	return y
+}
+
+// exclude rules out every phase, rotation, reflection and position of a pattern as the
+// first phase of further solutions.
+func (s *periodicSearch) exclude(cells map[Cell]struct{}) {
+	first := s.phases[0]
+	for _, phase := range evolveAlone(cells, s.opts.Rules, s.opts.Period-1) {
+		for _, symmetry := range symmetries {
+			shape := normalisedCells(transformCells(phase, symmetry))
+			_, height, width := boundingBox(transformCells(phase, symmetry))
+			for top := 0; top+height <= s.opts.Rows; top++ {
+				for left := 0; left+width <= s.opts.Cols; left++ {
+					clause := make([]int, 0, len(first))
+					for cell, v := range first {
+						if _, ok := slices.BinarySearchFunc(shape, Cell{cell.R - top, cell.C - left}, compareCells); ok {
+							clause = append(clause, -v)
+						} else {
+							clause = append(clause, v)
+						}
+					}
+					s.enc.solver.addClause(clause...)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gameoflife
+
+import (
+	"slices"
+	"testing"
+)
+
+func periodicCodes(t *testing.T, opts PeriodicSearchOptions) []string {
+	t.Helper()
+	results, err := SearchPeriodic(opts)
+	if err != nil {
+		t.Fatalf("SearchPeriodic(%+v) error = %v", opts, err)
+	}
+	var codes []string
+	for _, result := range results {
+		if result.Behaviour.Period != opts.Period {
+			t.Errorf("%s has period %d; want %d", result.Apgcode, result.Behaviour.Period, opts.Period)
+		}
+		if slices.Contains(codes, result.Apgcode) {
+			t.Errorf("%s found twice", result.Apgcode)
+		}
+		codes = append(codes, result.Apgcode)
+	}
+	return codes
+}
+
+func TestSearchPeriodic_Oscillators(t *testing.T) {
+	codes := periodicCodes(t, PeriodicSearchOptions{Rows: 5, Cols: 5, Period: 2})
+	for _, want := range []string{"xp2_7", "xp2_7e", "xp2_318c"} { // blinker, toad, beacon
+		if !slices.Contains(codes, want) {
+			t.Errorf("period 2 oscillators in a 5x5 box = %v; want %s among them", codes, want)
+		}
+	}
+}
+
+func TestSearchPeriodic_StillLifes(t *testing.T) {
+	codes := periodicCodes(t, PeriodicSearchOptions{Rows: 3, Cols: 4, Period: 1})
+	for _, want := range []string{"xs4_33", "xs6_696", "xs5_253"} { // block, beehive, boat
+		if !slices.Contains(codes, want) {
+			t.Errorf("still lifes in a 3x4 box = %v; want %s among them", codes, want)
+		}
+	}
+}
+
+func TestSearchPeriodic_Spaceship(t *testing.T) {
+	codes := periodicCodes(t, PeriodicSearchOptions{Rows: 5, Cols: 5, Period: 4, Velocity: Cell{1, 1}})
+	if !slices.Equal(codes, []string{"xq4_153"}) {
+		t.Errorf("c/4 diagonal spaceships in a 5x5 box = %v; want just the glider", codes)
+	}
+}
+
+func TestSearchPeriodic_Symmetry(t *testing.T) {
+	// the blinker is the only period 2 oscillator in a 5x5 box symmetric under both mirrors
+	codes := periodicCodes(t, PeriodicSearchOptions{Rows: 5, Cols: 5, Period: 2, Symmetry: "D4+"})
+	if !slices.Equal(codes, []string{"xp2_7"}) {
+		t.Errorf("D4+ period 2 oscillators in a 5x5 box = %v; want [xp2_7]", codes)
+	}
+
+	if _, err := SearchPeriodic(PeriodicSearchOptions{Rows: 4, Cols: 5, Period: 2, Symmetry: "D8"}); err == nil {
+		t.Errorf("SearchPeriodic() with D8 symmetry in a 4x5 box succeeded; want an error")
+	}
+}
+
+func TestParseLifeLikeRule(t *testing.T) {
+	rule, err := ParseLifeLikeRule("b36/s23")
+	if err != nil {
+		t.Fatalf("ParseLifeLikeRule() error = %v", err)
+	}
+	if got := rule.String(); got != "B36/S23" {
+		t.Errorf("String() = %q; want B36/S23", got)
+	}
+	if !rule.Next(false, 6) || rule.Next(false, 2) || !rule.Next(true, 2) {
+		t.Errorf("B36/S23 gives wrong transitions")
+	}
+	if parsed := ParseRulesFromString("S23/B36"); len(parsed) != 1 || parsed[0] != Rule(rule) {
+		t.Errorf("ParseRulesFromString(S23/B36) = %v; want [%v]", parsed, rule)
+	}
+	for _, invalid := range []string{"B3", "B39/S23", "B3/B3", "B03/S23"} {
+		if _, err := ParseLifeLikeRule(invalid); err == nil {
+			t.Errorf("ParseLifeLikeRule(%q) succeeded; want an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package gameoflife
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoPredecessor means the search proved that no predecessor exists within the
+	// searched area: there the target is a Garden of Eden.
+	ErrNoPredecessor = errors.New("no predecessor exists")
+	// ErrSearchTimeout means the time limit ran out before the search reached an answer.
+	ErrSearchTimeout = errors.New("search time limit reached")
+)
+
+// PredecessorOptions configure FindPredecessor.
+type PredecessorOptions struct {
+	// Rules default to Conway's rule. Only totalistic rules can be searched.
+	Rules []Rule
+	// Margin is how far beyond the bounding box of the target the live cells of the
+	// predecessor may lie; every cell further out is dead.
+	Margin int
+	// TimeLimit bounds the search; zero means no limit.
+	TimeLimit time.Duration
+}
+
+// FindPredecessor finds a pattern on the infinite plane that evolves into the target in
+// one generation. The search is exhaustive within the bounding box of the target grown
+// by Margin, so when it fails with ErrNoPredecessor no predecessor fits in that box.
+// The predecessor is returned in the coordinates of the target.
+func FindPredecessor(target map[Cell]struct{}, opts PredecessorOptions) (map[Cell]struct{}, error) {
+	if len(opts.Rules) == 0 {
+		opts.Rules = []Rule{ConwayRule{}}
+	}
+	table, err := transitionTable(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if len(target) == 0 {
+		return map[Cell]struct{}{}, nil
+	}
+
+	minCell, height, width := boundingBox(target)
+	top, left := minCell.R-opts.Margin, minCell.C-opts.Margin
+	rows, cols := height+2*opts.Margin, width+2*opts.Margin
+
+	enc := newLifeEncoding(table)
+	vars := make(map[Cell]int, rows*cols)
+	for r := top; r < top+rows; r++ {
+		for c := left; c < left+cols; c++ {
+			vars[Cell{r, c}] = enc.solver.newVar()
+		}
+	}
+	// cells next to the box can be born from cells inside it, so they are constrained
+	// too; beyond that nothing can change
+	for r := top - 1; r <= top+rows; r++ {
+		for c := left - 1; c <= left+cols; c++ {
+			cell := Cell{r, c}
+			_, alive := target[cell]
+			enc.transition(vars[cell], mooreNeighbourVars(vars, cell), enc.constant(alive))
+		}
+	}
+
+	predecessor, err := solvePredecessor(enc, vars, opts.TimeLimit)
+	if errors.Is(err, ErrNoPredecessor) {
+		return nil, fmt.Errorf("%w within the %dx%d box around the target", err, rows, cols)
+	}
+	return predecessor, err
+}
+
+// FindPredecessor finds a universe of the same size whose next generation is this one,
+// taking the universe's topology into account. The search is exhaustive, so
+// ErrNoPredecessor means the current universe is a Garden of Eden on this grid.
+// The rules of the universe must be totalistic.
+func (g *GameOfLife) FindPredecessor(timeLimit time.Duration) (*GameOfLife, error) {
+	table, err := transitionTable(g.rules)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := newLifeEncoding(table)
+	vars := make(map[Cell]int, g.numRows*g.numCols)
+	for r := range g.numRows {
+		for c := range g.numCols {
+			vars[Cell{r, c}] = enc.solver.newVar()
+		}
+	}
+	for cell, v := range vars {
+		neighbours := make([]int, 0, len(g.neighbouringCells))
+		for _, offset := range g.neighbouringCells {
+			// neighbours beyond an edge that is not joined are dead, variable 0
+			if neighbour, ok := g.neighbour(cell, offset); ok {
+				neighbours = append(neighbours, vars[neighbour])
+			}
+		}
+		_, alive := g.universe[cell]
+		enc.transition(v, neighbours, enc.constant(alive))
+	}
+
+	cells, err := solvePredecessor(enc, vars, timeLimit)
+	if err != nil {
+		if errors.Is(err, ErrNoPredecessor) {
+			return nil, fmt.Errorf("%w on the %dx%d %s", err, g.numRows, g.numCols, g.topology)
+		}
+		return nil, err
+	}
+	predecessor := CreateSeedUniverse(g.numRows, g.numCols, Default, g.rules...)
+	predecessor.universe = cells
+	predecessor.topology = g.topology
+	return predecessor, nil
+}
+
+// solvePredecessor runs the solver and returns the live cells of the predecessor it found.
+func solvePredecessor(enc *lifeEncoding, vars map[Cell]int, timeLimit time.Duration) (map[Cell]struct{}, error) {
+	switch enc.solver.solve(deadlineAfter(timeLimit)) {
+	case satUnsatisfiable:
+		return nil, ErrNoPredecessor
+	case satUnknown:
+		return nil, ErrSearchTimeout
+	}
+	return enc.cells(vars), nil
+}
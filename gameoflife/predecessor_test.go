@@ -0,0 +1,95 @@
+package gameoflife
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestFindPredecessor_Glider(t *testing.T) {
+	target := maps.Clone(gliderCells)
+	predecessor, err := FindPredecessor(target, PredecessorOptions{Margin: 1})
+	if err != nil {
+		t.Fatalf("FindPredecessor() error = %v", err)
+	}
+	if next := evolveAlone(predecessor, []Rule{ConwayRule{}}, 1)[1]; !maps.Equal(next, target) {
+		t.Errorf("predecessor %v evolves into %v; want %v", predecessor, next, target)
+	}
+}
+
+func TestFindPredecessor_SingleCell(t *testing.T) {
+	target := map[Cell]struct{}{{5, 5}: {}}
+
+	// a live cell needs three live neighbours the generation before
+	_, err := FindPredecessor(target, PredecessorOptions{Margin: 0})
+	if !errors.Is(err, ErrNoPredecessor) {
+		t.Errorf("FindPredecessor() with margin 0 error = %v; want ErrNoPredecessor", err)
+	}
+
+	predecessor, err := FindPredecessor(target, PredecessorOptions{Margin: 1})
+	if err != nil {
+		t.Fatalf("FindPredecessor() with margin 1 error = %v", err)
+	}
+	if next := evolveAlone(predecessor, []Rule{ConwayRule{}}, 1)[1]; !maps.Equal(next, target) {
+		t.Errorf("predecessor %v evolves into %v; want %v", predecessor, next, target)
+	}
+}
+
+func TestFindPredecessor_NonTotalisticRule(t *testing.T) {
+	_, err := FindPredecessor(gliderCells, PredecessorOptions{Rules: []Rule{NoTopLeftNeighborRule{}}})
+	if err == nil {
+		t.Errorf("FindPredecessor() with a non-totalistic rule succeeded; want an error")
+	}
+}
+
+// TestGameOfLife_FindPredecessor compares the solver against brute force on a small grid
+// of every topology: every universe reachable in one step must get a verified predecessor,
+// every other universe must be reported as a Garden of Eden.
+func TestGameOfLife_FindPredecessor(t *testing.T) {
+	const rows, cols = 3, 3
+	universeOf := func(bits int) map[Cell]struct{} {
+		cells := make(map[Cell]struct{})
+		for i := range rows * cols {
+			if bits&(1<<i) != 0 {
+				cells[Cell{i / cols, i % cols}] = struct{}{}
+			}
+		}
+		return cells
+	}
+	bitsOf := func(cells map[Cell]struct{}) int {
+		bits := 0
+		for cell := range cells {
+			bits |= 1 << (cell.R*cols + cell.C)
+		}
+		return bits
+	}
+
+	g := CreateSeedUniverse(rows, cols, Default, ConwayRule{})
+	for _, topology := range Topologies() {
+		g.SetTopology(topology)
+		reachable := make(map[int]bool)
+		for bits := range 1 << (rows * cols) {
+			g.universe = universeOf(bits)
+			g.CreateNextGeneration()
+			reachable[bitsOf(g.universe)] = true
+		}
+
+		for bits := range 1 << (rows * cols) {
+			g.universe = universeOf(bits)
+			predecessor, err := g.FindPredecessor(0)
+			if !reachable[bits] {
+				if !errors.Is(err, ErrNoPredecessor) {
+					t.Fatalf("%s universe %#x: FindPredecessor() error = %v; want ErrNoPredecessor", topology, bits, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s universe %#x: FindPredecessor() error = %v", topology, bits, err)
+			}
+			predecessor.CreateNextGeneration()
+			if got := bitsOf(predecessor.universe); got != bits {
+				t.Fatalf("%s universe %#x: predecessor evolves into %#x", topology, bits, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package gameoflife
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// leafLevel is the level of the smallest quadtree node; leaves are 8x8 bitmaps,
+// matching the leaves of Golly's macrocell format.
+const leafLevel = 3
+
+// maxQuadTreeLevel bounds the tree so that cell coordinates always fit in an int.
+const maxQuadTreeLevel = 62
+
+// quadNode is a canonical (hash-consed) quadtree node covering a 2^level x 2^level square.
+// Two nodes with the same content are the same pointer, so huge regular patterns
+// are stored in a tiny number of nodes.
+type quadNode struct {
+	level          uint8
+	nw, ne, sw, se *quadNode
+	// leaf holds the 8x8 bitmap of a leaf node, bit r*8+c set for a live cell
+	leaf       uint64
+	population uint64
+}
+
+type quadChildren struct {
+	nw, ne, sw, se *quadNode
+}
+
+// quadStore interns quadtree nodes so that equal sub-trees are shared.
+type quadStore struct {
+	leaves map[uint64]*quadNode
+	nodes  map[quadChildren]*quadNode
+	empty  []*quadNode // empty node per level
+}
+
+func newQuadStore() *quadStore {
+	return &quadStore{
+		leaves: make(map[uint64]*quadNode),
+		nodes:  make(map[quadChildren]*quadNode),
+	}
+}
+
+func (s *quadStore) leafNode(bitmap uint64) *quadNode {
+	if n, ok := s.leaves[bitmap]; ok {
+		return n
+	}
+	n := &quadNode{level: leafLevel, leaf: bitmap, population: uint64(bits.OnesCount64(bitmap))}
+	s.leaves[bitmap] = n
+	return n
+}
+
+func (s *quadStore) node(nw, ne, sw, se *quadNode) *quadNode {
+	key := quadChildren{nw, ne, sw, se}
+	if n, ok := s.nodes[key]; ok {
+		return n
+	}
+	n := &quadNode{
+		level:      nw.level + 1,
+		nw:         nw,
+		ne:         ne,
+		sw:         sw,
+		se:         se,
+		population: saturatingAdd(saturatingAdd(nw.population, ne.population), saturatingAdd(sw.population, se.population)),
+	}
+	s.nodes[key] = n
+	return n
+}
+
+func (s *quadStore) emptyNode(level uint8) *quadNode {
+	for len(s.empty) <= int(level) {
+		l := uint8(len(s.empty))
+		switch {
+		case l < leafLevel:
+			s.empty = append(s.empty, nil)
+		case l == leafLevel:
+			s.empty = append(s.empty, s.leafNode(0))
+		default:
+			e := s.empty[l-1]
+			s.empty = append(s.empty, s.node(e, e, e, e))
+		}
+	}
+	return s.empty[level]
+}
+
+// QuadTree is a square region stored as a hash-consed quadtree. It can hold regular
+// patterns with trillions of cells, far beyond what map[Cell]struct{} can, for reading
+// and writing macrocell files; it is not stepped.
+// The tree is centred on the origin: a tree of level k covers rows and columns
+// -2^(k-1) .. 2^(k-1)-1, like Golly's macrocell format.
+type QuadTree struct {
+	store *quadStore
+	root  *quadNode
+}
+
+// NewQuadTreeFromCells builds a quadtree holding the given cells. It returns an error if
+// a cell lies beyond the largest tree, 2^61 cells from the origin.
+func NewQuadTreeFromCells(cells map[Cell]struct{}) (*QuadTree, error) {
+	t := &QuadTree{store: newQuadStore()}
+
+	level := uint8(leafLevel)
+	for cell := range cells {
+		for !t.fits(level, cell) {
+			if level == maxQuadTreeLevel {
+				return nil, fmt.Errorf("cell %v lies beyond the largest quadtree, %d cells from the origin", cell, 1<<(maxQuadTreeLevel-1))
+			}
+			level++
+		}
+	}
+
+	list := cellsOf(cells)
+	half := 1 << (level - 1)
+	t.root = t.build(level, -half, -half, list)
+	return t, nil
+}
+
+func (t *QuadTree) fits(level uint8, cell Cell) bool {
+	half := 1 << (level - 1)
+	return cell.R >= -half && cell.R < half && cell.C >= -half && cell.C < half
+}
+
+// build returns the node of the given level whose top-left cell is (top, left)
+// holding the given cells, all of which lie inside it.
+func (t *QuadTree) build(level uint8, top, left int, cells []Cell) *quadNode {
+	if len(cells) == 0 {
+		return t.store.emptyNode(level)
+	}
+	if level == leafLevel {
+		bitmap := uint64(0)
+		for _, cell := range cells {
+			bitmap |= 1 << ((cell.R-top)*8 + (cell.C - left))
+		}
+		return t.store.leafNode(bitmap)
+	}
+
+	half := 1 << (level - 1)
+	var quadrants [4][]Cell
+	for _, cell := range cells {
+		q := 0
+		if cell.R >= top+half {
+			q += 2
+		}
+		if cell.C >= left+half {
+			q++
+		}
+		quadrants[q] = append(quadrants[q], cell)
+	}
+	return t.store.node(
+		t.build(level-1, top, left, quadrants[0]),
+		t.build(level-1, top, left+half, quadrants[1]),
+		t.build(level-1, top+half, left, quadrants[2]),
+		t.build(level-1, top+half, left+half, quadrants[3]),
+	)
+}
+
+// Level returns the tree's level; it covers a 2^level x 2^level square.
+func (t *QuadTree) Level() int {
+	return int(t.root.level)
+}
+
+// Population returns the number of live cells, saturating at the maximum uint64.
+func (t *QuadTree) Population() uint64 {
+	return t.root.population
+}
+
+// NodeCount returns the number of distinct nodes needed to store the tree.
+func (t *QuadTree) NodeCount() int {
+	seen := make(map[*quadNode]struct{})
+	var walk func(n *quadNode)
+	walk = func(n *quadNode) {
+		if _, ok := seen[n]; ok {
+			return
+		}
+		seen[n] = struct{}{}
+		if n.level > leafLevel {
+			walk(n.nw)
+			walk(n.ne)
+			walk(n.sw)
+			walk(n.se)
+		}
+	}
+	walk(t.root)
+	return len(seen)
+}
+
+// Bounds returns the top-left corner and size of the smallest rectangle containing all
+// live cells, like Pattern.Bounds, without expanding the tree.
+func (t *QuadTree) Bounds() (minCell Cell, height, width int) {
+	if t.root.population == 0 {
+		return Cell{}, 0, 0
+	}
+	// boxes of the live cells of each node, relative to the node's top-left cell
+	type box struct{ top, left, bottom, right int }
+	boxes := make(map[*quadNode]box)
+	var boxOf func(n *quadNode) box
+	boxOf = func(n *quadNode) box {
+		if b, ok := boxes[n]; ok {
+			return b
+		}
+		b := box{top: math.MaxInt, left: math.MaxInt, bottom: math.MinInt, right: math.MinInt}
+		add := func(top, left, bottom, right int) {
+			b.top, b.left = min(b.top, top), min(b.left, left)
+			b.bottom, b.right = max(b.bottom, bottom), max(b.right, right)
+		}
+		if n.level == leafLevel {
+			for bitmap := n.leaf; bitmap != 0; bitmap &= bitmap - 1 {
+				i := bits.TrailingZeros64(bitmap)
+				add(i/8, i%8, i/8, i%8)
+			}
+		} else {
+			h := 1 << (n.level - 1)
+			for i, child := range []*quadNode{n.nw, n.ne, n.sw, n.se} {
+				if child.population > 0 {
+					c := boxOf(child)
+					r, col := i/2*h, i%2*h
+					add(c.top+r, c.left+col, c.bottom+r, c.right+col)
+				}
+			}
+		}
+		boxes[n] = b
+		return b
+	}
+	b := boxOf(t.root)
+	half := 1 << (t.root.level - 1)
+	return Cell{b.top - half, b.left - half}, b.bottom - b.top + 1, b.right - b.left + 1
+}
+
+// Cells expands the tree into a cell set. It refuses to expand more than limit cells.
+func (t *QuadTree) Cells(limit uint64) (map[Cell]struct{}, error) {
+	if t.root.population > limit {
+		return nil, fmt.Errorf("pattern has %d cells, more than the limit of %d", t.root.population, limit)
+	}
+
+	cells := make(map[Cell]struct{}, t.root.population)
+	half := 1 << (t.root.level - 1)
+	var walk func(n *quadNode, top, left int)
+	walk = func(n *quadNode, top, left int) {
+		if n.population == 0 {
+			return
+		}
+		if n.level == leafLevel {
+			for bitmap := n.leaf; bitmap != 0; bitmap &= bitmap - 1 {
+				i := bits.TrailingZeros64(bitmap)
+				cells[Cell{top + i/8, left + i%8}] = struct{}{}
+			}
+			return
+		}
+		h := 1 << (n.level - 1)
+		walk(n.nw, top, left)
+		walk(n.ne, top, left+h)
+		walk(n.sw, top+h, left)
+		walk(n.se, top+h, left+h)
+	}
+	walk(t.root, -half, -half)
+	return cells, nil
+}
+
+func saturatingAdd(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return ^uint64(0)
+	}
+	return sum
+}
@@ -0,0 +1,135 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// RenderFormat selects how a whole universe is drawn by Render.
+type RenderFormat int
+
+const (
+	// RenderANSI draws the universe with terminal colours, as Display does.
+	RenderANSI RenderFormat = iota
+	// RenderText draws one line per row, `O` for live and `.` for dead cells.
+	RenderText
+	// RenderPNG draws an image with every cell a square of pixels.
+	RenderPNG
+)
+
+func (f RenderFormat) String() string {
+	switch f {
+	case RenderText:
+		return "text"
+	case RenderPNG:
+		return "png"
+	default:
+		return "ansi"
+	}
+}
+
+// ParseRenderFormat returns the render format with the given name: ansi, text or png.
+func ParseRenderFormat(name string) (RenderFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "ansi", "terminal", "":
+		return RenderANSI, nil
+	case "text", "txt":
+		return RenderText, nil
+	case "png":
+		return RenderPNG, nil
+	default:
+		return RenderANSI, fmt.Errorf("unknown render format %q, want ansi, text or png", name)
+	}
+}
+
+// Render draws the whole universe to w in the given format. scale is the size in pixels of
+// a cell in PNG images and ignored otherwise.
+func (g *GameOfLife) Render(w io.Writer, format RenderFormat, scale int) error {
+	switch format {
+	case RenderText:
+		return g.WriteText(w)
+	case RenderPNG:
+		return g.WriteImage(w, scale)
+	default:
+		return g.WriteANSI(w)
+	}
+}
+
+// WriteANSI draws the universe with terminal colours, like Display.
+func (g *GameOfLife) WriteANSI(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "==============")
+
+	highlighted := g.highlightedCells()
+	for rowIndex := range g.numRows {
+		for colIndex := range g.numCols {
+			if _, ok := highlighted[Cell{rowIndex, colIndex}]; ok {
+				fmt.Fprint(out, " ", redChar)
+			} else if age, ok := g.Age(Cell{rowIndex, colIndex}); ok && g.colourMode == ColourByAge {
+				fmt.Fprint(out, " ", ageChar(age))
+			} else if colour, ok := g.Colour(Cell{rowIndex, colIndex}); ok && g.colours != nil {
+				fmt.Fprint(out, " ", colourChars[colour-1])
+			} else if _, ok := g.universe[Cell{rowIndex, colIndex}]; ok {
+				fmt.Fprint(out, " ", whiteChar)
+			} else {
+				fmt.Fprint(out, " ", blackChar)
+			}
+		}
+		fmt.Fprint(out, "\n\n")
+	}
+	return out.Flush()
+}
+
+// WriteText draws the universe as plain text, one line per row with `O` for live and `.`
+// for dead cells. Unlike a plaintext pattern file it always covers the whole universe.
+func (g *GameOfLife) WriteText(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for r := range g.numRows {
+		for c := range g.numCols {
+			if _, alive := g.universe[Cell{r, c}]; alive {
+				out.WriteByte('O')
+			} else {
+				out.WriteByte('.')
+			}
+		}
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// imageColours are the colours of live cells in images under a colour scheme, colour 1
+// first, matching the terminal colours.
+var imageColours = []color.NRGBA{
+	{0x3b, 0x6e, 0xe0, 0xff}, // blue
+	{0xe0, 0xc0, 0x30, 0xff}, // yellow
+	{0x40, 0xb0, 0x40, 0xff}, // green
+	{0xc0, 0x40, 0xc0, 0xff}, // magenta
+}
+
+// WriteImage draws the universe as a PNG image with each cell a scale x scale square:
+// black for dead cells, white for live ones, or their colour under a colour scheme.
+func (g *GameOfLife) WriteImage(w io.Writer, scale int) error {
+	scale = max(scale, 1)
+	img := image.NewNRGBA(image.Rect(0, 0, g.numCols*scale, g.numRows*scale))
+	for r := range g.numRows {
+		for c := range g.numCols {
+			colour := color.NRGBA{0, 0, 0, 0xff}
+			if shade, alive := g.Colour(Cell{r, c}); alive && g.colours != nil {
+				colour = imageColours[shade-1]
+			} else if alive {
+				colour = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			for y := r * scale; y < (r+1)*scale; y++ {
+				for x := c * scale; x < (c+1)*scale; x++ {
+					img.SetNRGBA(x, y, colour)
+				}
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,51 @@
+package gameoflife
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	g := CreateSeedUniverse(3, 4, Default, ConwayRule{})
+	g.universe = map[Cell]struct{}{{0, 1}: {}, {2, 3}: {}}
+
+	var text bytes.Buffer
+	if err := g.Render(&text, RenderText, 0); err != nil {
+		t.Fatalf("Render(text) error = %v", err)
+	}
+	if want := ".O..\n....\n...O\n"; text.String() != want {
+		t.Errorf("Render(text) = %q; want %q", text.String(), want)
+	}
+
+	var ansi bytes.Buffer
+	if err := g.Render(&ansi, RenderANSI, 0); err != nil {
+		t.Fatalf("Render(ansi) error = %v", err)
+	}
+	if got := strings.Count(ansi.String(), whiteChar); got != 2 {
+		t.Errorf("Render(ansi) drew %d live cells; want 2", got)
+	}
+
+	var image bytes.Buffer
+	if err := g.Render(&image, RenderPNG, 3); err != nil {
+		t.Fatalf("Render(png) error = %v", err)
+	}
+	img, err := png.Decode(&image)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 12 || size.Y != 9 {
+		t.Errorf("image size = %v; want 12x9", size)
+	}
+	live := color.NRGBAModel.Convert(img.At(4, 1)).(color.NRGBA)
+	dead := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA)
+	if live.R != 0xff || dead.R != 0 {
+		t.Errorf("live pixel = %v, dead pixel = %v; want white and black", live, dead)
+	}
+
+	if _, err := ParseRenderFormat("svg"); err == nil {
+		t.Errorf("ParseRenderFormat(svg) succeeded; want an error")
+	}
+}
@@ -1,5 +1,10 @@
 package gameoflife
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Rule interface defines the structure for rules that can be applied to cells in the Game of Life.
 // The Apply method takes a cell, its alive status, the count of its live neighbors,
 // and a pointer to the GameOfLife instance. It returns a boolean indicating whether the cell
@@ -10,9 +15,23 @@ type Rule interface {
 	Apply(cell Cell, alive bool, neighborCount int, g *GameOfLife) bool
 }
 
+// TotalisticRule is implemented by rules whose outcome depends only on whether the cell
+// is alive and how many live neighbours it has, not on which neighbours they are.
+// Searches that reason about a rule rather than run it, such as FindPredecessor, need this.
+type TotalisticRule interface {
+	Rule
+	// Next returns true if a cell in the given state with the given number of live
+	// neighbours is alive in the next generation.
+	Next(alive bool, neighborCount int) bool
+}
+
 type ConwayRule struct{}
 
 func (r ConwayRule) Apply(cell Cell, alive bool, neighborCount int, g *GameOfLife) bool {
+	return r.Next(alive, neighborCount)
+}
+
+func (r ConwayRule) Next(alive bool, neighborCount int) bool {
 	if alive {
 		// Underpopulation or Overcrowding
 		return neighborCount == 2 || neighborCount == 3
@@ -21,10 +40,114 @@ func (r ConwayRule) Apply(cell Cell, alive bool, neighborCount int, g *GameOfLif
 	return neighborCount == 3
 }
 
+// LifeLikeRule is an outer-totalistic rule written as a rulestring such as B3/S23 (Conway's
+// rule) or B36/S23 (HighLife): a dead cell is born with a number of live neighbours listed
+// after B, and a live cell survives with a number listed after S.
+type LifeLikeRule struct {
+	Birth    [9]bool
+	Survival [9]bool
+}
+
+// ParseLifeLikeRule parses a rulestring in B/S notation, in either order and any case.
+// Rules with B0 are rejected: a universe cannot turn on every empty cell at once.
+func ParseLifeLikeRule(rulestring string) (LifeLikeRule, error) {
+	var r LifeLikeRule
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(rulestring)), "/")
+	if len(parts) != 2 {
+		return r, fmt.Errorf("rulestring %q: want the form B3/S23", rulestring)
+	}
+
+	seen := map[byte]bool{}
+	for _, part := range parts {
+		if part == "" || (part[0] != 'B' && part[0] != 'S') || seen[part[0]] {
+			return r, fmt.Errorf("rulestring %q: want the form B3/S23", rulestring)
+		}
+		seen[part[0]] = true
+		counts := &r.Birth
+		if part[0] == 'S' {
+			counts = &r.Survival
+		}
+		for _, digit := range part[1:] {
+			if digit < '0' || digit > '8' {
+				return r, fmt.Errorf("rulestring %q: invalid neighbour count %q", rulestring, digit)
+			}
+			counts[digit-'0'] = true
+		}
+	}
+	if r.Birth[0] {
+		return r, fmt.Errorf("rulestring %q: B0 rules are not supported", rulestring)
+	}
+	return r, nil
+}
+
+// String returns the rulestring in B/S notation.
+func (r LifeLikeRule) String() string {
+	var sb strings.Builder
+	sb.WriteByte('B')
+	for count, born := range r.Birth {
+		if born {
+			sb.WriteByte(byte('0' + count))
+		}
+	}
+	sb.WriteString("/S")
+	for count, survives := range r.Survival {
+		if survives {
+			sb.WriteByte(byte('0' + count))
+		}
+	}
+	return sb.String()
+}
+
+func (r LifeLikeRule) Apply(cell Cell, alive bool, neighborCount int, g *GameOfLife) bool {
+	return r.Next(alive, neighborCount)
+}
+
+func (r LifeLikeRule) Next(alive bool, neighborCount int) bool {
+	if alive {
+		return r.Survival[neighborCount]
+	}
+	return r.Birth[neighborCount]
+}
+
 type NoTopLeftNeighborRule struct{}
 
 func (r NoTopLeftNeighborRule) Apply(cell Cell, alive bool, neighborCount int, g *GameOfLife) bool {
-	topLeftCell := g._wrapCellWithinUniverse(Cell{cell.R - 1, cell.C - 1})
+	topLeftCell, ok := g.neighbour(cell, Cell{-1, -1})
 	_, hasTopLeft := g.universe[topLeftCell]
+	hasTopLeft = hasTopLeft && ok
 	return !hasTopLeft && alive
 }
+
+// AgeLimitRule makes cells die of old age: a live cell that has been alive for MaxAge
+// generations dies whatever Rule says. It reads ages with Age, so the universe needs age
+// tracking; without it no cell ever counts as old.
+type AgeLimitRule struct {
+	Rule   Rule
+	MaxAge int
+}
+
+func (r AgeLimitRule) Apply(cell Cell, alive bool, neighborCount int, g *GameOfLife) bool {
+	if age, ok := g.Age(cell); ok && alive && age >= r.MaxAge {
+		return false
+	}
+	return r.Rule.Apply(cell, alive, neighborCount, g)
+}
+
+// transitionTable tabulates a set of totalistic rules: table[alive][count] is whether a
+// cell survives or is born. As in CreateNextGeneration, a cell is alive if any rule says so,
+// and a dead cell without live neighbours stays dead.
+func transitionTable(rules []Rule) ([2][9]bool, error) {
+	var table [2][9]bool
+	for _, rule := range rules {
+		totalistic, ok := rule.(TotalisticRule)
+		if !ok {
+			return table, fmt.Errorf("rule %T depends on more than the number of live neighbours", rule)
+		}
+		for count := range 9 {
+			table[0][count] = table[0][count] || totalistic.Next(false, count)
+			table[1][count] = table[1][count] || totalistic.Next(true, count)
+		}
+	}
+	table[0][0] = false
+	return table, nil
+}
@@ -0,0 +1,342 @@
+package gameoflife
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// RuleClass is the typical behaviour of random soups under a rule, after Wolfram's classes.
+type RuleClass int
+
+const (
+	// ClassDies rules make soups die out.
+	ClassDies RuleClass = iota
+	// ClassStable rules make soups settle into still lifes.
+	ClassStable
+	// ClassPeriodic rules make soups settle into oscillating ash.
+	ClassPeriodic
+	// ClassChaotic rules keep soups active without growing much.
+	ClassChaotic
+	// ClassExplosive rules make soups grow to fill the universe.
+	ClassExplosive
+)
+
+var ruleClassNames = []string{"dies", "stable", "periodic", "chaotic", "explosive"}
+
+func (c RuleClass) String() string {
+	if c < 0 || int(c) >= len(ruleClassNames) {
+		return "unknown"
+	}
+	return ruleClassNames[c]
+}
+
+func (c RuleClass) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// explosionFactor is how many times its starting population a soup must reach to count
+// as explosive. Soups under Conway's rule stay below 4; rules that fill the plane reach 6
+// even on the small tori used for exploring.
+const explosionFactor = 6
+
+// RuleExplorerOptions configure ExploreRules. Zero values select the defaults.
+type RuleExplorerOptions struct {
+	// Rules are the rules to examine; if empty, Samples rules are drawn at random.
+	Rules []LifeLikeRule
+	// Samples is the number of random rules to examine (100).
+	Samples int
+	// Seed selects the random rules.
+	Seed uint64
+	// SoupsPerRule is the number of soups run under each rule (4). Soup i uses the same
+	// seed, SeedPrefix followed by i, under every rule, so rules are compared on equal terms.
+	SoupsPerRule int
+	SeedPrefix   string
+	// UniverseSize is the side of the torus soups are run in (64).
+	UniverseSize int
+	// Generations is the longest a soup is run (500).
+	Generations int
+	// Workers is the number of rules examined in parallel, defaulting to the number of CPUs.
+	Workers int
+	// Progress, if set, receives a line for every rule examined.
+	Progress io.Writer
+}
+
+func (o *RuleExplorerOptions) setDefaults() {
+	o.Samples = cmp.Or(max(o.Samples, 0), 100)
+	o.SoupsPerRule = cmp.Or(max(o.SoupsPerRule, 0), 4)
+	o.UniverseSize = cmp.Or(max(o.UniverseSize, 0), 64)
+	o.Generations = cmp.Or(max(o.Generations, 0), 500)
+	o.Workers = cmp.Or(max(o.Workers, 0), runtime.NumCPU())
+	o.SeedPrefix = cmp.Or(o.SeedPrefix, "rule_")
+}
+
+// RuleReport holds the metrics of one rule, averaged over its soups.
+type RuleReport struct {
+	Rule  string    `json:"rule"`
+	Class RuleClass `json:"class"`
+	// Growth is the final population divided by the starting population.
+	Growth float64 `json:"growth"`
+	// Activity is the fraction of the universe that is born or dies per generation.
+	Activity float64 `json:"activity"`
+	// Entropy is the Shannon entropy, in bits, of the 2x2 blocks of the final generation:
+	// 0 for an empty universe, at most 4 for a random one.
+	Entropy float64 `json:"entropy"`
+	// StabilisationTime is the mean number of generations until the soups that settled
+	// did so, or -1 if none settled.
+	StabilisationTime float64 `json:"stabilisation_time"`
+	// Classes counts the soups by class; Class is the most common.
+	Classes map[string]int `json:"classes"`
+}
+
+// ruleReportColumns are the columns of a report in CSV and the keys it can be sorted by.
+var ruleReportColumns = []string{"rule", "class", "growth", "activity", "entropy", "stabilisation_time"}
+
+func (r RuleReport) csvRecord() []string {
+	record := []string{
+		r.Rule, r.Class.String(),
+		strconv.FormatFloat(r.Growth, 'f', 3, 64),
+		strconv.FormatFloat(r.Activity, 'f', 4, 64),
+		strconv.FormatFloat(r.Entropy, 'f', 3, 64),
+		strconv.FormatFloat(r.StabilisationTime, 'f', 1, 64),
+	}
+	for _, name := range ruleClassNames {
+		record = append(record, strconv.Itoa(r.Classes[name]))
+	}
+	return record
+}
+
+// ExploreRules runs random soups under many Life-like rules and classifies each rule by
+// what its soups do. Reports are in the order the rules were given or drawn.
+func ExploreRules(opts RuleExplorerOptions) []RuleReport {
+	opts.setDefaults()
+	rules := opts.Rules
+	if len(rules) == 0 {
+		rules = sampleRules(opts.Samples, opts.Seed)
+	}
+
+	reports := make([]RuleReport, len(rules))
+	var wg sync.WaitGroup
+	var progress sync.Mutex
+	next := make(chan int)
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				reports[i] = exploreRule(rules[i], opts)
+				if opts.Progress != nil {
+					progress.Lock()
+					fmt.Fprintf(opts.Progress, "%-20s %s\n", reports[i].Rule, reports[i].Class)
+					progress.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range rules {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return reports
+}
+
+// sampleRules draws distinct random Life-like rules without B0.
+func sampleRules(n int, seed uint64) []LifeLikeRule {
+	rng := rand.New(rand.NewPCG(seed, seed^0x5851f42d4c957f2d))
+	seen := make(map[LifeLikeRule]bool)
+	rules := make([]LifeLikeRule, 0, n)
+	// there are 2^17 rules without B0, so only ask for as many as exist
+	for len(rules) < min(n, 1<<17) {
+		var rule LifeLikeRule
+		for count := range 9 {
+			rule.Birth[count] = count > 0 && rng.IntN(2) == 1
+			rule.Survival[count] = rng.IntN(2) == 1
+		}
+		if !seen[rule] {
+			seen[rule] = true
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// soupMetrics is what happened to one soup.
+type soupMetrics struct {
+	class            RuleClass
+	growth, activity float64
+	entropy          float64
+	settled          bool
+	settleTime       int
+}
+
+func exploreRule(rule LifeLikeRule, opts RuleExplorerOptions) RuleReport {
+	report := RuleReport{Rule: rule.String(), Classes: make(map[string]int)}
+	settled, settleTimes := 0, 0
+	counts := make([]int, len(ruleClassNames))
+	for i := range opts.SoupsPerRule {
+		m := runRuleSoup(rule, opts.SeedPrefix+strconv.Itoa(i), opts)
+		counts[m.class]++
+		report.Growth += m.growth
+		report.Activity += m.activity
+		report.Entropy += m.entropy
+		if m.settled {
+			settled++
+			settleTimes += m.settleTime
+		}
+	}
+
+	n := float64(opts.SoupsPerRule)
+	report.Growth /= n
+	report.Activity /= n
+	report.Entropy /= n
+	report.StabilisationTime = -1
+	if settled > 0 {
+		report.StabilisationTime = float64(settleTimes) / float64(settled)
+	}
+	for class, count := range counts {
+		report.Classes[ruleClassNames[class]] = count
+		// ties go to the more active class
+		if count >= counts[report.Class] {
+			report.Class = RuleClass(class)
+		}
+	}
+	return report
+}
+
+// runRuleSoup runs one soup until it settles, explodes or runs out of generations.
+func runRuleSoup(rule LifeLikeRule, seed string, opts RuleExplorerOptions) soupMetrics {
+	size := opts.UniverseSize
+	g := CreateSeedUniverse(size, size, Default, rule)
+	g.universe = make(map[Cell]struct{})
+	offset := (size - SoupSize) / 2
+	for cell := range GenerateSoup(seed) {
+		if wrapped, ok := g.neighbour(cell, Cell{offset, offset}); ok {
+			g.universe[wrapped] = struct{}{}
+		}
+	}
+
+	initial := len(g.universe)
+	populations := []int{initial}
+	changes := 0
+	m := soupMetrics{class: ClassChaotic}
+	for g.generation < opts.Generations {
+		g.CreateNextGeneration()
+		changes += g.lastBirths + g.lastDeaths
+		populations = append(populations, len(g.universe))
+
+		if len(g.universe) >= explosionFactor*initial {
+			m.class = ClassExplosive
+			break
+		}
+		if period := populationPeriod(populations); period > 0 {
+			m.settled, m.settleTime = true, settleTime(populations, period)
+			switch {
+			case len(g.universe) == 0:
+				m.class = ClassDies
+			case period == 1:
+				m.class = ClassStable
+			default:
+				m.class = ClassPeriodic
+			}
+			break
+		}
+	}
+
+	m.growth = float64(len(g.universe)) / float64(max(initial, 1))
+	m.activity = float64(changes) / float64(max(g.generation, 1)) / float64(size*size)
+	m.entropy = g.blockEntropy()
+	return m
+}
+
+// blockEntropy returns the Shannon entropy, in bits, of the 2x2 blocks tiling the universe.
+func (g *GameOfLife) blockEntropy() float64 {
+	var counts [16]int
+	blocks := 0
+	for r := 0; r+1 < g.numRows; r += 2 {
+		for c := 0; c+1 < g.numCols; c += 2 {
+			state := 0
+			for i, offset := range []Cell{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+				if _, alive := g.universe[Cell{r + offset.R, c + offset.C}]; alive {
+					state |= 1 << i
+				}
+			}
+			counts[state]++
+			blocks++
+		}
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(blocks)
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// SortRuleReports sorts reports by one of the report columns (rule, class, growth,
+// activity, entropy, stabilisation_time), ascending or descending.
+func SortRuleReports(reports []RuleReport, key string, descending bool) error {
+	var compare func(a, b RuleReport) int
+	switch key {
+	case "rule":
+		compare = func(a, b RuleReport) int { return cmp.Compare(a.Rule, b.Rule) }
+	case "class":
+		compare = func(a, b RuleReport) int { return cmp.Compare(a.Class, b.Class) }
+	case "growth":
+		compare = func(a, b RuleReport) int { return cmp.Compare(a.Growth, b.Growth) }
+	case "activity":
+		compare = func(a, b RuleReport) int { return cmp.Compare(a.Activity, b.Activity) }
+	case "entropy":
+		compare = func(a, b RuleReport) int { return cmp.Compare(a.Entropy, b.Entropy) }
+	case "stabilisation_time":
+		compare = func(a, b RuleReport) int { return cmp.Compare(a.StabilisationTime, b.StabilisationTime) }
+	default:
+		return fmt.Errorf("cannot sort by %q, want one of %v", key, ruleReportColumns)
+	}
+
+	slices.SortStableFunc(reports, func(a, b RuleReport) int {
+		if descending {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+	return nil
+}
+
+// WriteRuleReports writes reports as CSV, with one column per class counting its soups,
+// or as JSON Lines.
+func WriteRuleReports(w io.Writer, reports []RuleReport, format StatsFormat) error {
+	if format == StatsJSONLines {
+		enc := json.NewEncoder(w)
+		for _, report := range reports {
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	out := csv.NewWriter(w)
+	header := slices.Concat(ruleReportColumns, ruleClassNames)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		if err := out.Write(report.csvRecord()); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
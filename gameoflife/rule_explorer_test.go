@@ -0,0 +1,77 @@
+package gameoflife
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExploreRules_Classes(t *testing.T) {
+	tests := []struct {
+		rule string
+		want RuleClass
+	}{
+		{"B3/S23", ClassStable},
+		{"B5/S", ClassDies},
+		{"B2/S", ClassExplosive},
+		{"B3/S012345678", ClassExplosive},
+	}
+	var rules []LifeLikeRule
+	for _, tt := range tests {
+		rule, err := ParseLifeLikeRule(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseLifeLikeRule(%q) error = %v", tt.rule, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	reports := ExploreRules(RuleExplorerOptions{Rules: rules, SoupsPerRule: 2, Generations: 400})
+	for i, tt := range tests {
+		if reports[i].Rule != tt.rule || reports[i].Class != tt.want {
+			t.Errorf("report %d = %s %s; want %s %s", i, reports[i].Rule, reports[i].Class, tt.rule, tt.want)
+		}
+	}
+	if reports[1].Entropy != 0 || reports[1].Growth != 0 {
+		t.Errorf("B5/S: entropy %v, growth %v; want 0 for an empty universe", reports[1].Entropy, reports[1].Growth)
+	}
+	if reports[0].StabilisationTime <= 0 || reports[2].StabilisationTime != -1 {
+		t.Errorf("stabilisation times %v and %v; want positive for B3/S23 and -1 for B2/S", reports[0].StabilisationTime, reports[2].StabilisationTime)
+	}
+
+	if err := SortRuleReports(reports, "growth", true); err != nil {
+		t.Fatalf("SortRuleReports() error = %v", err)
+	}
+	if !slices.IsSortedFunc(reports, func(a, b RuleReport) int { return int(b.Growth*1000) - int(a.Growth*1000) }) {
+		t.Errorf("reports not sorted by descending growth")
+	}
+	if err := SortRuleReports(reports, "colour", false); err == nil {
+		t.Errorf("SortRuleReports() by an unknown column succeeded; want an error")
+	}
+
+	var csv bytes.Buffer
+	if err := WriteRuleReports(&csv, reports, StatsCSV); err != nil {
+		t.Fatalf("WriteRuleReports() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != len(tests)+1 || !strings.HasPrefix(lines[0], "rule,class,growth") {
+		t.Errorf("CSV report = %q; want a header and one line per rule", csv.String())
+	}
+}
+
+func TestSampleRules(t *testing.T) {
+	rules := sampleRules(50, 1)
+	if len(rules) != 50 {
+		t.Fatalf("sampleRules() returned %d rules; want 50", len(rules))
+	}
+	seen := make(map[LifeLikeRule]bool)
+	for _, rule := range rules {
+		if rule.Birth[0] || seen[rule] {
+			t.Errorf("rule %s is a B0 rule or a duplicate", rule)
+		}
+		seen[rule] = true
+	}
+	if !slices.Equal(rules, sampleRules(50, 1)) {
+		t.Errorf("sampleRules() is not deterministic")
+	}
+}
@@ -29,7 +29,8 @@ func RuleFactory(ruleType RuleType) Rule {
 	}
 }
 
-// ParseRulesFromString parses comma-separated rule names into []Rule.
+// ParseRulesFromString parses comma-separated rule names into []Rule. Besides the names
+// of AvailableRuleNames, Life-like rulestrings such as B36/S23 are accepted.
 func ParseRulesFromString(rulesString string) []Rule {
 	rules := make([]Rule, 0)
 
@@ -41,6 +42,8 @@ func ParseRulesFromString(rulesString string) []Rule {
 		ruleString = strings.ToLower(strings.TrimSpace(ruleString))
 		if ruleName, ok := ruleNameToType[ruleString]; ok {
 			rules = append(rules, RuleFactory(ruleName))
+		} else if rule, err := ParseLifeLikeRule(ruleString); err == nil {
+			rules = append(rules, rule)
 		}
 	}
 
@@ -55,3 +58,26 @@ func AvailableRuleNames() []string {
 	}
 	return keys
 }
+
+// RuleName returns the name under which the given rule can be parsed by ParseRulesFromString.
+// Life-like rules are named by their rulestring. It returns an empty string for rules that
+// are not registered.
+func RuleName(rule Rule) string {
+	var ruleType RuleType
+	switch rule := rule.(type) {
+	case LifeLikeRule:
+		return rule.String()
+	case ConwayRule:
+		ruleType = ConwayRuleType
+	case NoTopLeftNeighborRule:
+		ruleType = NoTopLeftNeighborRuleType
+	default:
+		return ""
+	}
+	for name, t := range ruleNameToType {
+		if t == ruleType {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,426 @@
+package gameoflife
+
+import (
+	"slices"
+	"time"
+)
+
+// satResult is the outcome of a SAT search.
+type satResult int
+
+const (
+	satUnknown satResult = iota // gave up, e.g. because the time limit was reached
+	satSatisfiable
+	satUnsatisfiable
+)
+
+// satSolver is a small CDCL (conflict-driven clause learning) SAT solver with
+// two watched literals, first-UIP learning, VSIDS branching, phase saving and
+// Luby restarts. Variables are numbered from 1 and literals use the DIMACS
+// convention: v is the variable being true, -v it being false.
+type satSolver struct {
+	numVars  int
+	clauses  [][]int // literals in internal encoding, see lit
+	watches  [][]int // internal literal -> clauses watching it
+	value    []int8  // per variable: 0 unassigned, 1 true, -1 false
+	level    []int
+	reason   []int // clause that implied the variable, -1 for decisions
+	trail    []int // internal literals in assignment order
+	trailLim []int // trail length at the start of each decision level
+	qhead    int
+
+	activity []float64
+	bump     float64
+	heap     []int // variables ordered by activity
+	heapPos  []int // position of each variable in heap, -1 if absent
+	phase    []bool
+
+	unsat bool // an empty clause was added
+}
+
+func newSatSolver() *satSolver {
+	return &satSolver{
+		value:    []int8{0},
+		level:    []int{0},
+		reason:   []int{-1},
+		activity: []float64{0},
+		heapPos:  []int{-1},
+		phase:    []bool{false},
+		bump:     1,
+	}
+}
+
+// newVar adds a variable and returns its number.
+func (s *satSolver) newVar() int {
+	s.numVars++
+	s.value = append(s.value, 0)
+	s.level = append(s.level, 0)
+	s.reason = append(s.reason, -1)
+	s.activity = append(s.activity, 0)
+	s.heapPos = append(s.heapPos, -1)
+	s.phase = append(s.phase, false)
+	s.watches = append(s.watches, nil, nil)
+	s.heapInsert(s.numVars)
+	return s.numVars
+}
+
+// lit converts a DIMACS literal to the internal encoding 2*var+sign.
+func lit(dimacs int) int {
+	if dimacs < 0 {
+		return -2*dimacs + 1
+	}
+	return 2 * dimacs
+}
+
+func litVar(l int) int { return l >> 1 }
+
+// litValue returns 1 if the literal is true, -1 if false and 0 if unassigned.
+func (s *satSolver) litValue(l int) int8 {
+	v := s.value[litVar(l)]
+	if l&1 == 1 {
+		return -v
+	}
+	return v
+}
+
+// addClause adds a clause given as DIMACS literals. Clauses can be added between calls
+// to solve; the model of the previous call is lost.
+func (s *satSolver) addClause(dimacs ...int) {
+	if s.unsat {
+		return
+	}
+	s.backtrack(0)
+	clause := make([]int, 0, len(dimacs))
+	for _, d := range dimacs {
+		l := lit(d)
+		if slices.Contains(clause, l^1) {
+			return // tautology
+		}
+		if slices.Contains(clause, l) {
+			continue
+		}
+		switch s.litValue(l) {
+		case 1:
+			return // already satisfied at level 0
+		case -1:
+			continue
+		}
+		clause = append(clause, l)
+	}
+
+	switch len(clause) {
+	case 0:
+		s.unsat = true
+	case 1:
+		s.assign(clause[0], -1)
+		if s.propagate() >= 0 {
+			s.unsat = true
+		}
+	default:
+		s.attach(clause)
+	}
+}
+
+func (s *satSolver) attach(clause []int) int {
+	index := len(s.clauses)
+	s.clauses = append(s.clauses, clause)
+	s.watches[clause[0]^1-2] = append(s.watches[clause[0]^1-2], index)
+	s.watches[clause[1]^1-2] = append(s.watches[clause[1]^1-2], index)
+	return index
+}
+
+// watchers of literal l are stored at index l-2 (variables start at 1), keyed by the
+// negation of the watched literal: they are visited when that negation becomes true.
+
+func (s *satSolver) assign(l, reason int) {
+	v := litVar(l)
+	if l&1 == 1 {
+		s.value[v] = -1
+	} else {
+		s.value[v] = 1
+	}
+	s.level[v] = len(s.trailLim)
+	s.reason[v] = reason
+	s.trail = append(s.trail, l)
+}
+
+// propagate performs unit propagation and returns a conflicting clause, or -1.
+func (s *satSolver) propagate() int {
+	for s.qhead < len(s.trail) {
+		p := s.trail[s.qhead] // p just became true, so clauses watching ¬p must move
+		s.qhead++
+		watchers := s.watches[p-2]
+		kept := watchers[:0]
+		conflict := -1
+
+		for i, ci := range watchers {
+			if conflict >= 0 {
+				kept = append(kept, watchers[i:]...)
+				break
+			}
+			clause := s.clauses[ci]
+			falseLit := p ^ 1
+			if clause[0] == falseLit {
+				clause[0], clause[1] = clause[1], clause[0]
+			}
+			if s.litValue(clause[0]) == 1 {
+				kept = append(kept, ci)
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(clause); k++ {
+				if s.litValue(clause[k]) != -1 {
+					clause[1], clause[k] = clause[k], clause[1]
+					s.watches[clause[1]^1-2] = append(s.watches[clause[1]^1-2], ci)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			kept = append(kept, ci)
+			if s.litValue(clause[0]) == -1 {
+				conflict = ci
+			} else {
+				s.assign(clause[0], ci)
+			}
+		}
+		s.watches[p-2] = kept
+		if conflict >= 0 {
+			return conflict
+		}
+	}
+	return -1
+}
+
+// analyze derives a first-UIP clause from a conflict and returns it with the
+// level to backtrack to. The asserting literal is first.
+func (s *satSolver) analyze(conflict int) ([]int, int) {
+	seen := make([]bool, s.numVars+1)
+	learnt := []int{0}
+	pathCount := 0
+	p := -1
+	index := len(s.trail) - 1
+	currentLevel := len(s.trailLim)
+
+	for {
+		for _, q := range s.clauses[conflict] {
+			if q == p {
+				continue
+			}
+			v := litVar(q)
+			if seen[v] || s.level[v] == 0 {
+				continue
+			}
+			seen[v] = true
+			s.bumpActivity(v)
+			if s.level[v] == currentLevel {
+				pathCount++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+		for !seen[litVar(s.trail[index])] {
+			index--
+		}
+		p = s.trail[index]
+		index--
+		conflict = s.reason[litVar(p)]
+		pathCount--
+		if pathCount == 0 {
+			break
+		}
+	}
+	learnt[0] = p ^ 1
+
+	backtrackLevel := 0
+	for i := 1; i < len(learnt); i++ {
+		if l := s.level[litVar(learnt[i])]; l > backtrackLevel {
+			backtrackLevel = l
+			learnt[1], learnt[i] = learnt[i], learnt[1]
+		}
+	}
+	return learnt, backtrackLevel
+}
+
+func (s *satSolver) backtrack(level int) {
+	if len(s.trailLim) <= level {
+		return
+	}
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := litVar(s.trail[i])
+		s.phase[v] = s.value[v] == 1
+		s.value[v] = 0
+		s.reason[v] = -1
+		if s.heapPos[v] < 0 {
+			s.heapInsert(v)
+		}
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+func (s *satSolver) bumpActivity(v int) {
+	s.activity[v] += s.bump
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.bump *= 1e-100
+	}
+	if s.heapPos[v] >= 0 {
+		s.heapUp(s.heapPos[v])
+	}
+}
+
+// solve searches for a satisfying assignment until the deadline (zero for none).
+func (s *satSolver) solve(deadline time.Time) satResult {
+	if s.unsat {
+		return satUnsatisfiable
+	}
+	if s.propagate() >= 0 {
+		return satUnsatisfiable
+	}
+
+	conflicts := 0
+	restart := 1
+	nextRestart := 100 * luby(restart)
+	for {
+		if conflict := s.propagate(); conflict >= 0 {
+			conflicts++
+			if len(s.trailLim) == 0 {
+				return satUnsatisfiable
+			}
+			learnt, backtrackLevel := s.analyze(conflict)
+			s.backtrack(backtrackLevel)
+			if len(learnt) == 1 {
+				s.assign(learnt[0], -1)
+			} else {
+				s.assign(learnt[0], s.attach(learnt))
+			}
+			s.bump /= 0.95
+
+			if conflicts%256 == 0 && !deadline.IsZero() && time.Now().After(deadline) {
+				s.backtrack(0)
+				return satUnknown
+			}
+			if conflicts >= nextRestart {
+				restart++
+				nextRestart = conflicts + 100*luby(restart)
+				s.backtrack(0)
+			}
+			continue
+		}
+
+		v := s.pickBranchVar()
+		if v == 0 {
+			return satSatisfiable
+		}
+		s.trailLim = append(s.trailLim, len(s.trail))
+		if s.phase[v] {
+			s.assign(lit(v), -1)
+		} else {
+			s.assign(lit(-v), -1)
+		}
+	}
+}
+
+// modelValue returns the value of a variable in the satisfying assignment.
+func (s *satSolver) modelValue(v int) bool {
+	return s.value[v] == 1
+}
+
+// block adds a clause excluding the current model restricted to the given variables,
+// so that the next solve finds a different one.
+func (s *satSolver) block(vars []int) {
+	clause := make([]int, 0, len(vars))
+	for _, v := range vars {
+		if s.modelValue(v) {
+			clause = append(clause, -v)
+		} else {
+			clause = append(clause, v)
+		}
+	}
+	s.addClause(clause...)
+}
+
+func (s *satSolver) pickBranchVar() int {
+	for len(s.heap) > 0 {
+		v := s.heapPop()
+		if s.value[v] == 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// luby returns the i-th element (from 1) of the Luby sequence 1 1 2 1 1 2 4 ...
+func luby(i int) int {
+	for k := 1; ; k++ {
+		if i == (1<<k)-1 {
+			return 1 << (k - 1)
+		}
+		if i >= 1<<(k-1) && i < (1<<k)-1 {
+			return luby(i - (1 << (k - 1)) + 1)
+		}
+	}
+}
+
+func (s *satSolver) heapLess(a, b int) bool {
+	return s.activity[s.heap[a]] > s.activity[s.heap[b]]
+}
+
+func (s *satSolver) heapSwap(a, b int) {
+	s.heap[a], s.heap[b] = s.heap[b], s.heap[a]
+	s.heapPos[s.heap[a]] = a
+	s.heapPos[s.heap[b]] = b
+}
+
+func (s *satSolver) heapUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !s.heapLess(i, parent) {
+			return
+		}
+		s.heapSwap(i, parent)
+		i = parent
+	}
+}
+
+func (s *satSolver) heapDown(i int) {
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(s.heap) && s.heapLess(child, smallest) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		s.heapSwap(i, smallest)
+		i = smallest
+	}
+}
+
+func (s *satSolver) heapInsert(v int) {
+	s.heapPos[v] = len(s.heap)
+	s.heap = append(s.heap, v)
+	s.heapUp(len(s.heap) - 1)
+}
+
+func (s *satSolver) heapPop() int {
+	v := s.heap[0]
+	last := len(s.heap) - 1
+	s.heapSwap(0, last)
+	s.heap = s.heap[:last]
+	s.heapPos[v] = -1
+	if len(s.heap) > 0 {
+		s.heapDown(0)
+	}
+	return v
+}
@@ -0,0 +1,188 @@
+package gameoflife
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// lifeEncoding expresses generations of a totalistic rule as clauses of a SAT problem,
+// with one variable per cell and generation that may be alive. Variable 0 stands for a
+// cell that is always dead.
+type lifeEncoding struct {
+	solver *satSolver
+	table  [2][9]bool
+	truth  int // a variable fixed to true, see constant
+}
+
+func newLifeEncoding(table [2][9]bool) *lifeEncoding {
+	return &lifeEncoding{solver: newSatSolver(), table: table}
+}
+
+// constant returns a literal that is always true or always false.
+func (e *lifeEncoding) constant(value bool) int {
+	if e.truth == 0 {
+		e.truth = e.solver.newVar()
+		e.solver.addClause(e.truth)
+	}
+	if value {
+		return e.truth
+	}
+	return -e.truth
+}
+
+// mooreNeighbourVars returns the variables of the 8 neighbours of a cell, 0 for cells
+// without a variable.
+func mooreNeighbourVars(vars map[Cell]int, cell Cell) []int {
+	neighbours := make([]int, 0, 8)
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			if dr != 0 || dc != 0 {
+				neighbours = append(neighbours, vars[Cell{cell.R + dr, cell.C + dc}])
+			}
+		}
+	}
+	return neighbours
+}
+
+// transition adds clauses requiring that a cell with variable self and the given neighbour
+// variables is alive in the next generation exactly when the literal next is true.
+// A neighbour listed twice, as on very small tori, is counted twice.
+func (e *lifeEncoding) transition(self int, neighbours []int, next int) {
+	var inputs []int           // distinct variables involved
+	var weights []int          // how many times each input is a neighbour
+	index := make(map[int]int) // variable -> index in inputs
+	input := func(v int) int {
+		i, ok := index[v]
+		if !ok {
+			i = len(inputs)
+			index[v] = i
+			inputs = append(inputs, v)
+			weights = append(weights, 0)
+		}
+		return i
+	}
+
+	shape := transitionShape{self: -1, negated: next < 0}
+	if self != 0 {
+		shape.self = input(self)
+	}
+	for _, v := range neighbours {
+		if v != 0 {
+			weights[input(v)]++
+		}
+	}
+	shape.next = input(max(next, -next))
+	shape.weights = fmt.Sprint(weights)
+
+	key := transitionKey{e.table, shape}
+	cubes, ok := transitionCubes.Load(key)
+	if !ok {
+		cubes, _ = transitionCubes.LoadOrStore(key, violatingCubes(e.table, shape, weights))
+	}
+	for _, cube := range cubes.([]transitionCube) {
+		clause := make([]int, 0, len(inputs))
+		for i, v := range inputs {
+			switch {
+			case cube.care&(1<<i) == 0:
+			case cube.value&(1<<i) != 0:
+				clause = append(clause, -v)
+			default:
+				clause = append(clause, v)
+			}
+		}
+		e.solver.addClause(clause...)
+	}
+}
+
+// transitionShape describes how the inputs of a transition are wired: which input is the
+// cell itself (-1 for none), which is its next state and how often each is a neighbour.
+type transitionShape struct {
+	self, next int
+	negated    bool
+	weights    string
+}
+
+type transitionKey struct {
+	table [2][9]bool
+	shape transitionShape
+}
+
+// transitionCubes caches violatingCubes, which only depends on the rule and the shape of
+// the transition, and is the same for most cells of every search.
+var transitionCubes sync.Map
+
+// transitionCube is a set of assignments of a transition's inputs that all break the rule:
+// the inputs in care have the values in value, the others are free.
+type transitionCube struct {
+	care, value int
+}
+
+// violatingCubes covers every assignment of the inputs that breaks the rule with cubes,
+// each grown greedily by freeing inputs that do not matter, so that one short clause
+// rules out many assignments instead of one long clause each.
+func violatingCubes(table [2][9]bool, shape transitionShape, weights []int) []transitionCube {
+	n := len(weights)
+	violates := make([]bool, 1<<n)
+	for mask := range violates {
+		self := shape.self >= 0 && mask&(1<<shape.self) != 0
+		next := mask&(1<<shape.next) != 0 != shape.negated
+		count := 0
+		for i, weight := range weights {
+			if mask&(1<<i) != 0 {
+				count += weight
+			}
+		}
+		violates[mask] = table[boolIndex(self)][count] != next
+	}
+	allViolate := func(cube transitionCube) bool {
+		for mask := range violates {
+			if mask&cube.care == cube.value&cube.care && !violates[mask] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var cubes []transitionCube
+	for mask, violating := range violates {
+		if !violating || slices.ContainsFunc(cubes, func(c transitionCube) bool { return mask&c.care == c.value&c.care }) {
+			continue
+		}
+		cube := transitionCube{care: 1<<n - 1, value: mask}
+		for i := range n {
+			if wider := (transitionCube{care: cube.care &^ (1 << i), value: mask}); allViolate(wider) {
+				cube = wider
+			}
+		}
+		cubes = append(cubes, cube)
+	}
+	return cubes
+}
+
+// cells returns the cells whose variables are true in the solver's model.
+func (e *lifeEncoding) cells(vars map[Cell]int) map[Cell]struct{} {
+	cells := make(map[Cell]struct{})
+	for cell, v := range vars {
+		if e.solver.modelValue(v) {
+			cells[cell] = struct{}{}
+		}
+	}
+	return cells
+}
+
+// deadlineAfter turns a time limit into a deadline for satSolver.solve; zero means none.
+func deadlineAfter(timeLimit time.Duration) time.Time {
+	if timeLimit <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeLimit)
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
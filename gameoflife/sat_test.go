@@ -0,0 +1,72 @@
+package gameoflife
+
+import (
+	"testing"
+	"time"
+)
+
+// pigeonhole returns a solver for placing pigeons into holes with at most one per hole,
+// which is unsatisfiable when there are more pigeons than holes.
+func pigeonhole(pigeons, holes int) (*satSolver, [][]int) {
+	s := newSatSolver()
+	in := make([][]int, pigeons)
+	for p := range in {
+		in[p] = make([]int, holes)
+		for h := range in[p] {
+			in[p][h] = s.newVar()
+		}
+		s.addClause(in[p]...)
+	}
+	for h := range holes {
+		for p := range pigeons {
+			for q := p + 1; q < pigeons; q++ {
+				s.addClause(-in[p][h], -in[q][h])
+			}
+		}
+	}
+	return s, in
+}
+
+func TestSatSolver_Pigeonhole(t *testing.T) {
+	s, in := pigeonhole(5, 5)
+	if got := s.solve(time.Time{}); got != satSatisfiable {
+		t.Fatalf("5 pigeons in 5 holes: solve() = %v; want satisfiable", got)
+	}
+	for h := range 5 {
+		used := 0
+		for p := range 5 {
+			if s.modelValue(in[p][h]) {
+				used++
+			}
+		}
+		if used != 1 {
+			t.Errorf("hole %d holds %d pigeons in the model; want 1", h, used)
+		}
+	}
+
+	s, _ = pigeonhole(6, 5)
+	if got := s.solve(time.Time{}); got != satUnsatisfiable {
+		t.Errorf("6 pigeons in 5 holes: solve() = %v; want unsatisfiable", got)
+	}
+}
+
+func TestSatSolver_Block(t *testing.T) {
+	// exactly one of three variables: three models
+	s := newSatSolver()
+	vars := []int{s.newVar(), s.newVar(), s.newVar()}
+	s.addClause(vars...)
+	for i := range vars {
+		for j := i + 1; j < len(vars); j++ {
+			s.addClause(-vars[i], -vars[j])
+		}
+	}
+
+	models := 0
+	for s.solve(time.Time{}) == satSatisfiable {
+		models++
+		s.block(vars)
+	}
+	if models != 3 {
+		t.Errorf("enumerated %d models; want 3", models)
+	}
+}
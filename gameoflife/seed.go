@@ -86,3 +86,19 @@ func getDefaultSeedPattern(rows, cols int) map[Cell]struct{} {
 
 	return seedGrid
 }
+
+// FillRandom replaces the universe with random cells, each alive with the given density,
+// drawn from the universe's random number generator so the same seed gives the same
+// universe. Like a seed pattern it belongs before age tracking, colours or history are
+// enabled.
+func (g *GameOfLife) FillRandom(density float64) {
+	rng := g.Rand()
+	g.universe = make(map[Cell]struct{})
+	for r := range g.numRows {
+		for c := range g.numCols {
+			if rng.Float64() < density {
+				g.universe[Cell{r, c}] = struct{}{}
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package gameoflife
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// SoupSize is the width and height of the random soups examined by RunSoupSearch.
+const SoupSize = 16
+
+// soupUniverseSize is the side of the torus soups are run in. It is large enough that
+// escaping spaceships do not wrap around into the ash within the default MaxGenerations.
+const soupUniverseSize = 1024
+
+// GenerateSoup returns the 16x16 soup for a seed string. The 256 bits of the SHA-256
+// hash of the seed decide which cells are alive, so every seed names one soup.
+func GenerateSoup(seed string) map[Cell]struct{} {
+	hash := sha256.Sum256([]byte(seed))
+	cells := make(map[Cell]struct{})
+	for i := range SoupSize * SoupSize {
+		if hash[i/8]&(1<<(7-i%8)) != 0 {
+			cells[Cell{i / SoupSize, i % SoupSize}] = struct{}{}
+		}
+	}
+	return cells
+}
+
+// SoupSearchOptions configure RunSoupSearch.
+type SoupSearchOptions struct {
+	// SeedPrefix is prepended to the soup number to form each soup's seed.
+	SeedPrefix string
+	// Soups is the total number of soups the census should cover.
+	Soups int
+	// Workers is the number of soups run in parallel, defaulting to the number of CPUs.
+	Workers int
+	// MaxGenerations is how long a soup may run before it is considered unstable.
+	MaxGenerations int
+	Rules          []Rule
+	// StatePath, if set, is where the census is saved after every batch of soups, and
+	// where an interrupted search is resumed from.
+	StatePath string
+	// BatchSize is the number of soups between saves.
+	BatchSize int
+	// Progress, if set, receives a line after every batch.
+	Progress io.Writer
+}
+
+// SoupCensusEntry is the tally of one kind of object across all soups searched.
+type SoupCensusEntry struct {
+	Apgcode string `json:"apgcode"`
+	Name    string `json:"name,omitempty"`
+	Count   int    `json:"count"`
+	// ExampleSeeds are the seeds of the first soups the object appeared in.
+	ExampleSeeds []string `json:"example_seeds"`
+}
+
+// maxExampleSeeds is the number of example seeds kept per census entry.
+const maxExampleSeeds = 3
+
+// SoupCensus is the aggregated result of a soup search.
+type SoupCensus struct {
+	SeedPrefix    string                      `json:"seed_prefix"`
+	Rules         []string                    `json:"rules"`
+	SoupsSearched int                         `json:"soups_searched"`
+	Unstabilised  []string                    `json:"unstabilised,omitempty"`
+	Objects       map[string]*SoupCensusEntry `json:"objects"`
+}
+
+// Entries returns the census entries, most common first.
+func (c *SoupCensus) Entries() []*SoupCensusEntry {
+	entries := make([]*SoupCensusEntry, 0, len(c.Objects))
+	for _, entry := range c.Objects {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *SoupCensusEntry) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		if lessCode(a.Apgcode, b.Apgcode) {
+			return -1
+		}
+		return 1
+	})
+	return entries
+}
+
+// soupResult is the outcome of running one soup.
+type soupResult struct {
+	seed       string
+	stabilised bool
+	objects    []Object
+}
+
+// RunSoupSearch runs random soups to stabilisation, splits the remaining ash into
+// objects and tallies them by apgcode. Soups run in parallel; when StatePath is set
+// the census is saved after every batch and an existing state file is resumed.
+func RunSoupSearch(opts SoupSearchOptions) (*SoupCensus, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.MaxGenerations <= 0 {
+		opts.MaxGenerations = 3000
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if len(opts.Rules) == 0 {
+		opts.Rules = []Rule{ConwayRule{}}
+	}
+
+	ruleNames := make([]string, 0, len(opts.Rules))
+	for _, rule := range opts.Rules {
+		ruleNames = append(ruleNames, RuleName(rule))
+	}
+
+	census := &SoupCensus{SeedPrefix: opts.SeedPrefix, Rules: ruleNames, Objects: make(map[string]*SoupCensusEntry)}
+	if opts.StatePath != "" {
+		saved, err := LoadSoupCensus(opts.StatePath)
+		switch {
+		case err == nil:
+			if saved.SeedPrefix != opts.SeedPrefix || !slices.Equal(saved.Rules, ruleNames) {
+				return nil, fmt.Errorf("%s holds a search with seed prefix %q and rules %v", opts.StatePath, saved.SeedPrefix, saved.Rules)
+			}
+			census = saved
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+
+	for census.SoupsSearched < opts.Soups {
+		batch := min(opts.BatchSize, opts.Soups-census.SoupsSearched)
+		results := make([]soupResult, batch)
+
+		var wg sync.WaitGroup
+		next := make(chan int)
+		for range opts.Workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					seed := opts.SeedPrefix + strconv.Itoa(census.SoupsSearched+i)
+					results[i] = runSoup(seed, opts.Rules, opts.MaxGenerations)
+				}
+			}()
+		}
+		for i := range batch {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+
+		// merge in soup order so the census does not depend on scheduling
+		for _, result := range results {
+			census.add(result)
+		}
+		census.SoupsSearched += batch
+
+		if opts.StatePath != "" {
+			if err := census.Save(opts.StatePath); err != nil {
+				return census, err
+			}
+		}
+		if opts.Progress != nil {
+			fmt.Fprintf(opts.Progress, "%d/%d soups, %d distinct objects\n", census.SoupsSearched, opts.Soups, len(census.Objects))
+		}
+	}
+	return census, nil
+}
+
+func (c *SoupCensus) add(result soupResult) {
+	if !result.stabilised {
+		c.Unstabilised = append(c.Unstabilised, result.seed)
+	}
+	for _, object := range result.objects {
+		code := object.Apgcode
+		entry, ok := c.Objects[code]
+		if !ok {
+			entry = &SoupCensusEntry{Apgcode: code, Name: object.Name}
+			c.Objects[code] = entry
+		}
+		entry.Count++
+		if len(entry.ExampleSeeds) < maxExampleSeeds && !slices.Contains(entry.ExampleSeeds, result.seed) {
+			entry.ExampleSeeds = append(entry.ExampleSeeds, result.seed)
+		}
+	}
+}
+
+// runSoup runs one soup until its population becomes periodic and takes its census.
+func runSoup(seed string, rules []Rule, maxGenerations int) soupResult {
+	g := CreateSeedUniverse(soupUniverseSize, soupUniverseSize, Default, rules...)
+	g.universe = make(map[Cell]struct{})
+	offset := (soupUniverseSize - SoupSize) / 2
+	for cell := range GenerateSoup(seed) {
+		g.universe[Cell{cell.R + offset, cell.C + offset}] = struct{}{}
+	}
+
+	result := soupResult{seed: seed}
+	populations := []int{len(g.universe)}
+	for g.generation < maxGenerations && !result.stabilised {
+		g.CreateNextGeneration()
+		populations = append(populations, len(g.universe))
+		result.stabilised = populationPeriod(populations) > 0
+	}
+
+	result.objects = g.Census().Objects
+	return result
+}
+
+// populationPeriod returns the period with which the recent population history repeats,
+// or 0 if it does not repeat with a short period. This is how the ash of a soup is
+// recognised as settled. A population that died out has period 1.
+func populationPeriod(populations []int) int {
+	const maxPeriod, repeats = 12, 6
+	n := len(populations)
+	if n > 0 && populations[n-1] == 0 {
+		return 1
+	}
+	for period := 1; period <= maxPeriod; period++ {
+		window := period * repeats
+		if n < window+period || n < 60 {
+			continue
+		}
+		periodic := true
+		for i := n - window; i < n && periodic; i++ {
+			periodic = populations[i] == populations[i-period]
+		}
+		if periodic {
+			return period
+		}
+	}
+	return 0
+}
+
+// settleTime returns the first generation from which the population history repeats with
+// the given period.
+func settleTime(populations []int, period int) int {
+	i := len(populations) - 1
+	for i-period >= 0 && populations[i-period] == populations[i] {
+		i--
+	}
+	return max(i-period+1, 0)
+}
+
+// Save atomically writes the census as JSON.
+func (c *SoupCensus) Save(path string) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	})
+}
+
+// LoadSoupCensus reads a census saved by SoupCensus.Save.
+func LoadSoupCensus(path string) (*SoupCensus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &SoupCensus{}
+	if err := json.NewDecoder(f).Decode(c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Objects == nil {
+		c.Objects = make(map[string]*SoupCensusEntry)
+	}
+	return c, nil
+}
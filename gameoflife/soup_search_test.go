@@ -0,0 +1,63 @@
+package gameoflife
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSoup(t *testing.T) {
+	a, b := GenerateSoup("seed_1"), GenerateSoup("seed_1")
+	if !maps.Equal(a, b) {
+		t.Errorf("GenerateSoup is not deterministic")
+	}
+	if maps.Equal(a, GenerateSoup("seed_2")) {
+		t.Errorf("different seeds produced the same soup")
+	}
+	for cell := range a {
+		if cell.R < 0 || cell.R >= SoupSize || cell.C < 0 || cell.C >= SoupSize {
+			t.Errorf("cell %v outside the %dx%d soup", cell, SoupSize, SoupSize)
+		}
+	}
+	if len(a) < SoupSize*SoupSize/4 || len(a) > SoupSize*SoupSize*3/4 {
+		t.Errorf("soup has %d cells; want about half of %d", len(a), SoupSize*SoupSize)
+	}
+}
+
+func TestRunSoupSearch_Resume(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "census.json")
+	opts := SoupSearchOptions{SeedPrefix: "test_", Soups: 2, Workers: 2, BatchSize: 1, StatePath: statePath}
+
+	partial, err := RunSoupSearch(opts)
+	if err != nil {
+		t.Fatalf("RunSoupSearch() error = %v", err)
+	}
+	if partial.SoupsSearched != 2 || len(partial.Objects) == 0 {
+		t.Fatalf("searched %d soups with %d objects; want 2 soups with some objects", partial.SoupsSearched, len(partial.Objects))
+	}
+
+	opts.Soups = 3
+	resumed, err := RunSoupSearch(opts)
+	if err != nil {
+		t.Fatalf("resumed RunSoupSearch() error = %v", err)
+	}
+	opts.StatePath = ""
+	fresh, err := RunSoupSearch(opts)
+	if err != nil {
+		t.Fatalf("fresh RunSoupSearch() error = %v", err)
+	}
+
+	if resumed.SoupsSearched != 3 || len(resumed.Objects) != len(fresh.Objects) {
+		t.Fatalf("resumed census covers %d soups and %d objects; want 3 and %d", resumed.SoupsSearched, len(resumed.Objects), len(fresh.Objects))
+	}
+	for code, entry := range fresh.Objects {
+		if got := resumed.Objects[code]; got == nil || got.Count != entry.Count {
+			t.Errorf("object %s: resumed census differs from a fresh one", code)
+		}
+	}
+
+	opts.StatePath, opts.SeedPrefix = statePath, "other_"
+	if _, err := RunSoupSearch(opts); err == nil {
+		t.Errorf("resuming with a different seed prefix succeeded")
+	}
+}
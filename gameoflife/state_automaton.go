@@ -0,0 +1,282 @@
+package gameoflife
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StateRule is the transition rule of a StateAutomaton, whose cells can be in any of
+// States() states rather than just dead or alive.
+type StateRule interface {
+	// Name is the rule's name in checkpoints and pattern files, as used by Golly.
+	Name() string
+	// States returns the number of states; state 0 is the background.
+	States() int
+	// Next returns the next state of a cell from its state and the states of its Moore
+	// neighbours, in the order NW, N, NE, W, E, SW, S, SE. A cell in state 0 with all
+	// neighbours in state 0 must stay in state 0.
+	Next(state int, neighbours [8]int) int
+	// Symbols has one character per state, for text seeds.
+	Symbols() string
+	// Char returns how a cell in a state is shown in the terminal.
+	Char(state int) string
+	// Seed returns the starting cells used when no seed is given.
+	Seed(rows, cols int) map[Cell]int
+}
+
+// mooreOffsets are the Moore neighbours in the order StateRule.Next receives them.
+var mooreOffsets = [8]Cell{
+	{R: -1, C: -1}, {R: -1, C: 0}, {R: -1, C: 1},
+	{R: 0, C: -1}, {R: 0, C: 1},
+	{R: 1, C: -1}, {R: 1, C: 0}, {R: 1, C: 1},
+}
+
+// Indexes of the orthogonal neighbours in mooreOffsets.
+const (
+	northNeighbour = 1
+	westNeighbour  = 3
+	eastNeighbour  = 4
+	southNeighbour = 6
+)
+
+// diagramRows is how many past generations Display shows for one-row automata.
+const diagramRows = 40
+
+// StateAutomaton is a cellular automaton on a torus whose cells have more than two states,
+// such as WireWorld. Automata with a single row are elementary one-dimensional automata
+// and are displayed as a space-time diagram, one row per generation.
+type StateAutomaton struct {
+	rule       StateRule
+	cells      map[Cell]int // cells not in state 0
+	numRows    int
+	numCols    int
+	generation int
+	past       []string // rendered past generations of one-row automata
+
+	// auto-checkpointing during Run
+	autoCheckpoint
+}
+
+// NewStateAutomaton returns a rows x cols automaton following rule, with the given cells
+// in the given states; nil cells select the rule's own seed.
+func NewStateAutomaton(rows, cols int, rule StateRule, cells map[Cell]int) (*StateAutomaton, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, fmt.Errorf("invalid universe dimensions %dx%d", rows, cols)
+	}
+	if cells == nil {
+		cells = rule.Seed(rows, cols)
+	}
+	a := &StateAutomaton{rule: rule, cells: make(map[Cell]int, len(cells)), numRows: rows, numCols: cols}
+	for cell, state := range cells {
+		if state < 0 || state >= rule.States() {
+			return nil, fmt.Errorf("state %d of cell %v is not a state of %s", state, cell, rule.Name())
+		}
+		if state != 0 {
+			a.cells[a.wrap(cell)] = state
+		}
+	}
+	return a, nil
+}
+
+// NewStateAutomatonFromPattern centres a pattern in a rows x cols automaton. Multistate
+// patterns keep their states; live cells of two-state patterns get state 1.
+func NewStateAutomatonFromPattern(rows, cols int, rule StateRule, pattern *Pattern) (*StateAutomaton, error) {
+	offset, err := pattern.placement(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	cells := make(map[Cell]int, len(pattern.Cells))
+	for cell := range pattern.Cells {
+		cells[Cell{cell.R + offset.R, cell.C + offset.C}] = pattern.state(cell)
+	}
+	return NewStateAutomaton(rows, cols, rule, cells)
+}
+
+func (a *StateAutomaton) wrap(cell Cell) Cell {
+	return Cell{(cell.R%a.numRows + a.numRows) % a.numRows, (cell.C%a.numCols + a.numCols) % a.numCols}
+}
+
+// Rule returns the automaton's rule.
+func (a *StateAutomaton) Rule() StateRule {
+	return a.rule
+}
+
+// Generation returns the number of generations computed since the seed.
+func (a *StateAutomaton) Generation() int {
+	return a.generation
+}
+
+// State returns the state of a cell.
+func (a *StateAutomaton) State(cell Cell) int {
+	return a.cells[a.wrap(cell)]
+}
+
+// Cells returns the cells not in state 0 with their states.
+func (a *StateAutomaton) Cells() map[Cell]int {
+	return maps.Clone(a.cells)
+}
+
+// Step advances the automaton by one generation. Only cells in a non-zero state and their
+// neighbours can change, so only those are computed.
+func (a *StateAutomaton) Step() {
+	candidates := make(map[Cell]struct{}, 9*len(a.cells))
+	for cell := range a.cells {
+		candidates[cell] = struct{}{}
+		for _, offset := range mooreOffsets {
+			candidates[a.wrap(Cell{cell.R + offset.R, cell.C + offset.C})] = struct{}{}
+		}
+	}
+
+	next := make(map[Cell]int, len(a.cells))
+	for cell := range candidates {
+		var neighbours [8]int
+		for i, offset := range mooreOffsets {
+			neighbours[i] = a.cells[a.wrap(Cell{cell.R + offset.R, cell.C + offset.C})]
+		}
+		if state := a.rule.Next(a.cells[cell], neighbours); state != 0 {
+			next[cell] = state
+		}
+	}
+
+	if a.numRows == 1 {
+		a.past = append(a.past, a.renderRow(0))
+		if len(a.past) > diagramRows {
+			a.past = a.past[len(a.past)-diagramRows:]
+		}
+	}
+	a.cells = next
+	a.generation++
+}
+
+// Display prints the automaton with the rule's terminal characters. One-row automata are
+// printed with their recent past generations above the current one.
+func (a *StateAutomaton) Display() {
+	fmt.Println("==============")
+	if a.numRows == 1 {
+		for _, row := range a.past {
+			fmt.Println(row)
+		}
+		fmt.Println(a.renderRow(0))
+		return
+	}
+	for r := range a.numRows {
+		fmt.Print(a.renderRow(r), "\n\n")
+	}
+}
+
+func (a *StateAutomaton) renderRow(r int) string {
+	var row strings.Builder
+	for c := range a.numCols {
+		row.WriteString(" ")
+		row.WriteString(a.rule.Char(a.cells[Cell{r, c}]))
+	}
+	return row.String()
+}
+
+// Run simulates the automaton for a number of generations like GameOfLife.Run.
+func (a *StateAutomaton) Run(generations int, delay time.Duration) {
+	runAutomaton(a, &a.autoCheckpoint, generations, delay)
+}
+
+// Pattern returns the current generation as a multistate pattern in universe coordinates.
+func (a *StateAutomaton) Pattern() *Pattern {
+	p := &Pattern{Rule: a.rule.Name(), Cells: make(map[Cell]struct{}, len(a.cells)), States: a.Cells()}
+	for cell := range a.cells {
+		p.Cells[cell] = struct{}{}
+	}
+	return p
+}
+
+// Checkpoint captures the current state of the automaton.
+func (a *StateAutomaton) Checkpoint() (*Checkpoint, error) {
+	cp := &Checkpoint{
+		Version:    CheckpointVersion,
+		Rows:       a.numRows,
+		Cols:       a.numCols,
+		Generation: a.generation,
+		Rules:      []string{a.rule.Name()},
+		Cells:      slices.SortedFunc(maps.Keys(a.cells), compareCells),
+	}
+	cp.States = make([]int, len(cp.Cells))
+	for i, cell := range cp.Cells {
+		cp.States[i] = a.cells[cell]
+	}
+	return cp, nil
+}
+
+// restoreStateAutomaton rebuilds a StateAutomaton from a checkpoint of one.
+func (cp *Checkpoint) restoreStateAutomaton() (*StateAutomaton, error) {
+	if cp.Version < 2 || cp.Version > CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d", cp.Version)
+	}
+	rule, err := ParseStateRule(cp.Rules[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(cp.States) != len(cp.Cells) {
+		return nil, fmt.Errorf("checkpoint has %d cells but %d states", len(cp.Cells), len(cp.States))
+	}
+	for _, cell := range cp.Cells {
+		if cell.R < 0 || cell.R >= cp.Rows || cell.C < 0 || cell.C >= cp.Cols {
+			return nil, fmt.Errorf("cell %v lies outside the %dx%d universe", cell, cp.Rows, cp.Cols)
+		}
+	}
+
+	cells := make(map[Cell]int, len(cp.Cells))
+	for i, cell := range cp.Cells {
+		cells[cell] = cp.States[i]
+	}
+	a, err := NewStateAutomaton(cp.Rows, cp.Cols, rule, cells)
+	if err != nil {
+		return nil, err
+	}
+	a.generation = cp.Generation
+	return a, nil
+}
+
+// WriteCheckpoint writes the current state of the automaton to w in the given format.
+func (a *StateAutomaton) WriteCheckpoint(w io.Writer, format CheckpointFormat) error {
+	cp, err := a.Checkpoint()
+	if err != nil {
+		return err
+	}
+	return cp.write(w, format)
+}
+
+// SaveCheckpoint atomically writes a checkpoint to path, like GameOfLife.SaveCheckpoint.
+func (a *StateAutomaton) SaveCheckpoint(path string, format CheckpointFormat) error {
+	return writeFileAtomic(path, func(w io.Writer) error { return a.WriteCheckpoint(w, format) })
+}
+
+// ReadStateText reads a text seed: one line per row and one of the rule's Symbols per
+// cell, with spaces also standing for state 0. Lines starting with `!` are comments, as
+// in the plaintext format.
+func ReadStateText(r io.Reader, rule StateRule) (map[Cell]int, error) {
+	symbols := rule.Symbols()
+	cells := make(map[Cell]int)
+	scanner := bufio.NewScanner(r)
+	row := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		for col, ch := range []rune(line) {
+			state := strings.IndexRune(symbols, ch)
+			switch {
+			case ch == ' ':
+			case state < 0:
+				return nil, fmt.Errorf("line %d: %q is not a %s state, want one of %q", row+1, ch, rule.Name(), symbols)
+			case state > 0:
+				cells[Cell{row, col}] = state
+			}
+		}
+		row++
+	}
+	return cells, scanner.Err()
+}
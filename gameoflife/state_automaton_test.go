@@ -0,0 +1,164 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestWireWorld_ElectronAndClock(t *testing.T) {
+	cells, err := ReadStateText(strings.NewReader("~@#####"), WireWorld{})
+	if err != nil {
+		t.Fatalf("ReadStateText() error = %v", err)
+	}
+	a, err := NewStateAutomaton(3, 10, WireWorld{}, cells)
+	if err != nil {
+		t.Fatalf("NewStateAutomaton() error = %v", err)
+	}
+	for step := 1; step <= 4; step++ {
+		a.Step()
+		if a.State(Cell{0, 1 + step}) != WireHead || a.State(Cell{0, step}) != WireTail {
+			t.Fatalf("generation %d: head not at column %d: %v", step, 1+step, a.Cells())
+		}
+	}
+
+	// the clock's loop repeats every eight generations
+	clock, _ := NewStateAutomaton(8, 16, WireWorld{}, nil)
+	loop := func() map[Cell]int {
+		states := make(map[Cell]int)
+		for cell, state := range clock.Cells() {
+			if cell.C <= 5 {
+				states[cell] = state
+			}
+		}
+		return states
+	}
+	start := loop()
+	for range 8 {
+		clock.Step()
+	}
+	if !maps.Equal(loop(), start) {
+		t.Errorf("clock loop after 8 generations = %v; want %v", loop(), start)
+	}
+}
+
+func TestLangtonsAnt_MatchesWalkingAnt(t *testing.T) {
+	const size, steps = 24, 600
+	a, _ := NewStateAutomaton(size, size, LangtonsAnt{}, nil)
+
+	// the ant walked directly, for comparison
+	black := make(map[Cell]bool)
+	ant, heading := Cell{size / 2, size / 2}, antNorth
+	moves := []Cell{{-1, 0}, {0, 1}, {1, 0}, {0, -1}}
+	for step := 1; step <= steps; step++ {
+		if black[ant] {
+			heading = (heading + 3) % 4
+		} else {
+			heading = (heading + 1) % 4
+		}
+		black[ant] = !black[ant]
+		ant = a.wrap(Cell{ant.R + moves[heading].R, ant.C + moves[heading].C})
+		a.Step()
+
+		colour, antHeading := antState(a.State(ant))
+		if antHeading != heading || (colour == 1) != black[ant] {
+			t.Fatalf("step %d: ant cell %v has state %d; want heading %d on black=%v", step, ant, a.State(ant), heading, black[ant])
+		}
+	}
+	for cell, state := range a.Cells() {
+		if colour, _ := antState(state); (colour == 1) != black[cell] {
+			t.Errorf("cell %v has colour %d after %d steps; want black=%v", cell, colour, steps, black[cell])
+		}
+	}
+}
+
+func TestElementaryRules(t *testing.T) {
+	rule90, _ := NewElementaryRule(90)
+	a, _ := NewStateAutomaton(1, 64, rule90, map[Cell]int{{0, 32}: 1})
+	// Rule 90 from a single cell draws Pascal's triangle modulo 2
+	row := make([]int, 65)
+	row[32] = 1
+	for generation := 1; generation <= 20; generation++ {
+		a.Step()
+		next := make([]int, 65)
+		for c := 1; c < 64; c++ {
+			next[c] = row[c-1] ^ row[c+1]
+		}
+		row = next
+		for c := range 64 {
+			if a.State(Cell{0, c}) != row[c] {
+				t.Fatalf("rule 90 generation %d differs at column %d", generation, c)
+			}
+		}
+	}
+
+	rule110, _ := ParseStateRule("rule110")
+	b, _ := NewStateAutomaton(1, 8, rule110, nil)
+	want := []string{"......oo", ".....ooo", "....oo.o", "...ooooo", "..oo...o"}
+	for generation, row := range want {
+		b.Step()
+		if got := stateRow(b); got != row {
+			t.Errorf("rule 110 generation %d = %s; want %s", generation+1, got, row)
+		}
+	}
+
+	if _, err := ParseStateRule("w111"); err == nil {
+		t.Errorf("ParseStateRule(w111) accepted an odd rule")
+	}
+}
+
+func stateRow(a *StateAutomaton) string {
+	var row strings.Builder
+	for c := range a.numCols {
+		row.WriteByte(a.rule.Symbols()[a.State(Cell{0, c})])
+	}
+	return row.String()
+}
+
+func TestStateAutomaton_CheckpointAndPattern(t *testing.T) {
+	a, _ := NewStateAutomaton(8, 16, WireWorld{}, nil)
+	for range 5 {
+		a.Step()
+	}
+
+	for _, format := range []CheckpointFormat{CheckpointBinary, CheckpointJSON} {
+		var buf bytes.Buffer
+		if err := a.WriteCheckpoint(&buf, format); err != nil {
+			t.Fatalf("WriteCheckpoint(%s) error = %v", format, err)
+		}
+		cp, err := ReadCheckpoint(&buf)
+		if err != nil {
+			t.Fatalf("ReadCheckpoint(%s) error = %v", format, err)
+		}
+		restored, err := cp.RestoreAutomaton()
+		if err != nil {
+			t.Fatalf("RestoreAutomaton(%s) error = %v", format, err)
+		}
+		b, ok := restored.(*StateAutomaton)
+		if !ok || b.Generation() != 5 || !maps.Equal(b.Cells(), a.Cells()) {
+			t.Fatalf("%s checkpoint restored %T at generation %d; want the WireWorld automaton at generation 5", format, restored, restored.Generation())
+		}
+	}
+
+	var rle bytes.Buffer
+	if err := WritePattern(&rle, a.Pattern(), FormatRLE); err != nil {
+		t.Fatalf("WritePattern() error = %v", err)
+	}
+	p, err := ReadPattern(&rle, FormatRLE)
+	if err != nil {
+		t.Fatalf("ReadPattern() error = %v", err)
+	}
+	if p.Rule != "WireWorld" {
+		t.Errorf("pattern rule = %q; want WireWorld", p.Rule)
+	}
+	b, err := NewStateAutomatonFromPattern(8, 16, WireWorld{}, p)
+	if err != nil {
+		t.Fatalf("NewStateAutomatonFromPattern() error = %v", err)
+	}
+	a.Step()
+	b.Step()
+	if len(b.Cells()) != len(a.Cells()) {
+		t.Errorf("automaton read back from RLE has %d cells after a step; want %d", len(b.Cells()), len(a.Cells()))
+	}
+}
@@ -0,0 +1,212 @@
+package gameoflife
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseStateRule returns the multistate rule with the given name, in any case:
+// wireworld, langtons-ant, or an elementary rule as w110 or rule110.
+func ParseStateRule(name string) (StateRule, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "wireworld":
+		return WireWorld{}, nil
+	case "langtons-ant", "langtonsant", "ant":
+		return LangtonsAnt{}, nil
+	}
+	for _, prefix := range []string{"w", "rule-", "rule"} {
+		if number, ok := strings.CutPrefix(name, prefix); ok {
+			if n, err := strconv.Atoi(number); err == nil {
+				return NewElementaryRule(n)
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown multistate rule %q, want one of %v", name, StateRuleNames())
+}
+
+// StateRuleNames returns examples of the names ParseStateRule accepts, for CLI help.
+func StateRuleNames() []string {
+	return []string{"wireworld", "langtons-ant", "w110"}
+}
+
+// WireWorld simulates electronic circuits. Its states are empty, electron head, electron
+// tail and conductor: heads become tails, tails become conductors, and conductors become
+// heads when one or two of their neighbours are heads.
+type WireWorld struct{}
+
+// States of WireWorld cells.
+const (
+	WireEmpty = iota
+	WireHead
+	WireTail
+	WireConductor
+)
+
+func (WireWorld) Name() string    { return "WireWorld" }
+func (WireWorld) States() int     { return 4 }
+func (WireWorld) Symbols() string { return ".@~#" }
+
+func (WireWorld) Next(state int, neighbours [8]int) int {
+	switch state {
+	case WireHead:
+		return WireTail
+	case WireTail:
+		return WireConductor
+	case WireConductor:
+		heads := 0
+		for _, neighbour := range neighbours {
+			if neighbour == WireHead {
+				heads++
+			}
+		}
+		if heads == 1 || heads == 2 {
+			return WireHead
+		}
+		return WireConductor
+	}
+	return WireEmpty
+}
+
+func (WireWorld) Char(state int) string {
+	switch state {
+	case WireHead:
+		return "\033[44m \033[0m"
+	case WireTail:
+		return redChar
+	case WireConductor:
+		return "\033[43m \033[0m"
+	}
+	return blackChar
+}
+
+// Seed returns a clock: an electron circling a loop of eight cells, sending an electron
+// down a wire every eight generations.
+func (WireWorld) Seed(rows, cols int) map[Cell]int {
+	r, c := rows/2-1, 1
+	cells := map[Cell]int{
+		{r, c + 1}: WireTail, {r, c + 2}: WireHead, {r, c + 3}: WireConductor,
+		{r + 1, c}: WireConductor, {r + 1, c + 4}: WireConductor,
+		{r + 2, c + 1}: WireConductor, {r + 2, c + 2}: WireConductor, {r + 2, c + 3}: WireConductor,
+	}
+	for col := c + 5; col < cols-1; col++ {
+		cells[Cell{r + 1, col}] = WireConductor
+	}
+	return cells
+}
+
+// LangtonsAnt is an ant walking on a grid of white and black cells: on a white cell it
+// turns right, on a black cell left, then it flips the colour of its cell and moves one
+// cell forward. It is written as a cellular automaton whose states combine the colour of
+// a cell with the heading of the ant on it: 0 and 1 are white and black cells without an
+// ant, 2 to 5 a white cell with an ant heading north, east, south or west, and 6 to 9 the
+// same on a black cell. When several ants would enter the same cell, the first in the
+// order north, west, east, south wins and the others vanish.
+type LangtonsAnt struct{}
+
+// Headings of Langton's ant, clockwise.
+const (
+	antNorth = iota
+	antEast
+	antSouth
+	antWest
+)
+
+func (LangtonsAnt) Name() string    { return "LangtonsAnt" }
+func (LangtonsAnt) States() int     { return 10 }
+func (LangtonsAnt) Symbols() string { return ".#^>v<NESW" }
+
+// antState splits a state into the colour of the cell (0 white, 1 black) and the heading
+// of the ant on it, -1 if there is none.
+func antState(state int) (colour, heading int) {
+	switch {
+	case state < 2:
+		return state, -1
+	case state < 6:
+		return 0, state - 2
+	default:
+		return 1, state - 6
+	}
+}
+
+func (LangtonsAnt) Next(state int, neighbours [8]int) int {
+	colour, heading := antState(state)
+	if heading >= 0 {
+		colour = 1 - colour
+	}
+
+	// an ant arrives if, after turning, it faces this cell
+	for _, from := range []struct{ neighbour, heading int }{
+		{northNeighbour, antSouth}, {westNeighbour, antEast}, {eastNeighbour, antWest}, {southNeighbour, antNorth},
+	} {
+		neighbourColour, neighbourHeading := antState(neighbours[from.neighbour])
+		if neighbourHeading < 0 {
+			continue
+		}
+		turned := (neighbourHeading + 1) % 4 // right on white
+		if neighbourColour == 1 {
+			turned = (neighbourHeading + 3) % 4 // left on black
+		}
+		if turned == from.heading {
+			return 2 + 4*colour + turned
+		}
+	}
+	return colour
+}
+
+func (LangtonsAnt) Char(state int) string {
+	colour, heading := antState(state)
+	switch {
+	case heading >= 0:
+		return redChar
+	case colour == 1:
+		return whiteChar
+	}
+	return blackChar
+}
+
+// Seed returns a single ant in the middle heading north.
+func (LangtonsAnt) Seed(rows, cols int) map[Cell]int {
+	return map[Cell]int{{rows / 2, cols / 2}: 2 + antNorth}
+}
+
+// ElementaryRule is one of Wolfram's elementary one-dimensional automata, such as Rule 110:
+// bit l*4+c*2+r of Number is the next state of a cell in state c with left neighbour l and
+// right neighbour r. It is run in a universe with a single row.
+type ElementaryRule struct {
+	Number int
+}
+
+// NewElementaryRule returns the elementary rule with the given number. Odd numbers are
+// rejected: they turn on every empty cell at once.
+func NewElementaryRule(number int) (ElementaryRule, error) {
+	if number < 0 || number > 255 {
+		return ElementaryRule{}, fmt.Errorf("elementary rule %d: want a number from 0 to 255", number)
+	}
+	if number%2 == 1 {
+		return ElementaryRule{}, fmt.Errorf("elementary rule %d: odd rules turn on empty cells and are not supported", number)
+	}
+	return ElementaryRule{Number: number}, nil
+}
+
+func (r ElementaryRule) Name() string  { return "W" + strconv.Itoa(r.Number) }
+func (ElementaryRule) States() int     { return 2 }
+func (ElementaryRule) Symbols() string { return ".o" }
+
+func (r ElementaryRule) Next(state int, neighbours [8]int) int {
+	index := neighbours[westNeighbour]<<2 | state<<1 | neighbours[eastNeighbour]
+	return r.Number >> index & 1
+}
+
+func (ElementaryRule) Char(state int) string {
+	if state == 1 {
+		return whiteChar
+	}
+	return blackChar
+}
+
+// Seed returns a single live cell at the right end of the row, where Rule 110 grows from.
+func (ElementaryRule) Seed(rows, cols int) map[Cell]int {
+	return map[Cell]int{{0, cols - 1}: 1}
+}
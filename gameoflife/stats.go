@@ -0,0 +1,199 @@
+package gameoflife
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Stats are the numbers describing a single generation.
+// The bounding box and centre of mass are in universe coordinates and ignore wrap-around.
+type Stats struct {
+	Generation int     `json:"generation"`
+	Population int     `json:"population"`
+	Births     int     `json:"births"`
+	Deaths     int     `json:"deaths"`
+	MinRow     int     `json:"min_row"`
+	MinCol     int     `json:"min_col"`
+	MaxRow     int     `json:"max_row"`
+	MaxCol     int     `json:"max_col"`
+	Density    float64 `json:"density"`
+	CentreRow  float64 `json:"centre_row"`
+	CentreCol  float64 `json:"centre_col"`
+	Components int     `json:"components"`
+	// Colours are the populations of each colour under a colour scheme, colour 1 first.
+	Colours []int `json:"colours,omitempty"`
+}
+
+var statsColumns = []string{
+	"generation", "population", "births", "deaths",
+	"min_row", "min_col", "max_row", "max_col",
+	"density", "centre_row", "centre_col", "components",
+}
+
+func (s Stats) csvRecord() []string {
+	record := []string{
+		strconv.Itoa(s.Generation), strconv.Itoa(s.Population), strconv.Itoa(s.Births), strconv.Itoa(s.Deaths),
+		strconv.Itoa(s.MinRow), strconv.Itoa(s.MinCol), strconv.Itoa(s.MaxRow), strconv.Itoa(s.MaxCol),
+		strconv.FormatFloat(s.Density, 'g', 6, 64),
+		strconv.FormatFloat(s.CentreRow, 'f', 3, 64), strconv.FormatFloat(s.CentreCol, 'f', 3, 64),
+		strconv.Itoa(s.Components),
+	}
+	for _, population := range s.Colours {
+		record = append(record, strconv.Itoa(population))
+	}
+	return record
+}
+
+// columns returns the CSV header, with a colour_N column per colour.
+func (s Stats) columns() []string {
+	columns := slices.Clone(statsColumns)
+	for i := range s.Colours {
+		columns = append(columns, "colour_"+strconv.Itoa(i+1))
+	}
+	return columns
+}
+
+// Stats computes the statistics of the current generation. Births and deaths are
+// those of the step that produced it.
+func (g *GameOfLife) Stats() Stats {
+	s := Stats{
+		Generation: g.generation,
+		Population: len(g.universe),
+		Births:     g.lastBirths,
+		Deaths:     g.lastDeaths,
+		Components: len(g.components(g.neighbouringCells)),
+	}
+	if g.colours != nil {
+		s.Colours = g.ColourPopulations()
+	}
+	if g.numRows > 0 && g.numCols > 0 {
+		s.Density = float64(s.Population) / float64(g.numRows*g.numCols)
+	}
+
+	minCell, height, width := boundingBox(g.universe)
+	if s.Population > 0 {
+		s.MinRow, s.MinCol = minCell.R, minCell.C
+		s.MaxRow, s.MaxCol = minCell.R+height-1, minCell.C+width-1
+	}
+
+	sumR, sumC := 0, 0
+	for cell := range g.universe {
+		sumR += cell.R
+		sumC += cell.C
+	}
+	if s.Population > 0 {
+		s.CentreRow = float64(sumR) / float64(s.Population)
+		s.CentreCol = float64(sumC) / float64(s.Population)
+	}
+	return s
+}
+
+// components splits the live cells into groups connected through the given
+// neighbourhood, across the universe borders its topology joins.
+func (g *GameOfLife) components(neighbourhood []Cell) [][]Cell {
+	seen := make(map[Cell]struct{}, len(g.universe))
+	var components [][]Cell
+
+	for start := range g.universe {
+		if _, ok := seen[start]; ok {
+			continue
+		}
+		seen[start] = struct{}{}
+		component := []Cell{start}
+		for i := 0; i < len(component); i++ {
+			for _, offset := range neighbourhood {
+				neighbour, ok := g.neighbour(component[i], offset)
+				if _, alive := g.universe[neighbour]; !ok || !alive {
+					continue
+				}
+				if _, ok := seen[neighbour]; ok {
+					continue
+				}
+				seen[neighbour] = struct{}{}
+				component = append(component, neighbour)
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// StatsFormat selects how a StatsRecorder writes statistics.
+type StatsFormat int
+
+const (
+	// StatsCSV writes a header line followed by one comma separated line per generation.
+	StatsCSV StatsFormat = iota
+	// StatsJSONLines writes one JSON object per line and generation.
+	StatsJSONLines
+)
+
+// StatsFormatFromPath picks the stats format from a file extension: `.jsonl`, `.ndjson`
+// and `.json` select JSON Lines, everything else CSV.
+func StatsFormatFromPath(path string) StatsFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson", ".json":
+		return StatsJSONLines
+	default:
+		return StatsCSV
+	}
+}
+
+// StatsRecorder writes a time series of Stats.
+type StatsRecorder struct {
+	format      StatsFormat
+	csv         *csv.Writer
+	json        *json.Encoder
+	wroteHeader bool
+}
+
+// NewStatsRecorder returns a recorder writing to w in the given format.
+func NewStatsRecorder(w io.Writer, format StatsFormat) *StatsRecorder {
+	r := &StatsRecorder{format: format}
+	if format == StatsJSONLines {
+		r.json = json.NewEncoder(w)
+	} else {
+		r.csv = csv.NewWriter(w)
+	}
+	return r
+}
+
+// Record writes the statistics of one generation.
+func (r *StatsRecorder) Record(s Stats) error {
+	if r.format == StatsJSONLines {
+		return r.json.Encode(s)
+	}
+
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		if err := r.csv.Write(s.columns()); err != nil {
+			return err
+		}
+	}
+	return r.csv.Write(s.csvRecord())
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (r *StatsRecorder) Flush() error {
+	if r.csv != nil {
+		r.csv.Flush()
+		return r.csv.Error()
+	}
+	return nil
+}
+
+// RecordStats makes every new generation write its statistics to the recorder,
+// starting with the current one.
+func (g *GameOfLife) RecordStats(r *StatsRecorder) error {
+	g.statsRecorder = r
+	if err := r.Record(g.Stats()); err != nil {
+		return fmt.Errorf("recording stats: %w", err)
+	}
+	return nil
+}
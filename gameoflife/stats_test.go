@@ -0,0 +1,80 @@
+package gameoflife
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStats_Blinker(t *testing.T) {
+	g := CreateSeedUniverse(5, 5, Default, RuleFactory(ConwayRuleType))
+	g.CreateNextGeneration()
+
+	got := g.Stats()
+	want := Stats{
+		Generation: 1,
+		Population: 3,
+		Births:     2,
+		Deaths:     2,
+		MinRow:     2, MinCol: 1, MaxRow: 2, MaxCol: 3,
+		Density:    3.0 / 25,
+		CentreRow:  2,
+		CentreCol:  2,
+		Components: 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats() = %+v; want %+v", got, want)
+	}
+}
+
+func TestStats_Components(t *testing.T) {
+	g := CreateSeedUniverse(10, 10, Default, RuleFactory(ConwayRuleType))
+	g.universe = map[Cell]struct{}{
+		{0, 0}: {}, {1, 1}: {}, // diagonal neighbours
+		{5, 5}: {},
+		{9, 9}: {}, // touches {0, 0} across the wrapped corner
+	}
+	if got := g.Stats().Components; got != 2 {
+		t.Errorf("Components = %d; want 2", got)
+	}
+}
+
+func TestStatsRecorder_Formats(t *testing.T) {
+	g := CreateSeedUniverse(8, 8, Glider, RuleFactory(ConwayRuleType))
+
+	var csvOut, jsonOut bytes.Buffer
+	csvRecorder := NewStatsRecorder(&csvOut, StatsCSV)
+	if err := g.RecordStats(csvRecorder); err != nil {
+		t.Fatalf("RecordStats() error = %v", err)
+	}
+	g.CreateNextGeneration()
+	g.CreateNextGeneration()
+	if err := csvRecorder.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d csv lines, want header and 3 generations:\n%s", len(lines), csvOut.String())
+	}
+	if !strings.HasPrefix(lines[0], "generation,population,births,deaths") {
+		t.Errorf("unexpected csv header %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[3], "2,5,") {
+		t.Errorf("unexpected csv line for generation 2: %q", lines[3])
+	}
+
+	jsonRecorder := NewStatsRecorder(&jsonOut, StatsJSONLines)
+	if err := jsonRecorder.Record(g.Stats()); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	var decoded Stats
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", jsonOut.String(), err)
+	}
+	if !reflect.DeepEqual(decoded, g.Stats()) {
+		t.Errorf("decoded %+v; want %+v", decoded, g.Stats())
+	}
+}
@@ -0,0 +1,179 @@
+package gameoflife
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// Stop says when a run ends before its number of generations is up. The zero value never
+// stops a run.
+type Stop struct {
+	// Extinct stops the run once every cell is dead.
+	Extinct bool `json:"extinct,omitempty"`
+	// Stable stops the run once a generation is the same as the one before.
+	Stable bool `json:"stable,omitempty"`
+	// Period stops the run once the universe repeats a generation at most this many
+	// generations old, so oscillators of up to this period end it.
+	Period int `json:"period,omitempty"`
+	// MaxPopulation stops the run once more than this many cells are alive.
+	MaxPopulation int `json:"max_population,omitempty"`
+}
+
+// ParseStop parses stop conditions written as a comma-separated list of extinct, stable,
+// period=N and max-population=N, e.g. "extinct,period=12".
+func ParseStop(s string) (Stop, error) {
+	var stop Stop
+	for _, field := range strings.Split(s, ",") {
+		name, value, hasValue := strings.Cut(strings.ToLower(strings.TrimSpace(field)), "=")
+		var n int
+		if hasValue {
+			var err error
+			if n, err = strconv.Atoi(value); err != nil || n <= 0 {
+				return stop, fmt.Errorf("stop condition %q: want a positive number after =", field)
+			}
+		}
+		switch {
+		case name == "":
+		case name == "extinct" && !hasValue:
+			stop.Extinct = true
+		case name == "stable" && !hasValue:
+			stop.Stable = true
+		case name == "period" && hasValue:
+			stop.Period = n
+		case name == "max-population" && hasValue:
+			stop.MaxPopulation = n
+		default:
+			return stop, fmt.Errorf("unknown stop condition %q, want extinct, stable, period=N or max-population=N", field)
+		}
+	}
+	return stop, nil
+}
+
+// String returns the conditions in the form ParseStop reads.
+func (s Stop) String() string {
+	var fields []string
+	if s.Extinct {
+		fields = append(fields, "extinct")
+	}
+	if s.Stable {
+		fields = append(fields, "stable")
+	}
+	if s.Period > 0 {
+		fields = append(fields, "period="+strconv.Itoa(s.Period))
+	}
+	if s.MaxPopulation > 0 {
+		fields = append(fields, "max-population="+strconv.Itoa(s.MaxPopulation))
+	}
+	return strings.Join(fields, ",")
+}
+
+// SetStop makes the universe watch for the stop conditions as it evolves; StopReason
+// reports when one is met and Run ends there. The zero Stop stops watching.
+func (g *GameOfLife) SetStop(stop Stop) {
+	if stop == (Stop{}) {
+		g.stop = nil
+		return
+	}
+	g.stop = &stopWatcher{stop: stop}
+	g.stop.restart(g)
+}
+
+// StopReason returns why the run should stop, or an empty string while no stop condition
+// set with SetStop is met.
+func (g *GameOfLife) StopReason() string {
+	if g.stop == nil {
+		return ""
+	}
+	return g.stop.reason
+}
+
+// stopWatcher checks the stop conditions after every generation. It remembers as many
+// recent generations as the longest period it looks for.
+type stopWatcher struct {
+	stop   Stop
+	recent []seenGeneration // the latest generations, newest last
+	reason string
+	// outcome and period are what the universe was found to settle into
+	outcome Outcome
+	period  int
+}
+
+// seenGeneration is a generation the stop watcher remembers. The hash finds candidate
+// repeats quickly; the cells, a copy of the universe that engines stepping in place
+// cannot change, confirm them.
+type seenGeneration struct {
+	hash  uint64
+	cells map[Cell]struct{}
+}
+
+// restart forgets the generations seen so far, for when the universe was replaced rather
+// than evolved, and checks the current one.
+func (w *stopWatcher) restart(g *GameOfLife) {
+	w.recent = w.recent[:0]
+	w.reason = ""
+	w.outcome, w.period = OutcomeRunning, 0
+	w.observe(g)
+}
+
+// observe checks the conditions against the current generation.
+func (w *stopWatcher) observe(g *GameOfLife) {
+	population := len(g.universe)
+	hash := universeHash(g.universe)
+	window := w.stop.Period
+	if w.stop.Stable {
+		window = max(window, 1)
+	}
+
+	switch {
+	case w.stop.Extinct && population == 0:
+		w.reason = "extinct"
+		w.outcome = OutcomeExtinct
+	case w.stop.MaxPopulation > 0 && population > w.stop.MaxPopulation:
+		w.reason = fmt.Sprintf("population %d exceeds %d", population, w.stop.MaxPopulation)
+	default:
+		for period := 1; period <= min(window, len(w.recent)); period++ {
+			seen := w.recent[len(w.recent)-period]
+			if seen.hash != hash || !maps.Equal(seen.cells, g.universe) {
+				continue
+			}
+			if period == 1 {
+				w.reason = "stable"
+				w.outcome, w.period = OutcomeStable, 1
+			} else if period <= w.stop.Period {
+				w.reason = fmt.Sprintf("oscillating with period %d", period)
+				w.outcome, w.period = OutcomeOscillating, period
+			}
+			break
+		}
+	}
+
+	if window > 0 {
+		if len(w.recent) == window {
+			w.recent = append(w.recent[:0], w.recent[1:]...)
+		}
+		w.recent = append(w.recent, seenGeneration{hash, maps.Clone(g.universe)})
+	}
+}
+
+// universeHash hashes a set of live cells independently of map order.
+func universeHash(universe map[Cell]struct{}) uint64 {
+	hash := uint64(len(universe))
+	for cell := range universe {
+		hash += mixCell(cell)
+	}
+	return hash
+}
+
+// mixCell scrambles a cell's coordinates with the splitmix64 finaliser, so that sums of
+// mixed cells rarely collide.
+func mixCell(cell Cell) uint64 {
+	x := uint64(uint32(cell.R))<<32 | uint64(uint32(cell.C))
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,81 @@
+package gameoflife
+
+import "testing"
+
+func TestParseStop(t *testing.T) {
+	stop, err := ParseStop("extinct, Period=12,max-population=500")
+	want := Stop{Extinct: true, Period: 12, MaxPopulation: 500}
+	if err != nil || stop != want {
+		t.Fatalf("ParseStop = %+v, %v; want %+v", stop, err, want)
+	}
+	if again, err := ParseStop(stop.String()); err != nil || again != stop {
+		t.Errorf("ParseStop(%q) = %+v, %v; want %+v", stop.String(), again, err, stop)
+	}
+	for _, s := range []string{"stable=2", "period", "period=0", "max-population=-1", "forever"} {
+		if _, err := ParseStop(s); err == nil {
+			t.Errorf("ParseStop(%q) succeeded; want an error", s)
+		}
+	}
+}
+
+func TestStopReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		universe map[Cell]struct{}
+		stop     Stop
+		steps    int
+		want     string
+	}{
+		{"lone cell dies", map[Cell]struct{}{{2, 2}: {}}, Stop{Extinct: true}, 1, "extinct"},
+		{"block is stable", map[Cell]struct{}{{1, 1}: {}, {1, 2}: {}, {2, 1}: {}, {2, 2}: {}}, Stop{Stable: true}, 1, "stable"},
+		{"blinker oscillates", map[Cell]struct{}{{3, 2}: {}, {3, 3}: {}, {3, 4}: {}}, Stop{Period: 3}, 2, "oscillating with period 2"},
+		{"blinker is not stable", map[Cell]struct{}{{3, 2}: {}, {3, 3}: {}, {3, 4}: {}}, Stop{Stable: true}, 4, ""},
+		{"population limit", map[Cell]struct{}{{3, 2}: {}, {3, 3}: {}, {3, 4}: {}}, Stop{MaxPopulation: 2}, 0, "population 3 exceeds 2"},
+	}
+	for _, tt := range tests {
+		g := CreateSeedUniverse(8, 8, Default, ConwayRule{})
+		g.universe = tt.universe
+		g.SetStop(tt.stop)
+		for range tt.steps {
+			g.CreateNextGeneration()
+		}
+		if got := g.StopReason(); got != tt.want {
+			t.Errorf("%s: StopReason() = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStopReason_ConfirmsRepeatedHash(t *testing.T) {
+	g := CreateSeedUniverse(8, 8, Default, ConwayRule{})
+	g.universe = map[Cell]struct{}{{1, 1}: {}, {1, 2}: {}, {2, 1}: {}, {2, 2}: {}}
+	g.SetStop(Stop{Stable: true})
+	// another generation whose hash happens to be the block's is not a repeat of it
+	g.stop.recent[0].cells = map[Cell]struct{}{{5, 5}: {}}
+
+	g.CreateNextGeneration()
+	if got := g.StopReason(); got != "" {
+		t.Errorf("StopReason() after a hash collision = %q; want none", got)
+	}
+	g.CreateNextGeneration()
+	if got := g.StopReason(); got != "stable" {
+		t.Errorf("StopReason() = %q; want stable", got)
+	}
+}
+
+func TestStopReason_KeepsGenerationsSteppedInPlace(t *testing.T) {
+	// the tiled engine changes the universe's map in place, which must not change the
+	// generations the stop watcher remembers
+	g := CreateSeedUniverse(32, 32, Default, ConwayRule{})
+	g.SetRandomSeed(5)
+	g.FillRandom(0.35)
+	g.SetEngine(EngineTiled)
+	g.SetStop(Stop{Period: 4})
+	for range 8 {
+		g.CreateNextGeneration()
+	}
+	for i, seen := range g.stop.recent {
+		if universeHash(seen.cells) != seen.hash {
+			t.Errorf("remembered generation %d changed after it was seen", g.Generation()-len(g.stop.recent)+1+i)
+		}
+	}
+}
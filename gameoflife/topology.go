@@ -0,0 +1,95 @@
+package gameoflife
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Topology is how the edges of a universe are joined.
+type Topology int
+
+const (
+	// TopologyTorus wraps both pairs of opposite edges, so patterns leaving at the bottom
+	// right reappear at the top left. It is the default.
+	TopologyTorus Topology = iota
+	// TopologyPlane does not join the edges: cells beyond them are always dead.
+	TopologyPlane
+	// TopologyCylinder wraps the left and right edges; cells above and below are dead.
+	TopologyCylinder
+	// TopologyKlein wraps like a torus, but columns are mirrored when crossing the top or
+	// bottom edge, making a Klein bottle.
+	TopologyKlein
+)
+
+// topologyNames are the names of the topologies, as used on the command line and in
+// checkpoints.
+var topologyNames = map[Topology]string{
+	TopologyTorus:    "torus",
+	TopologyPlane:    "plane",
+	TopologyCylinder: "cylinder",
+	TopologyKlein:    "klein",
+}
+
+func (t Topology) String() string {
+	if name, ok := topologyNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Topology(%d)", int(t))
+}
+
+// Topologies returns every topology, the default first.
+func Topologies() []Topology {
+	return []Topology{TopologyTorus, TopologyPlane, TopologyCylinder, TopologyKlein}
+}
+
+// ParseTopology returns the topology with the given name: torus, plane, cylinder or klein.
+// An empty name is the torus.
+func ParseTopology(name string) (Topology, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return TopologyTorus, nil
+	}
+	for _, t := range Topologies() {
+		if topologyNames[t] == name {
+			return t, nil
+		}
+	}
+	return TopologyTorus, fmt.Errorf("unknown topology %q, want one of torus, plane, cylinder, klein", name)
+}
+
+// wrap maps a cell at most one universe away from the grid onto the grid. It returns false
+// for cells beyond an edge that is not joined.
+func (t Topology) wrap(cell Cell, rows, cols int) (Cell, bool) {
+	rowOutside := cell.R < 0 || cell.R >= rows
+	colOutside := cell.C < 0 || cell.C >= cols
+	switch t {
+	case TopologyPlane:
+		return cell, !rowOutside && !colOutside
+	case TopologyCylinder:
+		return Cell{cell.R, (cell.C + cols) % cols}, !rowOutside
+	case TopologyKlein:
+		wrapped := Cell{(cell.R + rows) % rows, (cell.C + cols) % cols}
+		if rowOutside {
+			wrapped.C = cols - 1 - wrapped.C
+		}
+		return wrapped, true
+	default:
+		return Cell{(cell.R + rows) % rows, (cell.C + cols) % cols}, true
+	}
+}
+
+// Topology returns how the edges of the universe are joined.
+func (g *GameOfLife) Topology() Topology {
+	return g.topology
+}
+
+// SetTopology changes how the edges of the universe are joined from the next generation on.
+func (g *GameOfLife) SetTopology(t Topology) {
+	g.topology = t
+}
+
+// neighbour returns the cell at offset from cell, following the universe's topology, and
+// false if it lies beyond an edge that is not joined.
+func (g *GameOfLife) neighbour(cell, offset Cell) (Cell, bool) {
+	return g.topology.wrap(Cell{cell.R + offset.R, cell.C + offset.C}, g.numRows, g.numCols)
+}
@@ -0,0 +1,97 @@
+package gameoflife
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+)
+
+func TestTopology_Wrap(t *testing.T) {
+	tests := []struct {
+		topology Topology
+		cell     Cell
+		want     Cell
+		inside   bool
+	}{
+		{TopologyTorus, Cell{-1, 5}, Cell{4, 5}, true},
+		{TopologyTorus, Cell{2, 8}, Cell{2, 0}, true},
+		{TopologyPlane, Cell{-1, 5}, Cell{-1, 5}, false},
+		{TopologyPlane, Cell{4, 7}, Cell{4, 7}, true},
+		{TopologyCylinder, Cell{2, -1}, Cell{2, 7}, true},
+		{TopologyCylinder, Cell{5, 3}, Cell{5, 3}, false},
+		{TopologyKlein, Cell{2, -1}, Cell{2, 7}, true},
+		{TopologyKlein, Cell{-1, 1}, Cell{4, 6}, true},
+		{TopologyKlein, Cell{5, 8}, Cell{0, 7}, true},
+	}
+	for _, tt := range tests {
+		got, inside := tt.topology.wrap(tt.cell, 5, 8)
+		if inside != tt.inside || inside && got != tt.want {
+			t.Errorf("%s.wrap(%v) = %v, %v; want %v, %v", tt.topology, tt.cell, got, inside, tt.want, tt.inside)
+		}
+	}
+
+	for _, topology := range Topologies() {
+		if parsed, err := ParseTopology(topology.String()); err != nil || parsed != topology {
+			t.Errorf("ParseTopology(%q) = %v, %v", topology, parsed, err)
+		}
+	}
+	if _, err := ParseTopology("sphere"); err == nil {
+		t.Errorf("ParseTopology(sphere) succeeded; want an error")
+	}
+}
+
+func TestCreateNextGeneration_Topologies(t *testing.T) {
+	// a blinker along the top edge: its vertical phase reaches over the edge
+	tests := []struct {
+		topology Topology
+		want     map[Cell]struct{}
+	}{
+		{TopologyTorus, map[Cell]struct{}{{4, 2}: {}, {0, 2}: {}, {1, 2}: {}}},
+		{TopologyPlane, map[Cell]struct{}{{0, 2}: {}, {1, 2}: {}}},
+		{TopologyCylinder, map[Cell]struct{}{{0, 2}: {}, {1, 2}: {}}},
+		{TopologyKlein, map[Cell]struct{}{{4, 2}: {}, {0, 2}: {}, {1, 2}: {}}},
+	}
+	for _, tt := range tests {
+		g := CreateSeedUniverse(5, 5, Default, ConwayRule{})
+		g.universe = map[Cell]struct{}{{0, 1}: {}, {0, 2}: {}, {0, 3}: {}}
+		g.SetTopology(tt.topology)
+		g.CreateNextGeneration()
+		if !maps.Equal(g.universe, tt.want) {
+			t.Errorf("%s: next generation = %v; want %v", tt.topology, g.universe, tt.want)
+		}
+	}
+
+	// on a Klein bottle a glider leaving at the bottom comes back mirrored at the top
+	g := CreateSeedUniverse(8, 8, Default, ConwayRule{})
+	g.SetTopology(TopologyKlein)
+	g.universe = map[Cell]struct{}{{5, 2}: {}, {6, 3}: {}, {7, 1}: {}, {7, 2}: {}, {7, 3}: {}}
+	for range 4 {
+		g.CreateNextGeneration()
+	}
+	want := map[Cell]struct{}{{6, 3}: {}, {7, 4}: {}, {0, 5}: {}, {0, 4}: {}, {0, 3}: {}}
+	if !maps.Equal(g.universe, want) {
+		t.Errorf("glider on a Klein bottle after 4 generations = %v; want %v", g.universe, want)
+	}
+}
+
+func TestCheckpoint_KeepsTopology(t *testing.T) {
+	for _, format := range []CheckpointFormat{CheckpointBinary, CheckpointJSON} {
+		g := CreateSeedUniverse(6, 6, Glider, ConwayRule{})
+		g.SetTopology(TopologyCylinder)
+		var buf bytes.Buffer
+		if err := g.WriteCheckpoint(&buf, format); err != nil {
+			t.Fatalf("WriteCheckpoint(%s) error = %v", format, err)
+		}
+		cp, err := ReadCheckpoint(&buf)
+		if err != nil {
+			t.Fatalf("ReadCheckpoint(%s) error = %v", format, err)
+		}
+		restored, err := cp.Restore()
+		if err != nil {
+			t.Fatalf("Restore(%s) error = %v", format, err)
+		}
+		if restored.Topology() != TopologyCylinder {
+			t.Errorf("%s checkpoint restored topology %s; want cylinder", format, restored.Topology())
+		}
+	}
+}
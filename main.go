@@ -1,47 +1,1170 @@
 package main
 
 import (
-	"flag"
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dilipvaidya/game-of-life/gameoflife"
 )
 
-// main function initializes the Game of Life universe based on user input flags
+// main dispatches to the command named by the first argument. Without one, or when the
+// first argument is a flag, it runs `run` so that command lines from before commands
+// existed keep working.
 func main() {
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		run(os.Args[1:])
+		return
+	}
 
-	// Custom usage function
-	flag.Usage = func() {
-		fmt.Printf("Usage of %s:\n", os.Args[0])
-		fmt.Println("This is a custom usage message.")
-		flag.PrintDefaults() // Prints default flag usage
-	}
-
-	// defining flags to accept user input
-	seedPatternStr := flag.String("seed", gameoflife.Default.String(), "Seed pattern for the universe (default, glider)")
-	rows := flag.Int("rows", 5, "Number of rows in the universe")
-	cols := flag.Int("cols", 5, "Number of columns in the universe")
-	ruleNames := flag.String("rules", "conway", fmt.Sprintf("Comma-separated rule names (e.g., conway,no-top-left). Available: %v", gameoflife.AvailableRuleNames()))
-	numberOfRuns := flag.Int("runs", 25, "Number of runs to execute")
-
-	// Parse the command line flags
-	flag.Parse()
-
-	// Validate seed pattern
-	var seedPattern gameoflife.SEED_PATTERN
-	switch *seedPatternStr {
-	case gameoflife.Default.String():
-		seedPattern = gameoflife.Default
-	case gameoflife.Glider.String():
-		seedPattern = gameoflife.Glider
-	default:
-		seedPattern = gameoflife.Default
+	name := os.Args[1]
+	if name == "help" {
+		if len(os.Args) > 2 {
+			newFlagSet(os.Args[2]).Usage()
+		} else {
+			usage()
+		}
+		return
+	}
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			cmd.run(os.Args[2:])
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// run animates a universe in the terminal, or steps through it interactively.
+func run(args []string) {
+	fs := newFlagSet("run")
+	universe := addUniverseFlags(fs, 5, 5)
+	fs.Lookup("rules").Usage = fmt.Sprintf("Comma-separated rule names or B/S rulestrings (e.g., conway,no-top-left or B36/S23), a multistate rule (%v) or a Margolus block rule (critters, bbm, tron, MS,D...). Available: %v", gameoflife.StateRuleNames(), gameoflife.AvailableRuleNames())
+	numberOfRuns := fs.Int("runs", 25, "Number of runs to execute")
+	delay := fs.Duration("delay", 500*time.Millisecond, "Pause between generations")
+	checkpointPath := fs.String("checkpoint", "", "File to write checkpoints to")
+	checkpointEvery := fs.Int("checkpoint-every", 10, "Write a checkpoint every N generations (requires -checkpoint)")
+	checkpointFormat := fs.String("checkpoint-format", "binary", "Checkpoint format (binary, json)")
+	savePath := fs.String("save", "", "Write the final universe to this pattern file (format from extension, default RLE)")
+	statsPath := fs.String("stats", "", "Write per-generation statistics to this file (.csv, or .jsonl for JSON Lines)")
+	find := fs.String("find", "", "Highlight every isolated occurrence of this pattern (catalogue name, apgcode or pattern file), in any phase and orientation")
+	printCensus := fs.Bool("census", false, "Print the objects found in the final universe")
+	interactiveMode := fs.Bool("interactive", false, "Step through generations interactively, with rewind")
+	historyKeyframe := fs.Int("history-keyframe", 16, "Interactive mode: store a full snapshot every N generations")
+	historyBudget := fs.Int("history-budget", 64<<20, "Interactive mode: memory budget for history in bytes")
+	ageColours := fs.Bool("age-colours", false, "Colour live cells by age, from white for newborn to dark red for old")
+	maxAge := fs.Int("max-age", 0, "Cells die after being alive for this many generations; 0 for no limit")
+	heatmapPath := fs.String("heatmap", "", "Write a long-exposure PNG of how often each cell was alive to this file")
+	heatmapScale := fs.Int("heatmap-scale", 8, "Size in pixels of each cell in the heat map")
+	colourScheme := fs.String("colours", "", "Colour-inheriting variant: immigration (2 colours) or quadlife (4 colours)")
+	colourSeed := fs.String("colour-seed", "random", "How the starting cells are coloured with -colours: random, or regions (one half or quadrant per colour)")
+	universe.addStopFlag()
+	fs.Parse(args)
+
+	cfg := universe.mustResolve()
+	if universe.applies("runs") {
+		cfg.Generations = *numberOfRuns
+	}
+	// output flags replace the config's outputs of the same kind
+	flagSinks := []struct {
+		path string
+		sink gameoflife.OutputSink
+	}{
+		{*savePath, gameoflife.OutputSink{Kind: "pattern", Path: *savePath}},
+		{*statsPath, gameoflife.OutputSink{Kind: "stats", Path: *statsPath}},
+		{*checkpointPath, gameoflife.OutputSink{Kind: "checkpoint", Path: *checkpointPath, Format: *checkpointFormat, Every: *checkpointEvery}},
+		{*heatmapPath, gameoflife.OutputSink{Kind: "heatmap", Path: *heatmapPath, Scale: *heatmapScale}},
+	}
+	for _, flagSink := range flagSinks {
+		if flagSink.path != "" {
+			cfg.Outputs = slices.DeleteFunc(cfg.Outputs, func(sink gameoflife.OutputSink) bool { return sink.Kind == flagSink.sink.Kind })
+			cfg.Outputs = append(cfg.Outputs, flagSink.sink)
+		}
+	}
+	universe.dump(cfg)
+
+	// multistate and block automata share the run loop, checkpointing and saving with Life
+	var automaton gameoflife.Automaton
+	var err error
+	patternPath := ""
+	if len(cfg.Patterns) > 0 {
+		patternPath = cfg.Patterns[0].Pattern
+	}
+	if rule, ruleErr := gameoflife.ParseStateRule(cfg.Rules); ruleErr == nil {
+		automaton, err = newStateAutomaton(rule, cfg.Rows, cfg.Cols, patternPath)
+	} else if rule, ruleErr := gameoflife.ParseBlockRule(cfg.Rules); ruleErr == nil {
+		seed := gameoflife.Default
+		if cfg.Seed == gameoflife.Glider.String() {
+			seed = gameoflife.Glider
+		}
+		automaton, err = newBlockAutomaton(rule, cfg.Rows, cfg.Cols, patternPath, seed)
+	}
+	if err == nil && automaton != nil && cfg.Topology != gameoflife.TopologyTorus.String() {
+		err = fmt.Errorf("-topology %s: multistate and block automata always run on a torus", cfg.Topology)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if automaton != nil {
+		for _, sink := range cfg.Outputs {
+			switch sink.Kind {
+			case "checkpoint":
+				format, err := parseSinkCheckpointFormat(sink)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(2)
+				}
+				automaton.EnableAutoCheckpoint(sink.Path, sinkEvery(sink), format)
+			case "pattern":
+			default:
+				fmt.Fprintf(os.Stderr, "%s outputs are only written for two-state rules\n", sink.Kind)
+				os.Exit(2)
+			}
+		}
+		automaton.Run(cfg.Generations, *delay)
+		for _, sink := range cfg.Outputs {
+			if sink.Kind == "pattern" {
+				if err := writePatternSink(sink, automaton.Pattern()); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+		}
+		return
 	}
 
 	// Create the Game of Life universe with the specified seed pattern and dimensions
+	rules := gameoflife.ParseRulesFromString(cfg.Rules)
+	if *maxAge > 0 {
+		for i, rule := range rules {
+			rules[i] = gameoflife.AgeLimitRule{Rule: rule, MaxAge: *maxAge}
+		}
+	}
+	game, err := cfg.BuildWith(rules...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *maxAge > 0 {
+		game.EnableAgeTracking()
+	}
+	if *ageColours {
+		game.SetColourMode(gameoflife.ColourByAge)
+	}
+	if *colourScheme != "" {
+		scheme, err := gameoflife.ParseColourScheme(*colourScheme)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		seed, err := gameoflife.ParseColourSeed(*colourSeed)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		game.EnableColours(scheme, seed)
+	}
+
+	closeOutputs, err := openOutputs(game, cfg.Outputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer closeOutputs()
+
+	if *find != "" {
+		pattern, err := gameoflife.ResolvePattern(*find)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		game.HighlightPattern(pattern.Cells, gameoflife.DefaultMatchOptions())
+	}
+
+	if *interactiveMode {
+		game.EnableHistory(*historyKeyframe, *historyBudget)
+		interactive(game)
+	} else {
+		game.Run(cfg.Generations, *delay)
+	}
+
+	if game.ColourScheme() != gameoflife.Monochrome {
+		fmt.Printf("%s populations by colour: %v\n", game.ColourScheme(), game.ColourPopulations())
+	}
+
+	if *printCensus {
+		census := game.Census()
+		fmt.Printf("Census of generation %d (%d objects):\n", game.Generation(), len(census.Objects))
+		for _, label := range census.Labels() {
+			fmt.Printf("%6d  %s\n", census.Counts[label], label)
+		}
+	}
+
+	if err := writeOutputs(game, cfg.Outputs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// openOutputs starts the outputs that follow a run as it goes: checkpoints, statistics and
+// the heat map's exposure. The returned function flushes and closes them.
+func openOutputs(game *gameoflife.GameOfLife, outputs []gameoflife.OutputSink) (func(), error) {
+	var closers []func()
+	closeAll := func() {
+		for _, closer := range slices.Backward(closers) {
+			closer()
+		}
+	}
+	for _, sink := range outputs {
+		switch sink.Kind {
+		case "checkpoint":
+			format, err := parseSinkCheckpointFormat(sink)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+			game.EnableAutoCheckpoint(sink.Path, sinkEvery(sink), format)
+		case "stats":
+			statsFile, err := os.Create(sink.Path)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+			recorder := gameoflife.NewStatsRecorder(statsFile, gameoflife.StatsFormatFromPath(sink.Path))
+			closers = append(closers, func() {
+				recorder.Flush()
+				statsFile.Close()
+			})
+			if err := game.RecordStats(recorder); err != nil {
+				closeAll()
+				return nil, err
+			}
+		case "heatmap":
+			game.EnableAgeTracking()
+		}
+	}
+	return closeAll, nil
+}
+
+// writeOutputs writes the outputs of the last generation: patterns, renders and heat maps.
+func writeOutputs(game *gameoflife.GameOfLife, outputs []gameoflife.OutputSink) error {
+	for _, sink := range outputs {
+		var err error
+		switch sink.Kind {
+		case "pattern":
+			err = writePatternSink(sink, game.Pattern())
+		case "render":
+			err = writeRenderSink(game, sink)
+		case "heatmap":
+			err = writeHeatMap(game, sink.Path, sinkScale(sink))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sinkEvery returns how often a checkpoint output is written, every 10 generations unless
+// it says otherwise.
+func sinkEvery(sink gameoflife.OutputSink) int {
+	if sink.Every > 0 {
+		return sink.Every
+	}
+	return 10
+}
+
+// sinkScale returns the size in pixels of a cell in an image output, 8 unless it says
+// otherwise.
+func sinkScale(sink gameoflife.OutputSink) int {
+	if sink.Scale > 0 {
+		return sink.Scale
+	}
+	return 8
+}
+
+// parseSinkCheckpointFormat returns the format of a checkpoint output, binary by default.
+func parseSinkCheckpointFormat(sink gameoflife.OutputSink) (gameoflife.CheckpointFormat, error) {
+	if sink.Format == "" {
+		return gameoflife.CheckpointBinary, nil
+	}
+	return gameoflife.ParseCheckpointFormat(sink.Format)
+}
+
+// writePatternSink writes a pattern output, in its format or the one implied by its path.
+func writePatternSink(sink gameoflife.OutputSink, pattern *gameoflife.Pattern) error {
+	if sink.Format == "" {
+		return writePatternFile(sink.Path, pattern)
+	}
+	format, err := gameoflife.ParsePatternFormat(sink.Format)
+	if err != nil {
+		return err
+	}
+	return writeFile(sink.Path, func(w io.Writer) error { return gameoflife.WritePattern(w, pattern, format) })
+}
+
+// writeRenderSink draws the universe to a render output, as PNG for a .png path and text
+// otherwise unless it names a format.
+func writeRenderSink(game *gameoflife.GameOfLife, sink gameoflife.OutputSink) error {
+	name := sink.Format
+	if name == "" {
+		name = "text"
+		if strings.EqualFold(filepath.Ext(sink.Path), ".png") {
+			name = "png"
+		}
+	}
+	format, err := gameoflife.ParseRenderFormat(name)
+	if err != nil {
+		return err
+	}
+	return writeFile(sink.Path, func(w io.Writer) error { return game.Render(w, format, sinkScale(sink)) })
+}
+
+// writeHeatMap writes the long-exposure image of the run to path as PNG.
+func writeHeatMap(game *gameoflife.GameOfLife, path string, scale int) error {
+	return writeFile(path, func(w io.Writer) error { return game.WriteHeatMap(w, scale) })
+}
+
+// newStateAutomaton creates a multistate automaton seeded from a pattern file (multistate
+// RLE, or a .txt file of the rule's state symbols), or with the rule's own seed. Elementary
+// rules always get a single row.
+func newStateAutomaton(rule gameoflife.StateRule, rows, cols int, patternPath string) (*gameoflife.StateAutomaton, error) {
+	if _, ok := rule.(gameoflife.ElementaryRule); ok {
+		rows = 1
+	}
+	if patternPath == "" {
+		return gameoflife.NewStateAutomaton(rows, cols, rule, nil)
+	}
+	if strings.EqualFold(filepath.Ext(patternPath), ".txt") {
+		f, err := os.Open(patternPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		cells, err := gameoflife.ReadStateText(f, rule)
+		if err != nil {
+			return nil, err
+		}
+		return gameoflife.NewStateAutomatonFromPattern(rows, cols, rule, statePattern(cells))
+	}
+	pattern, err := gameoflife.LoadPattern(patternPath)
+	if err != nil {
+		return nil, err
+	}
+	return gameoflife.NewStateAutomatonFromPattern(rows, cols, rule, pattern)
+}
+
+// newBlockAutomaton creates a block automaton seeded from a pattern file or a seed pattern.
+func newBlockAutomaton(rule gameoflife.BlockRule, rows, cols int, patternPath string, seed gameoflife.SEED_PATTERN) (*gameoflife.BlockAutomaton, error) {
+	cells := gameoflife.GetSeedGrid(seed, rows, cols)
+	if patternPath != "" {
+		pattern, err := gameoflife.LoadPattern(patternPath)
+		if err != nil {
+			return nil, err
+		}
+		if cells, err = pattern.Place(rows, cols); err != nil {
+			return nil, err
+		}
+	}
+	return gameoflife.NewBlockAutomaton(rows, cols, rule, cells)
+}
+
+// statePattern wraps cells with states in a pattern, so they are centred like pattern files.
+func statePattern(cells map[gameoflife.Cell]int) *gameoflife.Pattern {
+	pattern := &gameoflife.Pattern{Cells: make(map[gameoflife.Cell]struct{}, len(cells)), States: cells}
+	for cell := range cells {
+		pattern.Cells[cell] = struct{}{}
+	}
+	return pattern
+}
+
+// writePatternFile writes a pattern to path in the format implied by its extension, RLE by default.
+func writePatternFile(path string, pattern *gameoflife.Pattern) error {
+	format := gameoflife.DetectPatternFormat(path, nil)
+	if format == gameoflife.FormatUnknown {
+		format = gameoflife.FormatRLE
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gameoflife.WritePattern(f, pattern, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// interactive lets the user travel through time: forward by computing new generations,
+// backward and to any generation within the recorded history.
+func interactive(game *gameoflife.GameOfLife) {
+	in := bufio.NewScanner(os.Stdin)
+	message := ""
+	for {
+		fmt.Print("\033[H\033[2J") // Clear screen before printing next frame
+		oldest, _ := game.History().Oldest()
+		newest, _ := game.History().Newest()
+		fmt.Printf("Generation: %d (history %d..%d)\n", game.Generation(), oldest, newest)
+		game.Display()
+		if message != "" {
+			fmt.Println(message)
+			message = ""
+		}
+		fmt.Print("[enter/n] next  [b] back  [g N] go to generation  [q] quit > ")
+
+		if !in.Scan() {
+			return
+		}
+		fields := strings.Fields(in.Text())
+		command := ""
+		if len(fields) > 0 {
+			command = fields[0]
+		}
+
+		var err error
+		switch command {
+		case "", "n":
+			game.CreateNextGeneration()
+		case "b":
+			err = game.StepBack()
+		case "g":
+			var generation int
+			if len(fields) != 2 {
+				err = fmt.Errorf("usage: g <generation>")
+			} else if generation, err = strconv.Atoi(fields[1]); err == nil {
+				err = game.GoToGeneration(generation)
+			}
+		case "q":
+			return
+		default:
+			err = fmt.Errorf("unknown command %q", command)
+		}
+		if err != nil {
+			message = err.Error()
+		}
+	}
+}
+
+// render draws one generation of a universe as text, terminal colours or a PNG image.
+func render(args []string) {
+	fs := newFlagSet("render")
+	universe := addUniverseFlags(fs, 32, 32)
+	generation := fs.Int("gen", 0, "Generation to render")
+	formatName := fs.String("format", "", "Output format: ansi, text or png (default: png for a .png -out, otherwise text)")
+	outPath := fs.String("out", "", "File to write to (default: standard output)")
+	scale := fs.Int("scale", 8, "Size in pixels of each cell in PNG images")
+	jsonOutput := fs.Bool("json", false, "Print the generation as JSON, with its live cells, instead of drawing it")
+	fs.Parse(args)
+
+	if *formatName == "" {
+		*formatName = "text"
+		if strings.EqualFold(filepath.Ext(*outPath), ".png") {
+			*formatName = "png"
+		}
+	}
+	format, err := gameoflife.ParseRenderFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cfg := universe.mustResolve()
+	if universe.applies("gen") {
+		cfg.Generations = *generation
+	}
+	universe.dump(cfg)
+	game := build(cfg)
+	for game.Generation() < cfg.Generations {
+		game.CreateNextGeneration()
+	}
+
+	if *jsonOutput {
+		printJSON(summarise(game, cfg.Rules, true))
+		return
+	}
+	write := func(w io.Writer) error { return game.Render(w, format, *scale) }
+	if *outPath == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFile(*outPath, write)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// convert reads a pattern (a file, catalogue name or apgcode) and writes it in another
+// format, to a file or the standard output.
+func convert(args []string) {
+	fs := newFlagSet("convert")
+	formatName := fs.String("to", "", "Output format: rle, cells, life105, life106 or mc (default: from the output file's extension, otherwise RLE)")
+	name := fs.String("name", "", "Name to store in the converted pattern")
+	jsonOutput := fs.Bool("json", false, "Print a JSON summary of the conversion; without an output file it includes the converted pattern")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	outPath := fs.Arg(1)
+	format := gameoflife.FormatRLE
+	if *formatName != "" {
+		var err error
+		if format, err = gameoflife.ParsePatternFormat(*formatName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else if detected := gameoflife.DetectPatternFormat(outPath, nil); outPath != "" && detected != gameoflife.FormatUnknown {
+		format = detected
+	}
+
+	var converted strings.Builder
+	var population uint64
+	var patternName, rule string
+	var height, width int
+	if mc, err := gameoflife.LoadMacrocell(fs.Arg(0)); err == nil && format == gameoflife.FormatMacrocell {
+		// macrocell to macrocell keeps the quadtree, so patterns too big to expand convert too
+		patternName, rule = *name, mc.Rule
+		if *name != "" {
+			mc.Comments = append([]string{*name}, mc.Comments...)
+		}
+		if err := gameoflife.WriteMacrocell(&converted, mc); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		population = mc.Tree.Population()
+		_, height, width = mc.Tree.Bounds()
+	} else {
+		pattern, err := gameoflife.ResolvePattern(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if *name != "" {
+			pattern.Name = *name
+		}
+		if err := gameoflife.WritePattern(&converted, pattern, format); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		patternName, rule, population = pattern.Name, pattern.Rule, uint64(len(pattern.Cells))
+		_, height, width = pattern.Bounds()
+	}
+	if outPath != "" {
+		err := writeFile(outPath, func(w io.Writer) error {
+			_, err := io.WriteString(w, converted.String())
+			return err
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if *jsonOutput {
+		summary := struct {
+			Input      string `json:"input"`
+			Output     string `json:"output,omitempty"`
+			Format     string `json:"format"`
+			Name       string `json:"name,omitempty"`
+			Rule       string `json:"rule,omitempty"`
+			Population uint64 `json:"population"`
+			Width      int    `json:"width"`
+			Height     int    `json:"height"`
+			Pattern    string `json:"pattern,omitempty"`
+		}{fs.Arg(0), outPath, format.String(), patternName, rule, population, width, height, ""}
+		if outPath == "" {
+			summary.Pattern = converted.String()
+		}
+		printJSON(summary)
+	} else if outPath == "" {
+		fmt.Print(converted.String())
+	}
+}
+
+// analyze reports the statistics of a generation, how the whole pattern behaves when run
+// on its own, and the objects it is made of.
+func analyze(args []string) {
+	fs := newFlagSet("analyze")
+	universe := addUniverseFlags(fs, 64, 64)
+	generations := fs.Int("gens", 0, "Generations to run before analysing")
+	maxPeriod := fs.Int("max-period", 64, "Longest period looked for when classifying the whole pattern")
+	jsonOutput := fs.Bool("json", false, "Print the analysis as JSON")
+	fs.Parse(args)
+
+	cfg := universe.mustResolve()
+	if universe.applies("gens") {
+		cfg.Generations = *generations
+	}
+	universe.dump(cfg)
+	game := build(cfg)
+	for game.Generation() < cfg.Generations {
+		game.CreateNextGeneration()
+	}
+
+	rules := gameoflife.ParseRulesFromString(cfg.Rules)
+	cells := game.Pattern().Cells
+	census := game.Census()
+	type objectCount struct {
+		Label string `json:"label"`
+		Count int    `json:"count"`
+	}
+	// behaviour is gameoflife.Behaviour with the displacement's fields named for JSON
+	type behaviour struct {
+		Kind         gameoflife.ObjectKind `json:"kind"`
+		Period       int                   `json:"period"`
+		Displacement struct {
+			Row int `json:"row"`
+			Col int `json:"col"`
+		} `json:"displacement"`
+	}
+	analysis := struct {
+		Universe  universeSummary  `json:"universe"`
+		Stats     gameoflife.Stats `json:"stats"`
+		Apgcode   string           `json:"apgcode,omitempty"`
+		Behaviour behaviour        `json:"behaviour"`
+		Objects   []objectCount    `json:"objects"`
+	}{
+		Universe: summarise(game, cfg.Rules, false),
+		Stats:    game.Stats(),
+		Objects:  []objectCount{},
+	}
+	classified := gameoflife.Classify(cells, *maxPeriod, rules...)
+	analysis.Behaviour.Kind, analysis.Behaviour.Period = classified.Kind, classified.Period
+	analysis.Behaviour.Displacement.Row, analysis.Behaviour.Displacement.Col = classified.Displacement.R, classified.Displacement.C
+	if len(cells) > 0 {
+		analysis.Apgcode = gameoflife.Apgcode(cells, rules...)
+	}
+	for _, label := range census.Labels() {
+		analysis.Objects = append(analysis.Objects, objectCount{label, census.Counts[label]})
+	}
+
+	if *jsonOutput {
+		printJSON(analysis)
+		return
+	}
+	stats := analysis.Stats
+	fmt.Printf("Generation %d of a %dx%d %s: population %d, density %.4f, %d components\n",
+		stats.Generation, analysis.Universe.Rows, analysis.Universe.Cols, analysis.Universe.Topology, stats.Population, stats.Density, stats.Components)
+	if stats.Population > 0 {
+		fmt.Printf("Bounding box: rows %d..%d, columns %d..%d\n", stats.MinRow, stats.MaxRow, stats.MinCol, stats.MaxCol)
+		fmt.Printf("Apgcode: %s\n", analysis.Apgcode)
+	}
+	switch behaviour := analysis.Behaviour; behaviour.Kind {
+	case gameoflife.KindSpaceship:
+		fmt.Printf("On its own: spaceship with period %d, moving %d,%d per period\n", behaviour.Period, behaviour.Displacement.Row, behaviour.Displacement.Col)
+	case gameoflife.KindOscillator, gameoflife.KindStillLife:
+		fmt.Printf("On its own: %s with period %d\n", behaviour.Kind, behaviour.Period)
+	case gameoflife.KindExtinct:
+		fmt.Printf("On its own: dies out\n")
+	default:
+		fmt.Printf("On its own: does not repeat within %d generations\n", *maxPeriod)
+	}
+	fmt.Printf("Objects (%d):\n", len(census.Objects))
+	for _, object := range analysis.Objects {
+		fmt.Printf("%6d  %s\n", object.Count, object.Label)
+	}
+}
+
+// bench measures how fast a universe runs: it times a number of generations, several
+// times over, and reports the fastest run.
+func bench(args []string) {
+	fs := newFlagSet("bench")
+	universe := addUniverseFlags(fs, 256, 256)
+	density := fs.Float64("density", 0.35, "Fill the universe at random with this density, drawn from -random-seed; 0 keeps the seed pattern")
+	generations := fs.Int("gens", 200, "Generations per run")
+	runs := fs.Int("runs", 3, "Number of runs; the fastest one is reported")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	fs.Parse(args)
+
+	cfg := universe.mustResolve()
+	if universe.applies("density") {
+		cfg.Density = *density
+	}
+	if universe.applies("gens") {
+		cfg.Generations = *generations
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	universe.dump(cfg)
+
+	var best time.Duration
+	var game *gameoflife.GameOfLife
+	for range max(*runs, 1) {
+		game = build(cfg)
+		start := time.Now()
+		for range cfg.Generations {
+			game.CreateNextGeneration()
+		}
+		if elapsed := time.Since(start); best == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+
+	rows, cols := game.Size()
+	seconds := max(best.Seconds(), 1e-9)
+	result := struct {
+		Universe             universeSummary `json:"universe"`
+		Runs                 int             `json:"runs"`
+		Generations          int             `json:"generations"`
+		Seconds              float64         `json:"seconds"`
+		GenerationsPerSecond float64         `json:"generations_per_second"`
+		CellsPerSecond       float64         `json:"cells_per_second"`
+	}{
+		Universe:             summarise(game, cfg.Rules, false),
+		Runs:                 max(*runs, 1),
+		Generations:          cfg.Generations,
+		Seconds:              best.Seconds(),
+		GenerationsPerSecond: float64(cfg.Generations) / seconds,
+		CellsPerSecond:       float64(cfg.Generations) * float64(rows) * float64(cols) / seconds,
+	}
+	if *jsonOutput {
+		printJSON(result)
+		return
+	}
+	fmt.Printf("%dx%d %s, rules %s: %d generations in %v (%.1f generations/s, %.3g cells/s), best of %d\n",
+		rows, cols, game.Topology(), cfg.Rules, cfg.Generations, best.Round(time.Microsecond), result.GenerationsPerSecond, result.CellsPerSecond, result.Runs)
+}
+
+// resume loads a checkpoint and continues the run exactly where it left off.
+// Unless told otherwise it keeps checkpointing to the file it was resumed from.
+func resume(args []string) {
+	fs := newFlagSet("resume")
+	numberOfRuns := fs.Int("runs", 25, "Number of further generations to execute")
+	checkpointPath := fs.String("checkpoint", "", "File to write checkpoints to (default: the resumed file)")
+	checkpointEvery := fs.Int("checkpoint-every", 10, "Write a checkpoint every N generations, 0 to disable")
+	checkpointFormat := fs.String("checkpoint-format", "", "Checkpoint format (binary, json) (default: the resumed file's)")
+	delay := fs.Duration("delay", 500*time.Millisecond, "Pause between generations")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	game, err := gameoflife.LoadAutomaton(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot resume: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *checkpointPath == "" {
+		*checkpointPath = fs.Arg(0)
+	}
+	format, err := gameoflife.DetectCheckpointFormat(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot resume: %v\n", err)
+		os.Exit(1)
+	}
+	if *checkpointFormat != "" {
+		if format, err = gameoflife.ParseCheckpointFormat(*checkpointFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	game.EnableAutoCheckpoint(*checkpointPath, *checkpointEvery, format)
+
+	game.Run(*numberOfRuns, *delay)
+}
+
+// search runs an apgsearch-style census of random 16x16 soups.
+func search(args []string) {
+	fs := newFlagSet("search")
+	seedPrefix := fs.String("seed-prefix", "k_", "Prefix of the soup seeds; soup N uses seed <prefix>N")
+	soups := fs.Int("soups", 1000, "Total number of soups to search")
+	workers := fs.Int("workers", 0, "Number of soups run in parallel (default: number of CPUs)")
+	maxGenerations := fs.Int("max-gens", 3000, "Generations after which a soup is reported as unstabilised")
+	ruleNames := fs.String("rules", "conway", fmt.Sprintf("Comma-separated rule names or B/S rulestrings (e.g., conway,no-top-left or B36/S23). Available: %v", gameoflife.AvailableRuleNames()))
+	statePath := fs.String("state", "", "Census file, saved after every batch and resumed if it exists")
+	top := fs.Int("top", 30, "Number of census entries to print")
+	jsonOutput := fs.Bool("json", false, "Print the census as JSON")
+	fs.Parse(args)
+
+	census, err := gameoflife.RunSoupSearch(gameoflife.SoupSearchOptions{
+		SeedPrefix:     *seedPrefix,
+		Soups:          *soups,
+		Workers:        *workers,
+		MaxGenerations: *maxGenerations,
+		Rules:          gameoflife.ParseRulesFromString(*ruleNames),
+		StatePath:      *statePath,
+		Progress:       os.Stderr,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	entries := census.Entries()
+	if *top >= 0 && len(entries) > *top {
+		entries = entries[:*top]
+	}
+	if *jsonOutput {
+		printJSON(struct {
+			SeedPrefix    string                        `json:"seed_prefix"`
+			Rules         []string                      `json:"rules"`
+			SoupsSearched int                           `json:"soups_searched"`
+			Entries       []*gameoflife.SoupCensusEntry `json:"entries"`
+			Unstabilised  []string                      `json:"unstabilised,omitempty"`
+		}{census.SeedPrefix, census.Rules, census.SoupsSearched, entries, census.Unstabilised})
+		return
+	}
+
+	fmt.Printf("Census of %d soups with seed prefix %q:\n", census.SoupsSearched, census.SeedPrefix)
+	for _, entry := range entries {
+		fmt.Printf("%8d  %-24s %-24s %v\n", entry.Count, entry.Apgcode, entry.Name, entry.ExampleSeeds)
+	}
+	if len(census.Unstabilised) > 0 {
+		fmt.Printf("%d soups did not stabilise, e.g. %v\n", len(census.Unstabilised), census.Unstabilised[0])
+	}
+}
+
+// predecessor searches for a generation that evolves into the target, or proves that
+// none exists within the search area. It exits with status 3 for a Garden of Eden.
+func predecessor(args []string) {
+	fs := newFlagSet("predecessor")
+	usage := fs.Usage
+	fs.Usage = func() {
+		usage()
+		fmt.Println("\nThe target is a pattern file, catalogue name or apgcode. Exits with status 3 if no predecessor exists.")
+	}
+	ruleNames := fs.String("rules", "conway", fmt.Sprintf("Comma-separated rule names. Available: %v", gameoflife.AvailableRuleNames()))
+	margin := fs.Int("margin", 2, "How far beyond the target's bounding box predecessor cells may lie")
+	rows := fs.Int("rows", 0, "Search on a torus of this many rows instead of the plane (requires -cols)")
+	cols := fs.Int("cols", 0, "Search on a torus of this many columns instead of the plane (requires -rows)")
+	timeout := fs.Duration("timeout", time.Minute, "Give up after this long, 0 for no limit")
+	outPath := fs.String("out", "", "Write the predecessor to this RLE file (default: standard output)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	target, err := gameoflife.ResolvePattern(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	rules := gameoflife.ParseRulesFromString(*ruleNames)
+
+	var result *gameoflife.Pattern
+	if *rows > 0 || *cols > 0 {
+		game, err := gameoflife.CreateUniverseFromPattern(*rows, *cols, target, rules...)
+		if err == nil {
+			if game, err = game.FindPredecessor(*timeout); err == nil {
+				result = game.Pattern()
+			}
+		}
+	} else {
+		var cells map[gameoflife.Cell]struct{}
+		cells, err = gameoflife.FindPredecessor(target.Cells, gameoflife.PredecessorOptions{Rules: rules, Margin: *margin, TimeLimit: *timeout})
+		if err == nil {
+			result = gameoflife.NewPattern(cells)
+		}
+	}
+	switch {
+	case errors.Is(err, gameoflife.ErrNoPredecessor):
+		fmt.Fprintf(os.Stderr, "Garden of Eden: %v\n", err)
+		os.Exit(3)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	result.Name = "Predecessor of " + target.Name
+	if target.Name == "" {
+		result.Name = "Predecessor of " + fs.Arg(0)
+	}
+	out := os.Stdout
+	if *outPath != "" {
+		if out, err = os.Create(*outPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err := gameoflife.WritePattern(out, result, gameoflife.FormatRLE); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := out.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// periodic searches a box for still lifes, oscillators or spaceships of a given period
+// and writes each distinct one to <apgcode>.rle in the output directory.
+func periodic(args []string) {
+	fs := newFlagSet("periodic")
+	ruleNames := fs.String("rules", "conway", fmt.Sprintf("Rule name or rulestring (e.g. B36/S23). Available: %v", gameoflife.AvailableRuleNames()))
+	rows := fs.Int("rows", 8, "Height of the box every phase must fit in")
+	cols := fs.Int("cols", 8, "Width of the box every phase must fit in")
+	period := fs.Int("period", 2, "Period of the patterns to find")
+	velocity := fs.String("velocity", "0,0", "Rows,columns the pattern moves in one period, e.g. 0,2 for c/2 spaceships")
+	symmetry := fs.String("symmetry", "C1", fmt.Sprintf("Symmetry to impose. Available: %v", gameoflife.SearchSymmetries()))
+	maxResults := fs.Int("max", 10, "Stop after this many distinct patterns, 0 for all")
+	timeout := fs.Duration("timeout", time.Minute, "Give up after this long, 0 for no limit")
+	outDir := fs.String("out", "", "Directory to write the patterns to as <apgcode>.rle (default: only list them)")
+	fs.Parse(args)
+
+	var move gameoflife.Cell
+	if _, err := fmt.Sscanf(*velocity, "%d,%d", &move.R, &move.C); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -velocity %q: want rows,columns\n", *velocity)
+		os.Exit(2)
+	}
 	rules := gameoflife.ParseRulesFromString(*ruleNames)
-	game := gameoflife.CreateSeedUniverse(*rows, *cols, seedPattern, rules...)
-	game.Run(*numberOfRuns, 500*time.Millisecond)
+
+	results, err := gameoflife.SearchPeriodic(gameoflife.PeriodicSearchOptions{
+		Rules:      rules,
+		Rows:       *rows,
+		Cols:       *cols,
+		Period:     *period,
+		Velocity:   move,
+		Symmetry:   *symmetry,
+		MaxResults: *maxResults,
+		TimeLimit:  *timeout,
+	})
+	if err != nil && !errors.Is(err, gameoflife.ErrSearchTimeout) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	for _, result := range results {
+		fmt.Printf("%-24s %s, period %d\n", result.Apgcode, result.Behaviour.Kind, result.Behaviour.Period)
+		if *outDir == "" {
+			continue
+		}
+		pattern := gameoflife.NewPattern(result.Cells)
+		pattern.Name = result.Apgcode
+		pattern.Rule = gameoflife.Rulestring(rules)
+		pattern.Comments = []string{fmt.Sprintf("%s with period %d, found in a %dx%d box", result.Behaviour.Kind, result.Behaviour.Period, *rows, *cols)}
+		if err := writePatternFile(filepath.Join(*outDir, result.Apgcode+".rle"), pattern); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v after %d patterns\n", err, len(results))
+	}
+}
+
+// methuselah evolves small starting patterns towards long lifespans and prints a leaderboard.
+func methuselah(args []string) {
+	fs := newFlagSet("methuselah")
+	size := fs.Int("size", 8, "Width and height of the box starting patterns are drawn in")
+	population := fs.Int("population", 50, "Number of patterns in each generation of the search")
+	generations := fs.Int("generations", 20, "Number of generations of the search")
+	tournament := fs.Int("tournament", 3, "Number of patterns competing to become a parent")
+	mutation := fs.Float64("mutation", 0.05, "Chance of flipping each cell of a child")
+	crossover := fs.Float64("crossover", 0.7, "Chance that a child mixes two parents")
+	elite := fs.Int("elite", 2, "Number of best patterns carried over unchanged")
+	maxSteps := fs.Int("max-steps", 5000, "Generations after which a pattern is considered to never stabilise")
+	ruleNames := fs.String("rules", "conway", fmt.Sprintf("Rule names or rulestring. Available: %v", gameoflife.AvailableRuleNames()))
+	seed := fs.Uint64("random-seed", 1, "Random seed; the same seed and flags give the same results")
+	workers := fs.Int("workers", 0, "Number of patterns simulated in parallel (default: number of CPUs)")
+	top := fs.Int("top", 10, "Number of leaderboard entries")
+	outDir := fs.String("out", "", "Directory to write the leaderboard patterns to as rank-N.rle")
+	fs.Parse(args)
+
+	rules := gameoflife.ParseRulesFromString(*ruleNames)
+	best := gameoflife.FindMethuselahs(gameoflife.MethuselahOptions{
+		Size:           *size,
+		Population:     *population,
+		Generations:    *generations,
+		TournamentSize: *tournament,
+		MutationRate:   *mutation,
+		CrossoverRate:  *crossover,
+		Elite:          *elite,
+		MaxSteps:       *maxSteps,
+		Rules:          rules,
+		Seed:           *seed,
+		Workers:        *workers,
+		Leaderboard:    *top,
+		Progress:       os.Stderr,
+	})
+
+	fmt.Printf("%4s %9s %8s %7s %6s  %s\n", "rank", "fitness", "lifespan", "initial", "final", "pattern")
+	for i, m := range best {
+		pattern := gameoflife.NewPattern(m.Cells)
+		pattern.Rule = gameoflife.Rulestring(rules)
+		var rle strings.Builder
+		gameoflife.WritePattern(&rle, pattern, gameoflife.FormatRLE)
+		lines := strings.Split(strings.TrimSpace(rle.String()), "\n")
+		fmt.Printf("%4d %9.1f %8d %7d %6d  %s\n", i+1, m.Fitness, m.Lifespan, len(m.Cells), m.FinalPopulation, strings.Join(lines[1:], ""))
+
+		if *outDir != "" {
+			pattern.Name = fmt.Sprintf("Methuselah candidate %d", i+1)
+			pattern.Comments = []string{fmt.Sprintf("stabilises after %d generations with population %d", m.Lifespan, m.FinalPopulation)}
+			if err := writePatternFile(filepath.Join(*outDir, fmt.Sprintf("rank-%d.rle", i+1)), pattern); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+// exploreRules runs soups under many Life-like rules and reports how each behaves.
+func exploreRules(args []string) {
+	fs := newFlagSet("explore-rules")
+	ruleList := fs.String("rules", "", "Comma-separated rulestrings to examine (default: random rules)")
+	samples := fs.Int("samples", 100, "Number of random rules to examine")
+	seed := fs.Uint64("random-seed", 1, "Seed for drawing random rules")
+	soups := fs.Int("soups", 4, "Number of soups run under each rule")
+	size := fs.Int("size", 64, "Side of the torus soups are run in")
+	generations := fs.Int("gens", 500, "Longest a soup is run")
+	workers := fs.Int("workers", 0, "Number of rules examined in parallel (default: number of CPUs)")
+	sortBy := fs.String("sort", "rule", "Column to sort by: rule, class, growth, activity, entropy, stabilisation_time")
+	descending := fs.Bool("desc", false, "Sort in descending order")
+	outPath := fs.String("out", "", "Write the report to this file (.csv, or .jsonl for JSON Lines) instead of a table")
+	fs.Parse(args)
+
+	var rules []gameoflife.LifeLikeRule
+	if *ruleList != "" {
+		for _, rulestring := range strings.Split(*ruleList, ",") {
+			rule, err := gameoflife.ParseLifeLikeRule(rulestring)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	reports := gameoflife.ExploreRules(gameoflife.RuleExplorerOptions{
+		Rules:        rules,
+		Samples:      *samples,
+		Seed:         *seed,
+		SoupsPerRule: *soups,
+		UniverseSize: *size,
+		Generations:  *generations,
+		Workers:      *workers,
+		Progress:     os.Stderr,
+	})
+	if err := gameoflife.SortRuleReports(reports, *sortBy, *descending); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := gameoflife.WriteRuleReports(f, reports, gameoflife.StatsFormatFromPath(*outPath)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := f.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("%-20s %-10s %8s %8s %8s %10s\n", "rule", "class", "growth", "activity", "entropy", "stabilised")
+	for _, r := range reports {
+		fmt.Printf("%-20s %-10s %8.3f %8.4f %8.3f %10.1f\n", r.Rule, r.Class, r.Growth, r.Activity, r.Entropy, r.StabilisationTime)
+	}
+}
+
+// life3d runs three-dimensional Life from a random soup, showing it slice by slice, and
+// optionally exports the last generation for voxel viewers.
+func life3d(args []string) {
+	fs := newFlagSet("life3d")
+	size := fs.String("size", "16,16,16", "Size of the universe as x,y,z")
+	ruleString := fs.String("rule", "4555", "3D rule in Bays' notation (4555, 5766) or as B5/S45")
+	soup := fs.Int("soup", 6, "Side of the random cube in the middle of the universe")
+	density := fs.Float64("density", 0.3, "Fraction of the soup's cells that are alive")
+	seed := fs.Uint64("random-seed", 1, "Random seed of the soup")
+	generations := fs.Int("gens", 20, "Number of generations to run")
+	display := fs.Bool("display", true, "Print every generation slice by slice")
+	delay := fs.Duration("delay", 500*time.Millisecond, "Pause between displayed generations")
+	plyPath := fs.String("ply", "", "Write the last generation to this file as an ASCII PLY point cloud")
+	voxPath := fs.String("vox", "", "Write the last generation to this file in MagicaVoxel's VOX format")
+	fs.Parse(args)
+
+	var dims [3]int
+	fields := strings.Split(*size, ",")
+	if len(fields) != 3 {
+		fmt.Fprintf(os.Stderr, "invalid -size %q, want x,y,z\n", *size)
+		os.Exit(2)
+	}
+	for i := range dims {
+		var err error
+		if dims[i], err = strconv.Atoi(strings.TrimSpace(fields[i])); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -size %q: %v\n", *size, err)
+			os.Exit(2)
+		}
+	}
+	if *generations < 0 {
+		fmt.Fprintf(os.Stderr, "invalid -gens %d, want 0 or more\n", *generations)
+		os.Exit(2)
+	}
+	rule, err := gameoflife.ParseRule3D(*ruleString)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	universe, err := gameoflife.NewLife3D(dims[0], dims[1], dims[2], rule)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	universe.Soup(*soup, *density, *seed)
+
+	for {
+		if *display {
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Generation: %d, population %d\n", universe.Generation(), universe.Population())
+			universe.Display()
+		}
+		if universe.Generation() >= *generations {
+			break
+		}
+		universe.Step()
+		if *display {
+			time.Sleep(*delay)
+		}
+	}
+	fmt.Printf("Rule %s: population %d after %d generations\n", rule, universe.Population(), universe.Generation())
+
+	for _, export := range []struct {
+		path  string
+		write func(io.Writer) error
+	}{{*plyPath, universe.WritePLY}, {*voxPath, universe.WriteVOX}} {
+		if export.path == "" {
+			continue
+		}
+		if err := writeFile(export.path, export.write); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// writeFile creates path and fills it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// servePage is the page served at /: it shows the universe as an image and steps it
+// through the JSON API.
+const servePage = `<!DOCTYPE html>
+<html>
+<head><title>Game of Life</title></head>
+<body style="background:#222;color:#ddd;font-family:sans-serif">
+<p id="status"></p>
+<p><img id="universe" src="universe.png" style="image-rendering:pixelated"></p>
+<p>
+<button onclick="step(1)">Step</button>
+<button onclick="step(10)">Step 10</button>
+<button onclick="play()">Play/Pause</button>
+<button onclick="post('api/reset')">Reset</button>
+</p>
+<script>
+let timer = null;
+function show(state) {
+	document.getElementById("status").textContent =
+		"Generation " + state.generation + ", population " + state.population + ", " + state.rows + "x" + state.cols + " " + state.topology;
+	document.getElementById("universe").src = "universe.png?generation=" + state.generation;
+}
+function post(path) { return fetch(path, {method: "POST"}).then(r => r.json()).then(show); }
+function step(n) { return post("api/step?n=" + n); }
+function play() {
+	if (timer) { clearInterval(timer); timer = null; } else { timer = setInterval(() => step(1), 200); }
+}
+fetch("api/state").then(r => r.json()).then(show);
+</script>
+</body>
+</html>
+`
+
+// serve runs a universe behind an HTTP server: a page at / to watch and step it, the
+// current generation as a PNG image at /universe.png, and a JSON API:
+//
+//	GET  /api/state        the current generation and its live cells
+//	POST /api/step?n=N     advance N generations (default 1), then as /api/state
+//	POST /api/reset        go back to the seed, then as /api/state
+func serve(args []string) {
+	fs := newFlagSet("serve")
+	universe := addUniverseFlags(fs, 64, 64)
+	addr := fs.String("addr", "localhost:8080", "Address to listen on")
+	scale := fs.Int("scale", 8, "Size in pixels of each cell in the image")
+	maxSteps := fs.Int("max-steps", 10000, "Most generations a single step request may ask for")
+	fs.Parse(args)
+
+	cfg := universe.mustResolve()
+	universe.dump(cfg)
+	game := build(cfg)
+
+	// requests are served concurrently, but a universe is not safe for concurrent use
+	var mu sync.Mutex
+	writeState := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summarise(game, cfg.Rules, true))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, servePage)
+	})
+	mux.HandleFunc("GET /universe.png", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		game.WriteImage(w, *scale)
+	})
+	mux.HandleFunc("GET /api/state", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeState(w)
+	})
+	mux.HandleFunc("POST /api/step", func(w http.ResponseWriter, r *http.Request) {
+		steps := 1
+		if n := r.URL.Query().Get("n"); n != "" {
+			var err error
+			if steps, err = strconv.Atoi(n); err != nil || steps < 0 || steps > *maxSteps {
+				http.Error(w, fmt.Sprintf("n must be a number of generations from 0 to %d", *maxSteps), http.StatusBadRequest)
+				return
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for range steps {
+			game.CreateNextGeneration()
+		}
+		writeState(w)
+	})
+	mux.HandleFunc("POST /api/reset", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		seed, err := cfg.Build()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		game = seed
+		writeState(w)
+	})
+
+	fmt.Fprintf(os.Stderr, "Serving rules %s on http://%s/\n", cfg.Rules, *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}